@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/onedusk/jot/internal/compiler"
 	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/i18n"
+	"github.com/onedusk/jot/internal/renderer"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/toc"
+	"github.com/onedusk/jot/internal/tokenizer"
+	"github.com/onedusk/jot/internal/versioning"
 )
 
 // buildCmd represents the command for building the documentation.
@@ -29,6 +34,8 @@ func init() {
 	buildCmd.Flags().StringP("output", "o", "", "output directory (overrides config)")
 	buildCmd.Flags().BoolP("clean", "c", false, "clean output directory before building")
 	buildCmd.Flags().Bool("skip-llms-txt", false, "skip generation of llms.txt and llms-full.txt files")
+	buildCmd.Flags().Bool("llms-txt-sections", false, "also write llms.txt and llms-full.txt per top-level section, linked from the root llms.txt")
+	buildCmd.Flags().String("base-url", "", "base URL for llms.txt links (overrides llms_txt.base_url)")
 }
 
 // runBuild executes the main build logic for the documentation.
@@ -50,10 +57,64 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	// Build each locale into its own directory when locales are configured
+	locales, defaultLocale, err := loadLocales(config)
+	if err != nil {
+		return err
+	}
+	if len(locales) > 0 {
+		if viper.IsSet("versioning.versions") {
+			return fmt.Errorf("i18n.locales cannot be combined with versioning.versions")
+		}
+		if err := buildLocales(cmd, config, locales, defaultLocale); err != nil {
+			return err
+		}
+		elapsed := time.Since(start)
+		fmt.Printf(" Build completed in %.2fs\n", elapsed.Seconds())
+		return nil
+	}
+
+	// Build each version into its own directory when versions are configured
+	// or detected
+	if config.Versioning {
+		versions, latest, err := loadVersions(config)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			if err := buildVersions(cmd, config, versions, latest); err != nil {
+				return err
+			}
+			elapsed := time.Since(start)
+			fmt.Printf(" Build completed in %.2fs\n", elapsed.Seconds())
+			return nil
+		}
+	}
+
+	allDocs, err := scanDocuments(config.InputPaths, config.IgnorePatterns, config.GitHistory)
+	if err != nil {
+		return err
+	}
+
+	if err := buildSite(cmd, config, allDocs, config.OutputPath, nil, nil); err != nil {
+		return err
+	}
+
+	// Summary
+	elapsed := time.Since(start)
+	fmt.Printf(" Build completed in %.2fs\n", elapsed.Seconds())
+
+	return nil
+}
+
+// scanDocuments scans the input paths for markdown files, printing progress.
+// With gitHistory, documents carry their git history (see
+// scanner.Scanner.SetGitHistory).
+func scanDocuments(inputPaths, ignorePatterns []string, gitHistory bool) ([]scanner.Document, error) {
 	fmt.Println(" Scanning for markdown files...")
 
 	var allDocs []scanner.Document
-	for _, inputPath := range config.InputPaths {
+	for _, inputPath := range inputPaths {
 		fmt.Printf("  Scanning %s...\n", inputPath)
 
 		// Check if path exists
@@ -62,31 +123,57 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		s, err := scanner.NewScanner(inputPath, config.IgnorePatterns)
+		s, err := scanner.NewScanner(inputPath, ignorePatterns)
 		if err != nil {
-			return fmt.Errorf("failed to create scanner: %w", err)
+			return nil, fmt.Errorf("failed to create scanner: %w", err)
 		}
+		s.SetGitHistory(gitHistory)
 
 		// Scan documents
 		docs, err := s.Scan()
 		if err != nil {
-			return fmt.Errorf("failed to scan %s: %w", inputPath, err)
+			return nil, fmt.Errorf("failed to scan %s: %w", inputPath, err)
 		}
 
 		allDocs = append(allDocs, docs...)
 	}
 
 	if len(allDocs) == 0 {
-		return fmt.Errorf("no markdown files found")
+		return nil, fmt.Errorf("no markdown files found")
 	}
 
 	fmt.Printf("  Found %d markdown files\n\n", len(allDocs))
 
+	return allDocs, nil
+}
+
+// buildSite writes the table of contents, HTML pages, search index, markdown
+// mirror and llms.txt files for documents into outputPath. versionInfo places
+// the site in a version directory of a versioned site and localeInfo in a
+// locale directory of a multi-language site; nil for both builds a plain
+// site.
+func buildSite(cmd *cobra.Command, config BuildConfig, allDocs []scanner.Document, outputPath string, versionInfo *renderer.VersionInfo, localeInfo *renderer.LocaleInfo) error {
+	// Label file names in progress output with the version or locale directory
+	dir := ""
+	if versionInfo != nil {
+		dir = versionInfo.Dir
+	} else if localeInfo != nil {
+		dir = localeInfo.Dir
+	}
+	label := ""
+	if dir != "" {
+		label = dir + "/"
+	}
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
 	// Generate table of contents
 	fmt.Println(" Generating table of contents...")
 	tocBuilder := toc.NewBuilder()
 	tableOfContents := tocBuilder.Build(allDocs)
-	tocPath := filepath.Join(config.OutputPath, "toc.xml")
+	tocPath := filepath.Join(outputPath, "toc.xml")
 	if err := os.WriteFile(tocPath, []byte(tableOfContents.ToXML()), 0644); err != nil {
 		return fmt.Errorf("failed to write TOC: %w", err)
 	}
@@ -94,22 +181,34 @@ func runBuild(cmd *cobra.Command, args []string) error {
 
 	// Compile to HTML
 	fmt.Println(" Compiling to HTML...")
-	comp := compiler.NewCompiler(config.OutputPath)
+	comp := compiler.NewCompiler(outputPath)
+	comp.SetMarkdownMirror(config.MarkdownMirror)
+	comp.SetLanguage(config.Language)
+	comp.SetVersions(versionInfo)
+	comp.SetLocales(localeInfo)
+	comp.SetEditURL(config.EditURL)
 	if err := comp.Compile(allDocs, tableOfContents); err != nil {
 		return fmt.Errorf("failed to compile documents: %w", err)
 	}
 	fmt.Printf("  Generated %d HTML files\n", len(allDocs))
 	fmt.Printf("  Created assets/styles.css\n\n")
 
+	// Write a markdown twin next to every HTML page
+	if config.MarkdownMirror {
+		fmt.Println(" Writing markdown mirror...")
+		mirror := compiler.NewMarkdownCompiler(outputPath)
+		if err := mirror.Compile(allDocs, tableOfContents); err != nil {
+			return fmt.Errorf("failed to write markdown mirror: %w", err)
+		}
+		fmt.Printf("  Wrote %d markdown files and all-docs.md\n\n", len(allDocs))
+	}
+
 	// Generate llms.txt and llms-full.txt
 	if config.GenerateLLMSTxt {
 		fmt.Println(" Generating llms.txt...")
 
 		// Create project config from viper settings
-		projectConfig := export.ProjectConfig{
-			Name:        viper.GetString("project.name"),
-			Description: viper.GetString("project.description"),
-		}
+		projectConfig := loadProjectConfig(cmd, config)
 
 		// Set defaults if not configured
 		if projectConfig.Name == "" {
@@ -118,43 +217,346 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		if projectConfig.Description == "" {
 			projectConfig.Description = "Project documentation"
 		}
+		if versionInfo != nil {
+			for _, version := range versionInfo.Versions {
+				if version.Name == versionInfo.Current {
+					projectConfig.Name += " " + version.Label
+				}
+			}
+		}
+		if dir != "" && projectConfig.BaseURL != "" {
+			projectConfig.BaseURL = strings.TrimSuffix(projectConfig.BaseURL, "/") + "/" + dir
+		}
+
+		// Token budget for llms-full.txt, shared by all sections
+		var budgetTokenizer tokenizer.Tokenizer
+		maxTokens := viper.GetInt("llms_txt.max_tokens")
+		if maxTokens > 0 {
+			var err error
+			budgetTokenizer, err = tokenizer.New(exportEncoding(cmd))
+			if err != nil {
+				return err
+			}
+		}
+		newExporter := func(root string) *export.LLMSTxtExporter {
+			exporter := export.NewLLMSTxtExporter()
+			exporter.SetTOC(tableOfContents)
+			exporter.SetRoot(root)
+			if maxTokens > 0 {
+				exporter.SetMaxTokens(maxTokens, budgetTokenizer)
+			}
+			return exporter
+		}
+
+		// Generate llms.txt and llms-full.txt, linking to per-section files
+		// when sections are split out
+		exporter := newExporter("")
+		exporter.SetSplitSections(config.LLMSTxtSections)
+		writeLLMSTxtFiles(exporter, allDocs, projectConfig, outputPath, label)
+
+		if config.LLMSTxtSections {
+			_, sections := exporter.Sections(allDocs)
+			for _, section := range sections {
+				sectionConfig := projectConfig
+				sectionConfig.Name = projectConfig.Name + ": " + section.Title
+				writeLLMSTxtFiles(newExporter(section.Dir), section.Documents, sectionConfig, filepath.Join(outputPath, section.Dir), label+section.Dir+"/")
+			}
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
 
-		exporter := export.NewLLMSTxtExporter()
+// loadVersions returns the versions configured under versioning.versions,
+// or else, with versioning.detect, the version directories detected in the
+// input paths, and the latest version (versioning.latest, by default the
+// first version). No versions means the site is built unversioned.
+// Directories such as docs/2024 look like versions too, so without
+// versioning.detect they are only reported and built as ordinary sections.
+func loadVersions(config BuildConfig) ([]versioning.Version, versioning.Version, error) {
+	var versions []versioning.Version
+	if err := viper.UnmarshalKey("versioning.versions", &versions); err != nil {
+		return nil, versioning.Version{}, fmt.Errorf("invalid versioning.versions: %w", err)
+	}
 
-		// Generate llms.txt
-		llmsTxt, err := exporter.ToLLMSTxt(allDocs, projectConfig)
+	if len(versions) == 0 {
+		detected, err := versioning.Detect(config.InputPaths)
 		if err != nil {
-			fmt.Printf("  Warning: failed to generate llms.txt: %v\n", err)
-		} else {
-			llmsTxtPath := filepath.Join(config.OutputPath, "llms.txt")
-			if err := os.WriteFile(llmsTxtPath, []byte(llmsTxt), 0644); err != nil {
-				fmt.Printf("  Warning: failed to write llms.txt: %v\n", err)
-			} else {
-				llmsTxtSize := len(llmsTxt)
-				fmt.Printf("  Created llms.txt (%s)\n", humanizeBytes(llmsTxtSize))
+			return nil, versioning.Version{}, err
+		}
+		if viper.GetBool("versioning.detect") {
+			versions = detected
+		} else if len(detected) > 0 {
+			names := make([]string, len(detected))
+			for i, version := range detected {
+				names[i] = version.Name
 			}
+			fmt.Printf("  Warning: %s look like version directories but are built as ordinary sections; set versioning.detect: true to build them as versions\n", strings.Join(names, ", "))
 		}
+	}
+	if len(versions) == 0 {
+		return nil, versioning.Version{}, nil
+	}
 
-		// Generate llms-full.txt
-		llmsFullTxt, err := exporter.ToLLMSFullTxt(allDocs, projectConfig)
-		if err != nil {
-			fmt.Printf("  Warning: failed to generate llms-full.txt: %v\n", err)
-		} else {
-			llmsFullTxtPath := filepath.Join(config.OutputPath, "llms-full.txt")
-			if err := os.WriteFile(llmsFullTxtPath, []byte(llmsFullTxt), 0644); err != nil {
-				fmt.Printf("  Warning: failed to write llms-full.txt: %v\n", err)
-			} else {
-				llmsFullTxtSize := len(llmsFullTxt)
-				fmt.Printf("  Created llms-full.txt (%s)\n", humanizeBytes(llmsFullTxtSize))
+	// Versions read from git default to the first input path
+	for i := range versions {
+		if versions[i].Ref != "" && versions[i].Path == "" && len(config.InputPaths) > 0 {
+			versions[i].Path = config.InputPaths[0]
+		}
+	}
+	if err := versioning.Validate(versions); err != nil {
+		return nil, versioning.Version{}, fmt.Errorf("invalid versioning configuration: %w", err)
+	}
+
+	latest, err := versioning.Latest(versions, viper.GetString("versioning.latest"))
+	if err != nil {
+		return nil, versioning.Version{}, err
+	}
+	return versions, latest, nil
+}
+
+// buildVersions builds every version into its own directory of the output,
+// and the latest version also into latest/. Each version gets its own search
+// index and llms.txt files; the site root gets an index.html redirecting to
+// latest/, a versions.json and an llms.txt linking to each version's.
+func buildVersions(cmd *cobra.Command, config BuildConfig, versions []versioning.Version, latest versioning.Version) error {
+	// Versions read from git are extracted into a temporary directory
+	checkoutDir, err := os.MkdirTemp("", "jot-versions-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(checkoutDir)
+
+	documents := make(map[string][]scanner.Document, len(versions))
+	siteVersions := make([]renderer.SiteVersion, 0, len(versions))
+	for _, version := range versions {
+		inputPath := version.Path
+		if version.Ref != "" {
+			fmt.Printf(" Reading %s from %s...\n", version.Path, version.Ref)
+			inputPath, err = versioning.Checkout(version.Ref, version.Path, filepath.Join(checkoutDir, version.Name))
+			if err != nil {
+				return err
 			}
 		}
 
-		fmt.Println()
+		// Files extracted from a ref have no history of their own
+		docs, err := scanDocuments([]string{inputPath}, config.IgnorePatterns, config.GitHistory && version.Ref == "")
+		if err != nil {
+			return fmt.Errorf("failed to scan version %s: %w", version.Name, err)
+		}
+		documents[version.Name] = docs
+
+		// The latest version is offered under latest/, its stable URL
+		siteVersion := renderer.SiteVersion{Name: version.Name, Label: version.DisplayName(), Dir: version.Name, Pages: make(map[string]bool, len(docs))}
+		if version.Name == latest.Name {
+			siteVersion.Dir = versioning.LatestDir
+		}
+		for _, doc := range docs {
+			siteVersion.Pages[filepath.ToSlash(doc.RelativePath)] = true
+		}
+		siteVersions = append(siteVersions, siteVersion)
 	}
 
-	// Summary
-	elapsed := time.Since(start)
-	fmt.Printf(" Build completed in %.2fs\n", elapsed.Seconds())
+	// The latest version is built twice, under latest/ and under its name
+	build := func(dir, name string) error {
+		fmt.Printf(" Building version %s into %s/...\n\n", name, dir)
+		versionInfo := &renderer.VersionInfo{Dir: dir, Current: name, Latest: latest.Name, Versions: siteVersions}
+		if err := buildSite(cmd, config, documents[name], filepath.Join(config.OutputPath, dir), versionInfo, nil); err != nil {
+			return fmt.Errorf("failed to build version %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := build(versioning.LatestDir, latest.Name); err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err := build(version.Name, version.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := versioning.WriteIndex(config.OutputPath, versions, latest.Name); err != nil {
+		return err
+	}
+	fmt.Printf(" Created index.html and versions.json for %d versions\n", len(versions))
+
+	if config.GenerateLLMSTxt {
+		writeSiteIndexLLMSTxt(config.OutputPath, versionsLLMSTxt(loadProjectConfig(cmd, config), versions, latest))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// llmsTxtEntry is a link from the root llms.txt of a site split into
+// directories to the llms.txt of one directory.
+type llmsTxtEntry struct {
+	Title       string
+	Dir         string
+	Description string
+}
+
+// siteIndexLLMSTxt returns the root llms.txt of a site split into version or
+// locale directories, listing their llms.txt files under section.
+func siteIndexLLMSTxt(projectConfig export.ProjectConfig, section string, entries []llmsTxtEntry) string {
+	if projectConfig.Name == "" {
+		projectConfig.Name = "Documentation"
+	}
+	if projectConfig.Description == "" {
+		projectConfig.Description = "Project documentation"
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n> %s\n\n## %s\n\n", projectConfig.Name, projectConfig.Description, section)
+	for _, entry := range entries {
+		url := entry.Dir + "/llms.txt"
+		if projectConfig.BaseURL != "" {
+			url = strings.TrimSuffix(projectConfig.BaseURL, "/") + "/" + url
+		}
+		fmt.Fprintf(&builder, "- [%s](%s): %s\n", entry.Title, url, entry.Description)
+	}
+	return builder.String()
+}
+
+// versionsLLMSTxt returns the root llms.txt of a versioned site, which links
+// to the llms.txt of each version, the latest one first.
+func versionsLLMSTxt(projectConfig export.ProjectConfig, versions []versioning.Version, latest versioning.Version) string {
+	entries := []llmsTxtEntry{{Title: latest.DisplayName() + " (latest)", Dir: versioning.LatestDir, Description: "Documentation for the latest version"}}
+	for _, version := range versions {
+		if version.Name != latest.Name {
+			entries = append(entries, llmsTxtEntry{Title: version.DisplayName(), Dir: version.Name, Description: "Documentation for version " + version.DisplayName()})
+		}
+	}
+	return siteIndexLLMSTxt(projectConfig, "Versions", entries)
+}
+
+// writeSiteIndexLLMSTxt writes the root llms.txt of a site split into
+// directories, reporting failures as warnings.
+func writeSiteIndexLLMSTxt(outputPath, content string) {
+	llmsTxtPath := filepath.Join(outputPath, "llms.txt")
+	if err := os.WriteFile(llmsTxtPath, []byte(content), 0644); err != nil {
+		fmt.Printf("  Warning: failed to write llms.txt: %v\n", err)
+	} else {
+		fmt.Println(" Created llms.txt")
+	}
+}
+
+// loadLocales returns the locales configured under i18n.locales and the
+// default locale (i18n.default, by default the first locale). No locales
+// means a single-language site.
+func loadLocales(config BuildConfig) ([]i18n.Locale, i18n.Locale, error) {
+	var locales []i18n.Locale
+	if err := viper.UnmarshalKey("i18n.locales", &locales); err != nil {
+		return nil, i18n.Locale{}, fmt.Errorf("invalid i18n.locales: %w", err)
+	}
+	if len(locales) == 0 {
+		return nil, i18n.Locale{}, nil
+	}
+
+	defaultCode := viper.GetString("i18n.default")
+	if defaultCode == "" {
+		defaultCode = locales[0].Code
+	}
+	if err := i18n.Validate(locales, defaultCode); err != nil {
+		return nil, i18n.Locale{}, fmt.Errorf("invalid i18n configuration: %w", err)
+	}
+	for _, locale := range locales {
+		if locale.Code == defaultCode {
+			return locales, locale, nil
+		}
+	}
+	return locales, i18n.Locale{}, nil
+}
+
+// buildLocales builds every locale into its own directory of the output.
+// Locales with their own input paths are scanned from them; the others share
+// the input paths, where page.<code>.md is the translation of page.md. Pages
+// without a translation fall back to the default locale. The site root gets
+// an index.html redirecting to the visitor's language, a locales.json and an
+// llms.txt linking to each locale's.
+func buildLocales(cmd *cobra.Command, config BuildConfig, locales []i18n.Locale, defaultLocale i18n.Locale) error {
+	documents := make(map[string][]scanner.Document, len(locales))
+
+	// Split the shared input paths by filename suffix
+	var suffixCodes []string
+	for _, locale := range locales {
+		if len(locale.Paths) == 0 {
+			suffixCodes = append(suffixCodes, locale.Code)
+		}
+	}
+	if len(suffixCodes) > 0 {
+		docs, err := scanDocuments(config.InputPaths, config.IgnorePatterns, config.GitHistory)
+		if err != nil {
+			return err
+		}
+		// Files without a suffix belong to the default locale, or to no
+		// locale when it has its own input paths
+		byLocale, err := i18n.SplitDocuments(docs, suffixCodes, defaultLocale.Code)
+		if err != nil {
+			return fmt.Errorf("failed to split documents by locale: %w", err)
+		}
+		for _, code := range suffixCodes {
+			documents[code] = byLocale[code]
+		}
+	}
+
+	for _, locale := range locales {
+		if len(locale.Paths) == 0 {
+			continue
+		}
+		docs, err := scanDocuments(locale.Paths, config.IgnorePatterns, config.GitHistory)
+		if err != nil {
+			return fmt.Errorf("failed to scan locale %s: %w", locale.Code, err)
+		}
+		documents[locale.Code] = docs
+	}
+
+	// Complete every locale with the default locale's untranslated pages
+	fallbacks := make(map[string]map[string]bool, len(locales))
+	siteLocales := make([]renderer.SiteLocale, 0, len(locales))
+	for _, locale := range locales {
+		if locale.Code != defaultLocale.Code {
+			documents[locale.Code], fallbacks[locale.Code] = i18n.WithFallbacks(documents[locale.Code], documents[defaultLocale.Code])
+		}
+		if len(documents[locale.Code]) == 0 {
+			return fmt.Errorf("no markdown files found for locale %s", locale.Code)
+		}
+
+		siteLocale := renderer.SiteLocale{Code: locale.Code, Label: locale.DisplayName(), Dir: locale.Code, Pages: make(map[string]bool)}
+		for _, doc := range documents[locale.Code] {
+			siteLocale.Pages[filepath.ToSlash(doc.RelativePath)] = true
+		}
+		siteLocales = append(siteLocales, siteLocale)
+	}
+
+	var entries []llmsTxtEntry
+	for _, locale := range locales {
+		fmt.Printf(" Building locale %s into %s/ (%d untranslated pages)...\n\n", locale.Code, locale.Code, len(fallbacks[locale.Code]))
+		localeInfo := &renderer.LocaleInfo{
+			Dir:       locale.Code,
+			Current:   locale.Code,
+			Default:   defaultLocale.Code,
+			Locales:   siteLocales,
+			Fallbacks: fallbacks[locale.Code],
+			Notice:    locale.UntranslatedNotice(),
+		}
+		if err := buildSite(cmd, config, documents[locale.Code], filepath.Join(config.OutputPath, locale.Code), nil, localeInfo); err != nil {
+			return fmt.Errorf("failed to build locale %s: %w", locale.Code, err)
+		}
+		entries = append(entries, llmsTxtEntry{Title: locale.DisplayName(), Dir: locale.Code, Description: "Documentation in " + locale.DisplayName()})
+	}
+
+	if err := i18n.WriteIndex(config.OutputPath, locales, defaultLocale.Code); err != nil {
+		return err
+	}
+	fmt.Printf(" Created index.html and locales.json for %d locales\n", len(locales))
+
+	if config.GenerateLLMSTxt {
+		writeSiteIndexLLMSTxt(config.OutputPath, siteIndexLLMSTxt(loadProjectConfig(cmd, config), "Languages", entries))
+	}
+	fmt.Println()
 
 	return nil
 }
@@ -167,6 +569,12 @@ type BuildConfig struct {
 	IgnorePatterns     []string
 	Clean              bool
 	GenerateLLMSTxt    bool
+	LLMSTxtSections    bool
+	MarkdownMirror     bool
+	Versioning         bool
+	GitHistory         bool   // Read last updated, authors and commit of documents from git
+	EditURL            string // Template of "Edit this page" links, with {path}
+	Language           string // Language of the pages (i18n.default), for <html lang>
 	ProjectName        string
 	ProjectDescription string
 }
@@ -180,6 +588,12 @@ func loadBuildConfig(cmd *cobra.Command) BuildConfig {
 		IgnorePatterns:     viper.GetStringSlice("input.ignore"),
 		Clean:              viper.GetBool("output.clean"),
 		GenerateLLMSTxt:    true, // Default to true
+		LLMSTxtSections:    viper.GetBool("llms_txt.sections"),
+		MarkdownMirror:     viper.GetBool("features.markdown_mirror"),
+		Versioning:         viper.GetBool("features.versioning"),
+		GitHistory:         viper.GetBool("git.history"),
+		EditURL:            viper.GetString("git.edit_url"),
+		Language:           viper.GetString("i18n.default"),
 		ProjectName:        viper.GetString("project.name"),
 		ProjectDescription: viper.GetString("project.description"),
 	}
@@ -199,6 +613,9 @@ func loadBuildConfig(cmd *cobra.Command) BuildConfig {
 	if skipLLMSTxt, _ := cmd.Flags().GetBool("skip-llms-txt"); skipLLMSTxt {
 		config.GenerateLLMSTxt = false
 	}
+	if sections, _ := cmd.Flags().GetBool("llms-txt-sections"); sections {
+		config.LLMSTxtSections = true
+	}
 
 	// Defaults
 	if len(config.InputPaths) == 0 {
@@ -207,10 +624,68 @@ func loadBuildConfig(cmd *cobra.Command) BuildConfig {
 	if config.OutputPath == "" {
 		config.OutputPath = "./dist"
 	}
+	if config.Language == "" {
+		config.Language = "en"
+	}
 
 	return config
 }
 
+// writeLLMSTxtFiles writes llms.txt and llms-full.txt for documents into
+// outputDir. Failures are reported as warnings; label prefixes the file names
+// in progress output.
+func writeLLMSTxtFiles(exporter *export.LLMSTxtExporter, documents []scanner.Document, projectConfig export.ProjectConfig, outputDir, label string) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		fmt.Printf("  Warning: failed to create %s: %v\n", outputDir, err)
+		return
+	}
+
+	// Generate llms.txt
+	llmsTxt, err := exporter.ToLLMSTxt(documents, projectConfig)
+	if err != nil {
+		fmt.Printf("  Warning: failed to generate %sllms.txt: %v\n", label, err)
+	} else {
+		llmsTxtPath := filepath.Join(outputDir, "llms.txt")
+		if err := os.WriteFile(llmsTxtPath, []byte(llmsTxt), 0644); err != nil {
+			fmt.Printf("  Warning: failed to write %sllms.txt: %v\n", label, err)
+		} else {
+			llmsTxtSize := len(llmsTxt)
+			fmt.Printf("  Created %sllms.txt (%s)\n", label, humanizeBytes(llmsTxtSize))
+		}
+	}
+
+	// Generate llms-full.txt
+	llmsFullTxt, err := exporter.ToLLMSFullTxt(documents, projectConfig)
+	if err != nil {
+		fmt.Printf("  Warning: failed to generate %sllms-full.txt: %v\n", label, err)
+	} else {
+		llmsFullTxtPath := filepath.Join(outputDir, "llms-full.txt")
+		if err := os.WriteFile(llmsFullTxtPath, []byte(llmsFullTxt), 0644); err != nil {
+			fmt.Printf("  Warning: failed to write %sllms-full.txt: %v\n", label, err)
+		} else {
+			llmsFullTxtSize := len(llmsFullTxt)
+			fmt.Printf("  Created %sllms-full.txt (%s)\n", label, humanizeBytes(llmsFullTxtSize))
+		}
+	}
+}
+
+// loadProjectConfig returns the llms.txt project settings from the build
+// configuration and the llms_txt config section. A --base-url flag, when the
+// command defines one, overrides llms_txt.base_url.
+func loadProjectConfig(cmd *cobra.Command, config BuildConfig) export.ProjectConfig {
+	projectConfig := export.ProjectConfig{
+		Name:          config.ProjectName,
+		Description:   config.ProjectDescription,
+		BaseURL:       viper.GetString("llms_txt.base_url"),
+		LinkExtension: viper.GetString("llms_txt.link_extension"),
+		Optional:      viper.GetStringSlice("llms_txt.optional"),
+	}
+	if flag := cmd.Flags().Lookup("base-url"); flag != nil && flag.Changed {
+		projectConfig.BaseURL = flag.Value.String()
+	}
+	return projectConfig
+}
+
 // humanizeBytes converts a byte count to a human-readable string (e.g., "15KB", "2.3MB")
 func humanizeBytes(bytes int) string {
 	const unit = 1024
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -254,3 +255,233 @@ func TestHumanizeBytes(t *testing.T) {
 		}
 	}
 }
+
+// linkPattern matches the page links of generated HTML: anchors, alternate
+// links and switcher options.
+var linkPattern = regexp.MustCompile(`(?:href|<option value)="([^"]*)"`)
+
+// checkLinksResolve fails the test for every link in the HTML files under
+// outputDir that does not point at a generated file. External links,
+// fragments and assets, which depend on the working directory, are skipped.
+// Absolute links are resolved against outputDir as the site root.
+func checkLinksResolve(t *testing.T, outputDir string) {
+	t.Helper()
+	err := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".html" {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range linkPattern.FindAllStringSubmatch(string(content), -1) {
+			link := strings.SplitN(match[1], "#", 2)[0]
+			if link == "" || strings.Contains(link, "//") || strings.Contains(link, "assets/") {
+				continue
+			}
+
+			target := filepath.Join(filepath.Dir(path), filepath.FromSlash(link))
+			if strings.HasPrefix(link, "/") {
+				target = filepath.Join(outputDir, filepath.FromSlash(link))
+			}
+			if info, err := os.Stat(target); err == nil && info.IsDir() {
+				target = filepath.Join(target, "index.html")
+			}
+			if _, err := os.Stat(target); err != nil {
+				relativePath, _ := filepath.Rel(outputDir, path)
+				t.Errorf("%s links to %s, which was not generated", relativePath, match[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk %s: %v", outputDir, err)
+	}
+}
+
+// TestBuildVersions_LinksResolve verifies that every link of a versioned
+// site resolves, including links to versions whose home page is README.md.
+func TestBuildVersions_LinksResolve(t *testing.T) {
+	tmpDir := t.TempDir()
+	docsDir := filepath.Join(tmpDir, "docs")
+	files := map[string]string{
+		"v1/README.md": "# Version 1\n\nSee the [guide](guide.md).\n",
+		"v1/guide.md":  "# Guide\n\nOld guide.\n",
+		"v2/README.md": "# Version 2\n\nNew home.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(docsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	outputDir := filepath.Join(tmpDir, "dist")
+	configContent := `input:
+  paths:
+    - "` + docsDir + `"
+
+output:
+  path: "` + outputDir + `"
+
+features:
+  versioning: true
+
+versioning:
+  detect: true
+`
+	configPath := filepath.Join(tmpDir, "jot.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configPath)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "", "output directory")
+	cmd.Flags().BoolP("clean", "c", false, "clean output directory")
+	cmd.Flags().Bool("skip-llms-txt", false, "skip llms.txt generation")
+
+	if err := runBuild(cmd, []string{}); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for _, page := range []string{"latest/index.html", "v1/index.html", "v2/index.html"} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(page))); err != nil {
+			t.Errorf("%s was not created", page)
+		}
+	}
+	checkLinksResolve(t, outputDir)
+}
+
+// TestBuildVersions_DetectOff verifies that a directory named like a version
+// next to ordinary pages does not turn the build into a versioned site unless
+// versioning.detect is set.
+func TestBuildVersions_DetectOff(t *testing.T) {
+	tmpDir := t.TempDir()
+	docsDir := filepath.Join(tmpDir, "docs")
+	files := map[string]string{
+		"README.md":       "# Home\n\nWelcome.\n",
+		"guide.md":        "# Guide\n\nRead me.\n",
+		"2024/release.md": "# 2024 Release\n\nNotes.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(docsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	outputDir := filepath.Join(tmpDir, "dist")
+	configContent := `input:
+  paths:
+    - "` + docsDir + `"
+
+output:
+  path: "` + outputDir + `"
+
+features:
+  versioning: true
+`
+	configPath := filepath.Join(tmpDir, "jot.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configPath)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "", "output directory")
+	cmd.Flags().BoolP("clean", "c", false, "clean output directory")
+	cmd.Flags().Bool("skip-llms-txt", false, "skip llms.txt generation")
+
+	if err := runBuild(cmd, []string{}); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for _, page := range []string{"index.html", "guide.html", "2024/release.html"} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(page))); err != nil {
+			t.Errorf("%s was not created", page)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "latest")); err == nil {
+		t.Error("latest/ was created, want an unversioned site")
+	}
+}
+
+// TestBuildLocales_LinksResolve verifies that every link of a multi-language
+// site resolves, including links to locales whose home page is README.md.
+func TestBuildLocales_LinksResolve(t *testing.T) {
+	tmpDir := t.TempDir()
+	docsDir := filepath.Join(tmpDir, "docs")
+	files := map[string]string{
+		"README.md":    "# Home\n\nSee the [guide](guide.md).\n",
+		"README.ja.md": "# ホーム\n\nようこそ。\n",
+		"guide.md":     "# Guide\n\nNot translated.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(docsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	outputDir := filepath.Join(tmpDir, "dist")
+	configContent := `input:
+  paths:
+    - "` + docsDir + `"
+
+output:
+  path: "` + outputDir + `"
+
+i18n:
+  default: en
+  locales:
+    - code: en
+    - code: ja
+`
+	configPath := filepath.Join(tmpDir, "jot.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configPath)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "", "output directory")
+	cmd.Flags().BoolP("clean", "c", false, "clean output directory")
+	cmd.Flags().Bool("skip-llms-txt", false, "skip llms.txt generation")
+
+	if err := runBuild(cmd, []string{}); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for _, page := range []string{"en/index.html", "ja/index.html", "ja/guide.html"} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(page))); err != nil {
+			t.Errorf("%s was not created", page)
+		}
+	}
+	checkLinksResolve(t, outputDir)
+}
@@ -0,0 +1,234 @@
+// Package main is the entry point for the Jot CLI application.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/dedup"
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+	"github.com/spf13/cobra"
+)
+
+// checkCmd groups commands that inspect the documentation for problems.
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check documentation for problems",
+}
+
+// checkDuplicatesCmd reports near-duplicate documents and chunks.
+var checkDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Report near-duplicate documents and chunks",
+	Long: `Report near-duplicate documents and chunks, such as pages copied between
+product versions, which pollute retrieval results.
+
+Texts are compared by MinHash signatures over three-word shingles; two texts
+are near-duplicates when their estimated Jaccard similarity reaches the
+threshold. The first text of each group (in scan order) is canonical. Chunks
+are produced as by jot export, and chunks of documents that are duplicates
+as a whole are not reported again.
+
+Examples:
+  jot check duplicates
+  jot check duplicates --threshold 0.9 --scope chunks --strategy markdown
+  jot check duplicates --json > duplicates.json
+  jot check duplicates --fail   # exit non-zero when duplicates are found, e.g. in CI`,
+	RunE: runCheckDuplicates,
+}
+
+func init() {
+	checkDuplicatesCmd.Flags().Float64("threshold", dedup.DefaultThreshold, "similarity (0-1) at which texts count as near-duplicates")
+	checkDuplicatesCmd.Flags().String("scope", "all", "what to compare: documents, chunks, all")
+	checkDuplicatesCmd.Flags().StringP("strategy", "s", "fixed", "chunking strategy: fixed, semantic, markdown-headers, markdown, recursive")
+	checkDuplicatesCmd.Flags().Int("chunk-size", 512, "maximum tokens per chunk")
+	checkDuplicatesCmd.Flags().Int("chunk-overlap", 128, "token overlap between chunks")
+	checkDuplicatesCmd.Flags().String("tokenizer", "", "token encoding: cl100k_base, o200k_base, p50k_base, heuristic (overrides llm.tokenizer)")
+	checkDuplicatesCmd.Flags().Bool("json", false, "print the report as JSON")
+	checkDuplicatesCmd.Flags().Bool("fail", false, "exit with an error when near-duplicates are found")
+
+	checkCmd.AddCommand(checkDuplicatesCmd)
+	rootCmd.AddCommand(checkCmd)
+}
+
+// duplicateReport is the JSON form of the duplicates report.
+type duplicateReport struct {
+	Threshold float64          `json:"threshold"`
+	Documents []duplicateGroup `json:"documents"`
+	Chunks    []duplicateGroup `json:"chunks"`
+}
+
+// duplicateGroup is a canonical document or chunk and its near-duplicates.
+type duplicateGroup struct {
+	Canonical  duplicateItem   `json:"canonical"`
+	Duplicates []duplicateItem `json:"duplicates"`
+}
+
+// duplicateItem identifies a document or chunk in the report.
+type duplicateItem struct {
+	ID         string  `json:"id"`
+	Source     string  `json:"source"`
+	Similarity float64 `json:"similarity,omitempty"` // To the canonical item
+	Preview    string  `json:"-"`
+}
+
+// runCheckDuplicates executes the logic for the check duplicates command.
+func runCheckDuplicates(cmd *cobra.Command, args []string) error {
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("threshold must be >0 and <=1 (got %g)", threshold)
+	}
+	scope, _ := cmd.Flags().GetString("scope")
+	if scope != "documents" && scope != "chunks" && scope != "all" {
+		return fmt.Errorf("unsupported scope: %s (supported: documents, chunks, all)", scope)
+	}
+	asJSON, _ := cmd.Flags().GetBool("json")
+	fail, _ := cmd.Flags().GetBool("fail")
+
+	// Load configuration
+	config := loadBuildConfig(cmd)
+
+	fmt.Fprintln(os.Stderr, " Scanning for markdown files...")
+
+	var allDocs []scanner.Document
+	for _, inputPath := range config.InputPaths {
+		// Check if path exists
+		if _, err := os.Stat(inputPath); err != nil {
+			continue
+		}
+
+		s, err := scanner.NewScanner(inputPath, config.IgnorePatterns)
+		if err != nil {
+			return fmt.Errorf("failed to create scanner: %w", err)
+		}
+
+		// Scan documents
+		docs, err := s.Scan()
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", inputPath, err)
+		}
+
+		allDocs = append(allDocs, docs...)
+	}
+
+	if len(allDocs) == 0 {
+		return fmt.Errorf("no markdown files found")
+	}
+
+	fmt.Fprintf(os.Stderr, "  Found %d markdown files\n\n", len(allDocs))
+
+	report := duplicateReport{Threshold: threshold, Documents: []duplicateGroup{}, Chunks: []duplicateGroup{}}
+	duplicateDocs := make(map[string]bool)
+
+	if scope != "chunks" {
+		items := make(map[string]duplicateItem, len(allDocs))
+		detector := dedup.NewDetector(threshold)
+		for _, doc := range allDocs {
+			items[doc.ID] = duplicateItem{ID: doc.ID, Source: doc.RelativePath}
+			if _, ok := detector.Add(doc.ID, string(doc.Content)); ok {
+				duplicateDocs[doc.ID] = true
+			}
+		}
+		report.Documents = duplicateGroups(detector, items)
+	}
+
+	if scope != "documents" {
+		tok, err := tokenizer.New(exportEncoding(cmd))
+		if err != nil {
+			return err
+		}
+		strategyName, _ := cmd.Flags().GetString("strategy")
+		strategy, err := chunking.NewChunkStrategy(strategyName, tok)
+		if err != nil {
+			return err
+		}
+		chunkSize, _ := cmd.Flags().GetInt("chunk-size")
+		chunkOverlap, _ := cmd.Flags().GetInt("chunk-overlap")
+
+		items := make(map[string]duplicateItem)
+		detector := dedup.NewDetector(threshold)
+		for _, doc := range allDocs {
+			if duplicateDocs[doc.ID] {
+				continue
+			}
+			chunks, err := strategy.Chunk(doc, chunkSize, chunkOverlap)
+			if err != nil {
+				return fmt.Errorf("failed to chunk %s: %w", doc.RelativePath, err)
+			}
+			for _, chunk := range chunks {
+				items[chunk.ID] = duplicateItem{ID: chunk.ID, Source: doc.RelativePath, Preview: chunk.Text}
+				detector.Add(chunk.ID, chunk.Text)
+			}
+		}
+		report.Chunks = duplicateGroups(detector, items)
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		if scope != "chunks" {
+			printDuplicateGroups("documents", threshold, report.Documents)
+		}
+		if scope != "documents" {
+			printDuplicateGroups("chunks", threshold, report.Chunks)
+		}
+	}
+
+	if fail && len(report.Documents)+len(report.Chunks) > 0 {
+		return fmt.Errorf("found %d groups of near-duplicate documents and %d of chunks", len(report.Documents), len(report.Chunks))
+	}
+	return nil
+}
+
+// duplicateGroups converts the groups found by detector into report groups.
+func duplicateGroups(detector *dedup.Detector, items map[string]duplicateItem) []duplicateGroup {
+	groups := []duplicateGroup{}
+	for _, group := range detector.Groups() {
+		reported := duplicateGroup{Canonical: items[group.CanonicalID]}
+		for _, duplicate := range group.Duplicates {
+			item := items[duplicate.ID]
+			item.Similarity = duplicate.Similarity
+			reported.Duplicates = append(reported.Duplicates, item)
+		}
+		groups = append(groups, reported)
+	}
+	return groups
+}
+
+// printDuplicateGroups prints one section of the text report.
+func printDuplicateGroups(kind string, threshold float64, groups []duplicateGroup) {
+	duplicates := 0
+	for _, group := range groups {
+		duplicates += len(group.Duplicates)
+	}
+	fmt.Printf(" Near-duplicate %s (similarity >= %g): %d groups, %d duplicates\n", kind, threshold, len(groups), duplicates)
+
+	for _, group := range groups {
+		fmt.Printf("  %s\n", describeDuplicateItem(group.Canonical))
+		for _, duplicate := range group.Duplicates {
+			fmt.Printf("    %.2f  %s\n", duplicate.Similarity, describeDuplicateItem(duplicate))
+		}
+	}
+	fmt.Println()
+}
+
+// describeDuplicateItem formats a document as its path and a chunk as its
+// path, ID and the start of its text.
+func describeDuplicateItem(item duplicateItem) string {
+	if item.Preview == "" {
+		return item.Source
+	}
+	preview := strings.Join(strings.Fields(item.Preview), " ")
+	if runes := []rune(preview); len(runes) > 60 {
+		preview = string(runes[:60]) + "..."
+	}
+	return fmt.Sprintf("%s [%s] %q", item.Source, item.ID, preview)
+}
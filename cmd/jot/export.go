@@ -2,14 +2,27 @@
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/dedup"
+	"github.com/onedusk/jot/internal/embedding"
+	"github.com/onedusk/jot/internal/enrich"
 	"github.com/onedusk/jot/internal/export"
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
+	"github.com/onedusk/jot/internal/tokenizer"
+	"github.com/onedusk/jot/internal/vectorstore"
 )
 
 // exportCmd provides the command for exporting documentation into various formats
@@ -26,13 +39,15 @@ Supported formats:
   - llms-full:  Complete documentation concatenated for LLM context
   - jsonl:      JSON Lines format for vector database ingestion
   - markdown:   Enriched markdown with YAML frontmatter
+  - llm:        JSON with chunks, sections, code blocks and a keyword index
 
 Chunking strategies:
   - fixed:            Fixed-size token chunks with word boundaries (default)
   - semantic:         Semantic boundary detection for natural breaks
   - markdown-headers: Split at markdown header boundaries
+  - markdown:         Structure-aware; never splits code blocks, tables, lists or quotes
   - recursive:        Hierarchical splitting (paragraph->line->space->char)
-  - contextual:       Context-aware chunking (alias for semantic)
+  - contextual:       Semantic chunking with a context preamble on every chunk
 
 Examples:
   # Export to llms.txt format
@@ -44,31 +59,74 @@ Examples:
   # Export markdown with header-based chunking
   jot export --format markdown --strategy markdown-headers --output docs.md
 
+  # Export one markdown file per document into docs-md/, with docs-md/index.json
+  jot export --format markdown --split --output docs-md
+
+  # Export documents with chunks, sections and a keyword index as one JSON file
+  jot export --format llm --output docs.llm.json
+
   # Export JSONL with custom chunk size
   jot export --format jsonl --chunk-size 1024 --chunk-overlap 256 --output chunks.jsonl
 
   # Export with embeddings (warning: API costs apply)
-  jot export --format jsonl --include-embeddings --output embeddings.jsonl`,
+  jot export --format jsonl --include-embeddings --output embeddings.jsonl
+
+  # Export with embeddings from a local Ollama server
+  jot export --format jsonl --include-embeddings --embeddings-url http://localhost:11434/v1 --embeddings-model nomic-embed-text
+
+  # Export with offline hashed bag-of-words embeddings (no network)
+  jot export --format jsonl --include-embeddings --embeddings-provider hash
+
+  # Upsert chunks into a Qdrant collection, deleting chunks of removed documents
+  jot export --push qdrant://localhost:6333/docs --include-embeddings --embeddings-provider hash
+
+  # Export only chunks changed since a previous export, plus removed chunk IDs
+  jot export --format jsonl --since docs.manifest.json --manifest docs.manifest.json --output delta.jsonl
+
+  # Drop chunks that near-duplicate earlier ones (e.g. docs copied between versions)
+  jot export --format jsonl --dedup drop --output docs.jsonl
+
+  # Export Qdrant points (also: pinecone, weaviate, chroma, langchain, llamaindex)
+  jot export --format jsonl --target qdrant --include-embeddings --output points.jsonl`,
 	RunE: runExport,
 }
 
 func init() {
 	// Format selection
-	exportCmd.Flags().StringP("format", "f", "json", "export format: json, yaml, llms-txt, llms-full, jsonl, markdown")
+	exportCmd.Flags().StringP("format", "f", "json", "export format: json, yaml, llms-txt, llms-full, jsonl, markdown, llm, training")
 	exportCmd.Flags().StringP("output", "o", "", "output file (default: stdout)")
 
 	// Chunking configuration
-	exportCmd.Flags().StringP("strategy", "s", "fixed", "chunking strategy: fixed, semantic, markdown-headers, recursive, contextual")
+	exportCmd.Flags().StringP("strategy", "s", "fixed", "chunking strategy: fixed, semantic, markdown-headers, markdown, recursive, contextual")
+	exportCmd.Flags().String("tokenizer", "", "token encoding: cl100k_base, o200k_base, p50k_base, heuristic (overrides llm.tokenizer)")
+	exportCmd.Flags().String("target", "jot", "JSONL record layout: jot, pinecone, qdrant, weaviate, chroma, langchain, llamaindex")
 	exportCmd.Flags().IntP("chunk-size", "", 512, "maximum tokens per chunk (must be >0 and <=2048)")
 	exportCmd.Flags().IntP("chunk-overlap", "", 128, "token overlap between chunks (must be >0 and <=2048)")
 
 	// Preset configurations
 	exportCmd.Flags().Bool("for-rag", false, "preset for RAG: jsonl format + semantic strategy + 512 tokens")
 	exportCmd.Flags().Bool("for-context", false, "preset for context: markdown format + headers strategy + 1024 tokens")
-	exportCmd.Flags().Bool("for-training", false, "preset for training: jsonl format + fixed strategy + 256 tokens")
+	exportCmd.Flags().Bool("for-training", false, "preset for fine-tuning: training format, chat-message Q/A pairs with a validation split")
 
 	// Advanced options
-	exportCmd.Flags().Bool("include-embeddings", false, "generate embeddings for JSONL format (warning: API costs apply)")
+	exportCmd.Flags().Bool("include-embeddings", false, "generate embeddings for JSONL and LLM formats; the default openai provider needs OPENAI_API_KEY unless --embeddings-url is set (warning: API costs apply)")
+	exportCmd.Flags().String("embeddings-provider", "", "embedding provider: openai, hash (overrides llm.embeddings.provider)")
+	exportCmd.Flags().String("embeddings-url", "", "base URL of an OpenAI-compatible embeddings API (overrides llm.embeddings.url)")
+	exportCmd.Flags().String("embeddings-model", "", "embedding model name (overrides llm.embeddings.model)")
+	exportCmd.Flags().Bool("contextual", false, "prepend a context preamble to each chunk (jsonl, markdown)")
+	exportCmd.Flags().String("context-url", "", "base URL of an OpenAI-compatible chat API for generated context (overrides llm.context.url)")
+	exportCmd.Flags().String("context-model", "", "chat model for generated context (overrides llm.context.model)")
+	exportCmd.Flags().String("manifest", "", "write a manifest of exported documents and chunk IDs to this file (jsonl)")
+	exportCmd.Flags().String("since", "", "export only chunks added or changed since the export described by this manifest (jsonl)")
+	exportCmd.Flags().String("tombstones", "", "with --since, write removed chunk IDs to this file (default: <output>.tombstones.json)")
+	exportCmd.Flags().Int("max-tokens", 0, "fit llms-full output into this many tokens, summarizing or omitting low-priority documents (0: no limit)")
+	exportCmd.Flags().String("base-url", "", "base URL for llms.txt links (overrides llms_txt.base_url)")
+	exportCmd.Flags().String("dedup", "", "handle near-duplicate chunks in jsonl format: drop, or merge (keep with canonical_chunk_id)")
+	exportCmd.Flags().Float64("dedup-threshold", dedup.DefaultThreshold, "similarity (0-1) at which chunks count as near-duplicates")
+	exportCmd.Flags().Float64("validation-split", export.DefaultValidationSplit, "fraction of documents held out for validation in training format (0: none)")
+	exportCmd.Flags().String("validation-output", "", "file for the validation split of training format (default: <output>.validation.jsonl)")
+	exportCmd.Flags().Bool("split", false, "write markdown as one file per document (per chunk with --strategy) into the --output directory")
+	exportCmd.Flags().String("push", "", "upsert chunks into a vector store instead of writing output: qdrant://host:6333/collection, chroma://..., weaviate://...; deletes stale chunks of the same llm.push.source only")
 
 	rootCmd.AddCommand(exportCmd)
 }
@@ -118,7 +176,7 @@ func validateExportFlags(cmd *cobra.Command) error {
 
 	// Validate format
 	format, _ := cmd.Flags().GetString("format")
-	validFormats := []string{"json", "yaml", "llms-txt", "llms-full", "jsonl", "markdown"}
+	validFormats := []string{"json", "yaml", "llms-txt", "llms-full", "jsonl", "markdown", "llm", "training"}
 	isValidFormat := false
 	for _, vf := range validFormats {
 		if format == vf {
@@ -127,27 +185,96 @@ func validateExportFlags(cmd *cobra.Command) error {
 		}
 	}
 	if !isValidFormat {
-		return fmt.Errorf("unsupported format: %s (supported: json, yaml, llms-txt, llms-full, jsonl, markdown)\n\nExample:\n  jot export --format llms-txt --output llms.txt", format)
+		return fmt.Errorf("unsupported format: %s (supported: json, yaml, llms-txt, llms-full, jsonl, markdown, llm, training)\n\nExample:\n  jot export --format llms-txt --output llms.txt", format)
 	}
 
 	// Validate strategy
 	strategy, _ := cmd.Flags().GetString("strategy")
-	validStrategies := []string{"fixed", "semantic", "markdown-headers", "recursive", "contextual"}
 	isValidStrategy := false
-	for _, vs := range validStrategies {
+	for _, vs := range chunking.AvailableStrategies() {
 		if strategy == vs {
 			isValidStrategy = true
 			break
 		}
 	}
 	if !isValidStrategy {
-		return fmt.Errorf("unsupported strategy: %s (supported: fixed, semantic, markdown-headers, recursive, contextual)\n\nExample:\n  jot export --format jsonl --strategy semantic --output docs.jsonl", strategy)
+		return fmt.Errorf("unsupported strategy: %s (supported: %s)\n\nExample:\n  jot export --format jsonl --strategy semantic --output docs.jsonl", strategy, strings.Join(chunking.AvailableStrategies(), ", "))
+	}
+
+	// Validate tokenizer
+	encoding := exportEncoding(cmd)
+	isValidEncoding := false
+	for _, ve := range tokenizer.AvailableEncodings() {
+		if encoding == ve {
+			isValidEncoding = true
+			break
+		}
+	}
+	if !isValidEncoding {
+		return fmt.Errorf("unsupported tokenizer: %s (supported: %s)\n\nExample:\n  jot export --format jsonl --tokenizer o200k_base --output docs.jsonl", encoding, strings.Join(tokenizer.AvailableEncodings(), ", "))
+	}
+
+	// Validate target
+	target, _ := cmd.Flags().GetString("target")
+	isValidTarget := false
+	for _, vt := range export.AvailableTargets() {
+		if target == vt {
+			isValidTarget = true
+			break
+		}
+	}
+	if !isValidTarget {
+		return fmt.Errorf("unsupported target: %s (supported: %s)\n\nExample:\n  jot export --format jsonl --target qdrant --output points.jsonl", target, strings.Join(export.AvailableTargets(), ", "))
+	}
+	if target != export.DefaultTarget() && format != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Warning: --target only applies to JSONL format (current format: %s)\n", format)
+	}
+
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	if maxTokens < 0 {
+		return fmt.Errorf("max-tokens must be >=0 (got %d)\n\nExample:\n  jot export --format llms-full --max-tokens 100000 --output llms-full.txt", maxTokens)
+	}
+	if maxTokens > 0 && format != "llms-full" {
+		fmt.Fprintf(os.Stderr, "Warning: --max-tokens only applies to llms-full format (current format: %s)\n", format)
+	}
+
+	dedupMode, _ := cmd.Flags().GetString("dedup")
+	if dedupMode != "" && dedupMode != export.DuplicatesDrop && dedupMode != export.DuplicatesMerge {
+		return fmt.Errorf("unsupported dedup mode: %s (supported: drop, merge)\n\nExample:\n  jot export --format jsonl --dedup drop --output docs.jsonl", dedupMode)
+	}
+	dedupThreshold, _ := cmd.Flags().GetFloat64("dedup-threshold")
+	if dedupThreshold <= 0 || dedupThreshold > 1 {
+		return fmt.Errorf("dedup-threshold must be >0 and <=1 (got %g)", dedupThreshold)
+	}
+	if push, _ := cmd.Flags().GetString("push"); dedupMode != "" && format != "jsonl" && !forRAG && push == "" {
+		fmt.Fprintf(os.Stderr, "Warning: --dedup only applies to JSONL format (current format: %s)\n", format)
+	}
+
+	validationSplit, _ := cmd.Flags().GetFloat64("validation-split")
+	if validationSplit < 0 || validationSplit >= 1 {
+		return fmt.Errorf("validation-split must be >=0 and <1 (got %g)\n\nExample:\n  jot export --for-training --validation-split 0.2 --output train.jsonl", validationSplit)
+	}
+
+	split, _ := cmd.Flags().GetBool("split")
+	if split {
+		if format != "markdown" && !forContext {
+			return fmt.Errorf("--split requires markdown format (current format: %s)", format)
+		}
+		if outputFile, _ := cmd.Flags().GetString("output"); outputFile == "" {
+			return fmt.Errorf("--split requires --output to name a directory\n\nExample:\n  jot export --format markdown --split --output docs-md")
+		}
+	}
+
+	manifestFile, _ := cmd.Flags().GetString("manifest")
+	if manifestFile != "" && format != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Warning: --manifest only applies to JSONL format (current format: %s)\n", format)
 	}
 
 	// Warn if include-embeddings is used with non-JSONL format
 	includeEmbeddings, _ := cmd.Flags().GetBool("include-embeddings")
-	if includeEmbeddings && format != "jsonl" {
-		fmt.Fprintf(os.Stderr, "Warning: --include-embeddings only applies to JSONL format (current format: %s)\n", format)
+	pushURL, _ := cmd.Flags().GetString("push")
+	if includeEmbeddings && format != "jsonl" && format != "llm" && pushURL == "" {
+		fmt.Fprintf(os.Stderr, "Warning: --include-embeddings only applies to JSONL and LLM formats (current format: %s)\n", format)
 	}
 
 	return nil
@@ -163,6 +290,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 	// Get flags
 	format, _ := cmd.Flags().GetString("format")
 	outputFile, _ := cmd.Flags().GetString("output")
+	targetName, _ := cmd.Flags().GetString("target")
 	strategy, _ := cmd.Flags().GetString("strategy")
 	chunkSize, _ := cmd.Flags().GetInt("chunk-size")
 	chunkOverlap, _ := cmd.Flags().GetInt("chunk-overlap")
@@ -178,25 +306,78 @@ func runExport(cmd *cobra.Command, args []string) error {
 		strategy = "semantic"
 		chunkSize = 512
 		chunkOverlap = 128
-		fmt.Println(" Using RAG preset: jsonl format, semantic strategy, 512 token chunks")
+		fmt.Fprintln(os.Stderr, " Using RAG preset: jsonl format, semantic strategy, 512 token chunks")
 	} else if forContext {
 		format = "markdown"
 		strategy = "markdown-headers"
 		chunkSize = 1024
 		chunkOverlap = 256
-		fmt.Println(" Using context preset: markdown format, headers strategy, 1024 token chunks")
+		fmt.Fprintln(os.Stderr, " Using context preset: markdown format, headers strategy, 1024 token chunks")
 	} else if forTraining {
+		format = "training"
+		fmt.Fprintln(os.Stderr, " Using training preset: chat-message Q/A pairs from headings, code blocks and definitions")
+	}
+
+	// Pushing to a vector store exports JSONL chunks with embeddings
+	pushURL, _ := cmd.Flags().GetString("push")
+	var store vectorstore.Store
+	if pushURL != "" {
+		if format != "jsonl" && (cmd.Flags().Changed("format") || forContext) {
+			return fmt.Errorf("--push requires jsonl format (current format: %s)", format)
+		}
+		if outputFile != "" {
+			return fmt.Errorf("--push and --output cannot be used together")
+		}
+		if !includeEmbeddings {
+			return fmt.Errorf("--push requires --include-embeddings\n\nExample:\n  jot export --push qdrant://localhost:6333/docs --include-embeddings --embeddings-url http://localhost:11434/v1 --embeddings-model nomic-embed-text")
+		}
 		format = "jsonl"
-		strategy = "fixed"
-		chunkSize = 256
-		chunkOverlap = 64
-		fmt.Println(" Using training preset: jsonl format, fixed strategy, 256 token chunks")
+
+		var err error
+		store, err = vectorstore.Open(pushURL, viper.GetString("llm.push.api_key"))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Training exports hold out a validation split in a second file
+	validationSplit, _ := cmd.Flags().GetFloat64("validation-split")
+	validationFile, _ := cmd.Flags().GetString("validation-output")
+	if format == "training" && validationSplit > 0 {
+		if validationFile == "" {
+			validationFile = defaultValidationPath(outputFile)
+		}
+		if validationFile == "" {
+			return fmt.Errorf("training format with a validation split requires --validation-output when writing to stdout\n\nExample:\n  jot export --for-training --validation-output valid.jsonl > train.jsonl")
+		}
+	}
+
+	// Incremental exports compare against the manifest of a previous export
+	sinceFile, _ := cmd.Flags().GetString("since")
+	tombstonesFile, _ := cmd.Flags().GetString("tombstones")
+	var previous *export.Manifest
+	if sinceFile != "" {
+		if format != "jsonl" {
+			return fmt.Errorf("--since requires jsonl format (current format: %s)", format)
+		}
+		if tombstonesFile == "" {
+			tombstonesFile = defaultTombstonesPath(outputFile)
+		}
+		if tombstonesFile == "" && store == nil {
+			return fmt.Errorf("--since requires --tombstones when writing to stdout\n\nExample:\n  jot export --format jsonl --since docs.manifest.json --tombstones removed.json > delta.jsonl")
+		}
+
+		var err error
+		previous, err = export.ReadManifest(sinceFile)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Load configuration
 	config := loadBuildConfig(cmd)
 
-	fmt.Println(" Scanning for markdown files...")
+	fmt.Fprintln(os.Stderr, " Scanning for markdown files...")
 
 	var allDocs []scanner.Document
 	for _, inputPath := range config.InputPaths {
@@ -210,6 +391,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to create scanner: %w", err)
 		}
+		s.SetGitHistory(config.GitHistory)
 
 		// Scan documents
 		docs, err := s.Scan()
@@ -224,98 +406,561 @@ func runExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no markdown files found")
 	}
 
-	fmt.Printf("  Found %d markdown files\n\n", len(allDocs))
+	fmt.Fprintf(os.Stderr, "  Found %d markdown files\n\n", len(allDocs))
+
+	var err error
+
+	// Set up the embedding provider if requested
+	var embedder embedding.Embedder
+	if includeEmbeddings && (format == "jsonl" || format == "llm") {
+		embeddingConfig := loadEmbeddingConfig(cmd)
+		embedder, err = embedding.NewEmbedder(embeddingConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize embeddings: %w", err)
+		}
+
+		if embeddingConfig.IsRemote() {
+			endpoint := embeddingConfig.BaseURL
+			if endpoint == "" {
+				endpoint = embedding.DefaultBaseURL
+			}
+			fmt.Fprintf(os.Stderr, " WARNING: --include-embeddings will send document text to %s\n", endpoint)
+			fmt.Fprintln(os.Stderr, " This may incur costs and take significant time depending on document size")
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+
+	// Set up the chunking strategy for formats that split documents
+	var tok tokenizer.Tokenizer
+	var chunkStrategy chunking.ChunkStrategy
+	if format == "jsonl" || format == "markdown" || format == "llm" {
+		tok, err = tokenizer.New(exportEncoding(cmd))
+		if err != nil {
+			return err
+		}
+		chunkStrategy, err = newExportStrategy(strategy, tok, embedder)
+		if err != nil {
+			return err
+		}
+	}
 
-	// Log embeddings warning if applicable
-	if includeEmbeddings && format == "jsonl" {
-		fmt.Println(" WARNING: --include-embeddings will generate embeddings using external API")
-		fmt.Println(" This may incur costs and take significant time depending on document size")
-		fmt.Println("")
+	// Set up contextual enrichment if requested
+	var enricher *enrich.Enricher
+	if format == "jsonl" || format == "markdown" {
+		enricher, err = newExportEnricher(cmd, strategy)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Create exporter
 	exporter := export.NewExporter()
+	exporter.SetEmbedder(embedder)
+	exporter.SetStrategy(chunkStrategy)
+	exporter.SetChunkSize(chunkSize, chunkOverlap)
+	exporter.SetTokenizer(tok)
+
+	// Record exported chunks for consumers that diff exports; incremental
+	// exports always need the manifest to compute tombstones
+	manifestFile, _ := cmd.Flags().GetString("manifest")
+	var manifest *export.Manifest
+	if (manifestFile != "" || previous != nil) && format == "jsonl" {
+		manifest = export.NewManifest()
+	}
+
+	if store != nil {
+		jsonlExporter := export.NewJSONLExporter()
+		jsonlExporter.SetEmbedder(embedder)
+		jsonlExporter.SetStrategy(chunkStrategy)
+		jsonlExporter.SetTokenizer(tok)
+		jsonlExporter.SetEnricher(enricher)
+		jsonlExporter.SetManifest(manifest)
+		jsonlExporter.SetSince(previous)
+		setExportDuplicates(cmd, jsonlExporter)
+		if err := pushExport(jsonlExporter, store, allDocs, chunkSize, chunkOverlap, manifest, previous); err != nil {
+			return err
+		}
+		return writeManifest(manifestFile, manifest)
+	}
+
+	if split, _ := cmd.Flags().GetBool("split"); split {
+		// Files are per chunk only when a strategy was asked for
+		markdownExporter := export.NewMarkdownExporterWithTokenizer(tok)
+		if cmd.Flags().Changed("strategy") || forContext {
+			markdownExporter.SetStrategy(chunkStrategy, chunkSize, chunkOverlap)
+			fmt.Fprintf(os.Stderr, " Exporting enriched markdown files (strategy: %s, chunk-size: %d)...\n", strategy, chunkSize)
+		} else {
+			fmt.Fprintln(os.Stderr, " Exporting enriched markdown files (one per document)...")
+		}
+		markdownExporter.SetEnricher(enricher)
+		index, err := markdownExporter.WriteSeparateFiles(outputFile, allDocs)
+		if err != nil {
+			return fmt.Errorf("failed to export: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, " Exported %d files to %s\n", len(index.Files), outputFile)
+		return nil
+	}
+
+	// Open the destination before exporting so that streaming formats can
+	// write documents as they are processed
+	out, err := openExportOutput(outputFile)
+	if err != nil {
+		return err
+	}
 
 	var output string
-	var err error
 
 	// Export based on format
 	switch format {
 	case "json":
-		fmt.Println(" Exporting to JSON...")
+		fmt.Fprintln(os.Stderr, " Exporting to JSON...")
 		output, err = exporter.ToJSON(allDocs)
 
 	case "yaml":
-		fmt.Println(" Exporting to YAML...")
+		fmt.Fprintln(os.Stderr, " Exporting to YAML...")
 		output, err = exporter.ToYAML(allDocs)
 
 	case "llms-txt":
-		fmt.Println(" Exporting to llms.txt format...")
+		fmt.Fprintln(os.Stderr, " Exporting to llms.txt format...")
 		llmsTxtExporter := export.NewLLMSTxtExporter()
-		projectConfig := export.ProjectConfig{
-			Name:        config.ProjectName,
-			Description: config.ProjectDescription,
-		}
-		output, err = llmsTxtExporter.ToLLMSTxt(allDocs, projectConfig)
+		llmsTxtExporter.SetTOC(toc.NewBuilder().Build(allDocs))
+		output, err = llmsTxtExporter.ToLLMSTxt(allDocs, loadProjectConfig(cmd, config))
 
 	case "llms-full":
-		fmt.Println(" Exporting to llms-full.txt format...")
+		fmt.Fprintln(os.Stderr, " Exporting to llms-full.txt format...")
 		llmsTxtExporter := export.NewLLMSTxtExporter()
-		projectConfig := export.ProjectConfig{
-			Name:        config.ProjectName,
-			Description: config.ProjectDescription,
+		if maxTokens, _ := cmd.Flags().GetInt("max-tokens"); maxTokens > 0 {
+			budgetTokenizer, tokErr := tokenizer.New(exportEncoding(cmd))
+			if tokErr != nil {
+				out.Abort()
+				return tokErr
+			}
+			llmsTxtExporter.SetMaxTokens(maxTokens, budgetTokenizer)
+			fmt.Fprintf(os.Stderr, " Fitting into %d tokens (tokenizer: %s)...\n", maxTokens, budgetTokenizer.Encoding())
 		}
-		output, err = llmsTxtExporter.ToLLMSFullTxt(allDocs, projectConfig)
+		output, err = llmsTxtExporter.ToLLMSFullTxt(allDocs, loadProjectConfig(cmd, config))
 
 	case "jsonl":
-		fmt.Printf(" Exporting to JSONL format (strategy: %s, chunk-size: %d, overlap: %d)...\n", strategy, chunkSize, chunkOverlap)
+		fmt.Fprintf(os.Stderr, " Exporting to JSONL format (strategy: %s, tokenizer: %s, target: %s, chunk-size: %d, overlap: %d)...\n", strategy, tok.Encoding(), targetName, chunkSize, chunkOverlap)
 		jsonlExporter := export.NewJSONLExporter()
-		output, err = jsonlExporter.ToJSONL(allDocs, chunkSize, chunkOverlap)
+		jsonlExporter.SetEmbedder(embedder)
+		jsonlExporter.SetStrategy(chunkStrategy)
+		jsonlExporter.SetTokenizer(tok)
+		jsonlExporter.SetEnricher(enricher)
+		target, targetErr := export.NewTarget(targetName)
+		if targetErr != nil {
+			out.Abort()
+			return targetErr
+		}
+		jsonlExporter.SetTarget(target)
+		jsonlExporter.SetManifest(manifest)
+		jsonlExporter.SetSince(previous)
+		setExportDuplicates(cmd, jsonlExporter)
+		err = jsonlExporter.WriteJSONL(out, allDocs, chunkSize, chunkOverlap)
 
 	case "markdown":
-		fmt.Printf(" Exporting to enriched markdown (strategy: %s, chunk-size: %d)...\n", strategy, chunkSize)
-		markdownExporter, mdErr := export.NewMarkdownExporter()
-		if mdErr != nil {
-			err = mdErr
-		} else {
-			output, err = markdownExporter.ToEnrichedMarkdown(allDocs, false)
-		}
+		fmt.Fprintf(os.Stderr, " Exporting to enriched markdown (strategy: %s, chunk-size: %d)...\n", strategy, chunkSize)
+		markdownExporter := export.NewMarkdownExporterWithTokenizer(tok)
+		markdownExporter.SetStrategy(chunkStrategy, chunkSize, chunkOverlap)
+		markdownExporter.SetEnricher(enricher)
+		err = markdownExporter.WriteEnrichedMarkdown(out, allDocs)
+
+	case "training":
+		fmt.Fprintf(os.Stderr, " Exporting training pairs (validation split: %g)...\n", validationSplit)
+		err = writeTrainingExport(out, validationFile, validationSplit, loadProjectConfig(cmd, config), allDocs)
 
 	case "llm":
-		// Legacy format - keep for backward compatibility
-		fmt.Println(" Exporting for LLM consumption (legacy format)...")
+		fmt.Fprintf(os.Stderr, " Exporting for LLM consumption (strategy: %s, tokenizer: %s)...\n", strategy, tok.Encoding())
 		llmData, llmErr := exporter.ToLLMFormat(allDocs)
 		if llmErr != nil {
 			err = llmErr
 		} else {
-			jsonBytes, _ := json.MarshalIndent(llmData, "", "  ")
-			output = string(jsonBytes)
+			jsonBytes, marshalErr := json.MarshalIndent(llmData, "", "  ")
+			output, err = string(jsonBytes), marshalErr
 		}
 
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+
+	// Formats that are not streamed are written in one piece
+	if err == nil && output != "" {
+		if outputFile == "" {
+			output += "\n"
+		}
+		_, err = out.Write([]byte(output))
 	}
 
 	if err != nil {
+		out.Abort()
 		return fmt.Errorf("failed to export: %w", err)
 	}
 
-	// Write output
+	if err := out.Close(); err != nil {
+		return err
+	}
 	if outputFile != "" {
-		// Ensure directory exists
-		dir := filepath.Dir(outputFile)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		fmt.Fprintf(os.Stderr, " Exported to %s\n", outputFile)
+	}
+
+	if previous != nil {
+		if err := writeTombstones(tombstonesFile, previous, manifest); err != nil {
+			return err
 		}
+	}
 
-		if err := os.WriteFile(outputFile, []byte(output), 0644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+	return writeManifest(manifestFile, manifest)
+}
+
+// defaultTombstonesPath returns the tombstone file written next to an
+// incremental export, or "" when the export goes to stdout.
+func defaultTombstonesPath(outputFile string) string {
+	if outputFile == "" {
+		return ""
+	}
+	base := strings.TrimSuffix(outputFile, ".gz")
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return base + ".tombstones.json"
+}
+
+// setExportDuplicates enables near-duplicate handling on a JSONL exporter
+// when --dedup is given.
+func setExportDuplicates(cmd *cobra.Command, exporter *export.JSONLExporter) {
+	mode, _ := cmd.Flags().GetString("dedup")
+	if mode == "" {
+		return
+	}
+	threshold, _ := cmd.Flags().GetFloat64("dedup-threshold")
+	exporter.SetDuplicates(dedup.NewDetector(threshold), mode)
+	fmt.Fprintf(os.Stderr, " Near-duplicate chunks (similarity >= %g) will be %s\n", threshold, map[string]string{
+		export.DuplicatesDrop:  "dropped",
+		export.DuplicatesMerge: "marked with canonical_chunk_id",
+	}[mode])
+}
+
+// defaultValidationPath returns the validation file written next to a
+// training export, or "" when the export goes to stdout.
+func defaultValidationPath(outputFile string) string {
+	if outputFile == "" {
+		return ""
+	}
+	base := strings.TrimSuffix(outputFile, ".gz")
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + ".validation" + ext + strings.TrimPrefix(outputFile, base)
+}
+
+// writeTrainingExport writes training pairs to out and, when validationSplit
+// is positive, the held-out documents' pairs to validationFile.
+func writeTrainingExport(out io.Writer, validationFile string, validationSplit float64, projectConfig export.ProjectConfig, documents []scanner.Document) error {
+	exporter := export.NewTrainingExporter()
+	exporter.SetValidationSplit(validationSplit)
+	if projectConfig.Name != "" {
+		exporter.SetSystemPrompt(fmt.Sprintf("You answer questions about %s using its documentation.", projectConfig.Name))
+	}
+
+	var validation *exportOutput
+	if validationSplit > 0 {
+		var err error
+		validation, err = openExportOutput(validationFile)
+		if err != nil {
+			return err
 		}
-		fmt.Printf(" Exported to %s\n", outputFile)
+	}
+
+	var stats export.TrainingStats
+	var err error
+	if validation != nil {
+		stats, err = exporter.WriteTraining(out, validation, documents)
 	} else {
-		// Write to stdout
-		fmt.Println(output)
+		stats, err = exporter.WriteTraining(out, nil, documents)
+	}
+	if err != nil {
+		if validation != nil {
+			validation.Abort()
+		}
+		return err
 	}
 
+	fmt.Fprintf(os.Stderr, " Training set: %d pairs from %d documents; %d duplicate answers dropped\n", stats.TrainRecords, stats.TrainDocuments, stats.Duplicates)
+	if validation == nil {
+		return nil
+	}
+	if err := validation.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, " Validation set: %d pairs from %d documents in %s\n", stats.ValidationRecords, stats.ValidationDocuments, validationFile)
+	if stats.ValidationDocuments == 0 {
+		fmt.Fprintln(os.Stderr, " Warning: no documents fell into the validation split; raise --validation-split for small doc sets")
+	}
 	return nil
 }
+
+// writeTombstones writes the difference between the previous and the current
+// manifest to path; its removed_chunks list the chunk IDs consumers should
+// delete.
+func writeTombstones(path string, previous, current *export.Manifest) error {
+	diff := export.DiffManifests(previous, current)
+
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstones: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create tombstones directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write tombstones: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, " Incremental export: %d added or changed chunks, %d removed (tombstones in %s)\n", len(diff.AddedChunks), len(diff.RemovedChunks), path)
+	return nil
+}
+
+// writeManifest writes manifest to path. It does nothing if manifest is nil
+// or path is empty, as for incremental exports without --manifest, which
+// only use the manifest to compute tombstones.
+func writeManifest(path string, manifest *export.Manifest) error {
+	if manifest == nil || path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	if err := manifest.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, " Wrote manifest to %s\n", path)
+	return nil
+}
+
+// pushExport chunks and embeds documents and upserts them into store in
+// batches, then removes chunks of documents that no longer exist. Pruning is
+// limited to chunks pushed under the same source (llm.push.source, by
+// default the project name).
+//
+// For incremental pushes (previous is not nil) only new chunks are sent;
+// unchanged chunks, recorded in manifest, are kept in the store.
+func pushExport(exporter *export.JSONLExporter, store vectorstore.Store, documents []scanner.Document, chunkSize, chunkOverlap int, manifest, previous *export.Manifest) error {
+	fmt.Fprintf(os.Stderr, " Pushing to %s...\n", store.Name())
+
+	ctx := context.Background()
+	pusher := vectorstore.NewPusher(store, viper.GetInt("llm.push.batch_size"))
+	source := viper.GetString("llm.push.source")
+	if source == "" {
+		source = viper.GetString("project.name")
+	}
+	pusher.SetSource(source)
+	err := exporter.EachChunk(documents, chunkSize, chunkOverlap, func(doc scanner.Document, metadata export.ChunkMetadata) error {
+		return pusher.Add(ctx, vectorstore.Chunk{Metadata: metadata, Frontmatter: doc.Metadata})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	if previous != nil {
+		for id := range manifest.ChunkIDs() {
+			pusher.Keep(id)
+		}
+	}
+
+	result, err := pusher.Finish(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, " Pushed %d chunks to %s (%d stale chunks deleted)\n", result.Upserted, store.Name(), result.Deleted)
+	return nil
+}
+
+// exportOutput is the destination of an export: stdout or a file, optionally
+// gzip-compressed, behind a buffered writer.
+type exportOutput struct {
+	*bufio.Writer
+	file *os.File     // nil when writing to stdout
+	gzip *gzip.Writer // nil unless the file name ends in .gz
+}
+
+// openExportOutput opens path for writing, creating parent directories as
+// needed. An empty path selects stdout; a path ending in .gz is compressed.
+func openExportOutput(path string) (*exportOutput, error) {
+	if path == "" {
+		return &exportOutput{Writer: bufio.NewWriter(os.Stdout)}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	out := &exportOutput{file: file}
+	if strings.HasSuffix(path, ".gz") {
+		out.gzip = gzip.NewWriter(file)
+		out.Writer = bufio.NewWriter(out.gzip)
+	} else {
+		out.Writer = bufio.NewWriter(file)
+	}
+	return out, nil
+}
+
+// Close flushes all buffered data and closes the underlying file.
+func (o *exportOutput) Close() error {
+	if err := o.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if o.gzip != nil {
+		if err := o.gzip.Close(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	if o.file != nil {
+		if err := o.file.Close(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Abort discards a failed export, removing the partially written file.
+func (o *exportOutput) Abort() {
+	if o.file == nil {
+		o.Flush()
+		return
+	}
+	o.file.Close()
+	os.Remove(o.file.Name())
+}
+
+// exportEncoding returns the tokenizer encoding selected by --tokenizer or
+// llm.tokenizer, falling back to the default encoding.
+func exportEncoding(cmd *cobra.Command) string {
+	if encoding, _ := cmd.Flags().GetString("tokenizer"); encoding != "" {
+		return encoding
+	}
+	if encoding := viper.GetString("llm.tokenizer"); encoding != "" {
+		return encoding
+	}
+	return tokenizer.DefaultEncoding()
+}
+
+// newExportStrategy creates the named chunking strategy. Semantic strategies
+// use the embedder for similarity when one is configured, and pick up the
+// llm.semantic_threshold setting.
+func newExportStrategy(name string, tok tokenizer.Tokenizer, embedder embedding.Embedder) (chunking.ChunkStrategy, error) {
+	strategy, err := chunking.NewChunkStrategy(name, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	if semantic, ok := strategy.(*chunking.SemanticStrategy); ok {
+		if embedder != nil {
+			semantic.SetEmbedder(embedder)
+		}
+		if viper.IsSet("llm.semantic_threshold") {
+			semantic.SetThreshold(viper.GetFloat64("llm.semantic_threshold"))
+		}
+	}
+
+	return strategy, nil
+}
+
+// newExportEnricher creates the contextual enricher, or returns nil when
+// enrichment is not enabled by --contextual, llm.context.enabled or the
+// contextual strategy. When a chat endpoint is configured, each chunk also
+// gets a generated blurb, cached on disk per chunk.
+func newExportEnricher(cmd *cobra.Command, strategy string) (*enrich.Enricher, error) {
+	enabled, _ := cmd.Flags().GetBool("contextual")
+	if !enabled && !viper.GetBool("llm.context.enabled") && strategy != "contextual" {
+		return nil, nil
+	}
+
+	enricher := enrich.NewEnricher()
+
+	url := viper.GetString("llm.context.url")
+	if flagURL, _ := cmd.Flags().GetString("context-url"); flagURL != "" {
+		url = flagURL
+	}
+	if url == "" {
+		return enricher, nil
+	}
+
+	model := viper.GetString("llm.context.model")
+	if flagModel, _ := cmd.Flags().GetString("context-model"); flagModel != "" {
+		model = flagModel
+	}
+	if model == "" {
+		return nil, fmt.Errorf("generated context requires a model (set --context-model or llm.context.model)")
+	}
+
+	apiKey := viper.GetString("llm.context.api_key")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+
+	var completer enrich.Completer = enrich.NewHTTPCompleter(url, model, apiKey)
+	cacheDir := viper.GetString("llm.context.cache_dir")
+	if cacheDir == "" {
+		if userCacheDir, err := os.UserCacheDir(); err == nil {
+			cacheDir = filepath.Join(userCacheDir, "jot", "context")
+		}
+	}
+	if cacheDir != "" {
+		completer = enrich.NewCachedCompleter(completer, cacheDir)
+	}
+	enricher.SetCompleter(completer)
+
+	fmt.Fprintf(os.Stderr, " WARNING: generated context will send document text to %s\n", url)
+	fmt.Fprintln(os.Stderr)
+
+	return enricher, nil
+}
+
+// loadEmbeddingConfig reads the llm.embeddings section of the configuration and
+// applies any overrides from command-line flags.
+func loadEmbeddingConfig(cmd *cobra.Command) embedding.Config {
+	config := embedding.Config{
+		Provider:   viper.GetString("llm.embeddings.provider"),
+		BaseURL:    viper.GetString("llm.embeddings.url"),
+		Model:      viper.GetString("llm.embeddings.model"),
+		APIKey:     viper.GetString("llm.embeddings.api_key"),
+		BatchSize:  viper.GetInt("llm.embeddings.batch_size"),
+		Dimensions: viper.GetInt("llm.embeddings.dimensions"),
+		CacheDir:   viper.GetString("llm.embeddings.cache_dir"),
+	}
+
+	// Override with command flags
+	if provider, _ := cmd.Flags().GetString("embeddings-provider"); provider != "" {
+		config.Provider = provider
+	}
+	if url, _ := cmd.Flags().GetString("embeddings-url"); url != "" {
+		config.BaseURL = url
+	}
+	if model, _ := cmd.Flags().GetString("embeddings-model"); model != "" {
+		config.Model = model
+	}
+
+	// Defaults
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if config.CacheDir == "" {
+		if cacheDir, err := os.UserCacheDir(); err == nil {
+			config.CacheDir = filepath.Join(cacheDir, "jot", "embeddings")
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/onedusk/jot/internal/export"
+	"github.com/spf13/viper"
+)
+
+// setupExportTest writes files into a docs directory, points the input paths
+// of a fresh configuration at it and returns the temporary directory.
+func setupExportTest(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	docsDir := filepath.Join(tmpDir, "docs")
+	writeExportDocs(t, docsDir, files)
+
+	viper.Reset()
+	viper.Set("input.paths", []string{docsDir})
+	t.Cleanup(viper.Reset)
+	return tmpDir
+}
+
+// writeExportDocs writes files, keyed by slash-separated path, into docsDir.
+func writeExportDocs(t *testing.T, docsDir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(docsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// runExportWith runs the export command with the given flags and restores
+// the flag defaults afterwards.
+func runExportWith(t *testing.T, flags map[string]string) error {
+	t.Helper()
+	flags["tokenizer"] = "heuristic"
+	defer func() {
+		for name := range flags {
+			flag := exportCmd.Flags().Lookup(name)
+			flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		}
+	}()
+
+	for name, value := range flags {
+		if err := exportCmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("Failed to set --%s: %v", name, err)
+		}
+	}
+	return runExport(exportCmd, nil)
+}
+
+// TestExport_SinceWithoutManifest verifies that an incremental export
+// without --manifest writes the delta and tombstones and no manifest.
+func TestExport_SinceWithoutManifest(t *testing.T) {
+	tmpDir := setupExportTest(t, map[string]string{
+		"guide.md": "# Guide\n\nIntro.\n\n## Install\n\nRun it.\n",
+		"faq.md":   "# FAQ\n\nAsk.\n",
+	})
+	manifestFile := filepath.Join(tmpDir, "docs.manifest.json")
+	if err := runExportWith(t, map[string]string{
+		"format":   "jsonl",
+		"strategy": "markdown",
+		"output":   filepath.Join(tmpDir, "docs.jsonl"),
+		"manifest": manifestFile,
+	}); err != nil {
+		t.Fatalf("Full export failed: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(tmpDir, "docs", "faq.md")); err != nil {
+		t.Fatal(err)
+	}
+	deltaFile := filepath.Join(tmpDir, "delta.jsonl")
+	if err := runExportWith(t, map[string]string{
+		"format":   "jsonl",
+		"strategy": "markdown",
+		"output":   deltaFile,
+		"since":    manifestFile,
+	}); err != nil {
+		t.Fatalf("Incremental export without --manifest failed: %v", err)
+	}
+
+	if _, err := os.Stat(deltaFile); err != nil {
+		t.Errorf("delta.jsonl was not created: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "delta.tombstones.json"))
+	if err != nil {
+		t.Fatalf("Failed to read tombstones: %v", err)
+	}
+	var diff struct {
+		RemovedChunks []string `json:"removed_chunks"`
+	}
+	if err := json.Unmarshal(data, &diff); err != nil || len(diff.RemovedChunks) != 1 {
+		t.Errorf("tombstones = %s, want the FAQ chunk removed", data)
+	}
+}
+
+// TestExport_PushSinceWithoutManifest verifies that an incremental push
+// without --manifest upserts the new chunks and succeeds.
+func TestExport_PushSinceWithoutManifest(t *testing.T) {
+	tmpDir := setupExportTest(t, map[string]string{
+		"guide.md": "# Guide\n\nIntro.\n\n## Install\n\nRun it.\n",
+	})
+	manifestFile := filepath.Join(tmpDir, "docs.manifest.json")
+	if err := runExportWith(t, map[string]string{
+		"format":   "jsonl",
+		"strategy": "markdown",
+		"output":   filepath.Join(tmpDir, "docs.jsonl"),
+		"manifest": manifestFile,
+	}); err != nil {
+		t.Fatalf("Full export failed: %v", err)
+	}
+
+	writeExportDocs(t, filepath.Join(tmpDir, "docs"), map[string]string{
+		"news.md": "# News\n\nNothing yet.\n",
+	})
+
+	// A minimal Qdrant API that counts upserted points
+	var mu sync.Mutex
+	var upserted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/points"):
+			var body struct {
+				Points []json.RawMessage `json:"points"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			upserted += len(body.Points)
+			mu.Unlock()
+		case strings.HasSuffix(r.URL.Path, "/points/scroll"):
+			w.Write([]byte(`{"result":{"points":[],"next_page_offset":null}}`))
+			return
+		}
+		w.Write([]byte(`{"result":{}}`))
+	}))
+	defer server.Close()
+
+	if err := runExportWith(t, map[string]string{
+		"strategy":            "markdown",
+		"push":                "qdrant://" + strings.TrimPrefix(server.URL, "http://") + "/docs",
+		"include-embeddings":  "true",
+		"embeddings-provider": "hash",
+		"since":               manifestFile,
+	}); err != nil {
+		t.Fatalf("Incremental push without --manifest failed: %v", err)
+	}
+
+	if upserted != 1 {
+		t.Errorf("upserted %d points, want the news chunk only", upserted)
+	}
+}
+
+// TestExport_LLMFormat verifies that --format llm writes an export that
+// validates against the published llm schema.
+func TestExport_LLMFormat(t *testing.T) {
+	tmpDir := setupExportTest(t, map[string]string{
+		"guide.md": "# Guide\n\nIntro.\n\n## Install\n\n```sh\ngo install ./cmd/jot\n```\n",
+	})
+	outputFile := filepath.Join(tmpDir, "docs.llm.json")
+	if err := runExportWith(t, map[string]string{
+		"format": "llm",
+		"output": outputFile,
+	}); err != nil {
+		t.Fatalf("LLM export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if err := export.ValidateExport("llm", data); err != nil {
+		t.Errorf("LLM export does not match its schema: %v", err)
+	}
+	if !strings.Contains(string(data), `"version": "`+export.LLMFormatVersion+`"`) {
+		t.Errorf("LLM export does not carry format version %s", export.LLMFormatVersion)
+	}
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/onedusk/jot/internal/tokenizer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -73,4 +74,12 @@ func initConfig() {
 			fmt.Println("Using config file:", viper.ConfigFileUsed())
 		}
 	}
+
+	// Tokenizer data for air-gapped environments
+	if dir := viper.GetString("llm.tokenizer_data"); dir != "" {
+		tokenizer.SetDataDir(dir)
+	}
+	if viper.GetBool("llm.offline") {
+		tokenizer.SetOffline(true)
+	}
 }
@@ -0,0 +1,125 @@
+// Package main is the entry point for the Jot CLI application.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/jsonschema"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd prints the published JSON Schema of an export format.
+var schemaCmd = &cobra.Command{
+	Use:   "schema <json|jsonl|llm>",
+	Short: "Print the JSON Schema of an export format",
+	Long: `Print the JSON Schema of an export format, so that downstream consumers
+can validate exports or generate types from them.
+
+Formats:
+  - json:  jot export --format json (and --format yaml, which has the same structure)
+  - jsonl: one line of jot export --format jsonl with the default jot target
+  - llm:   the LLM export format
+
+Each schema's $id ends in its format version, which changes whenever the
+format does.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: export.SchemaFormats(),
+	RunE:      runSchema,
+}
+
+// validateExportCmd checks an export file against the schema of its format.
+var validateExportCmd = &cobra.Command{
+	Use:   "validate-export <file>",
+	Short: "Validate an export file against its JSON Schema",
+	Long: `Validate an export file against the JSON Schema of its format (see jot schema).
+
+The format is detected from the file name and content (.jsonl, .yaml/.yml,
+otherwise JSON, where a semantic index marks the llm format) unless --format
+is given. Files ending in .gz are decompressed.
+
+Examples:
+  jot validate-export docs.json
+  jot validate-export chunks.jsonl.gz
+  jot validate-export --format llm export.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runValidateExport,
+}
+
+func init() {
+	validateExportCmd.Flags().StringP("format", "f", "", "export format: json, yaml, jsonl, llm (default: detected)")
+
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(validateExportCmd)
+}
+
+// runSchema executes the logic for the schema command.
+func runSchema(cmd *cobra.Command, args []string) error {
+	data, err := export.Schema(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// runValidateExport executes the logic for the validate-export command.
+func runValidateExport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	data, err := readExportFile(path)
+	if err != nil {
+		return err
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = export.DetectFormat(path, data)
+	}
+	version, err := export.FormatVersion(format)
+	if err != nil {
+		return err
+	}
+
+	if err := export.ValidateExport(format, data); err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return err
+		}
+		for _, violation := range validationErr.Violations {
+			fmt.Fprintf(os.Stderr, "  %s\n", violation)
+		}
+		return fmt.Errorf("%s does not match the %s schema (version %s): %d violations", path, format, version, len(validationErr.Violations))
+	}
+
+	fmt.Printf(" %s is a valid %s export (schema version %s)\n", path, format, version)
+	return nil
+}
+
+// readExportFile reads an export, decompressing it when the name ends in .gz.
+func readExportFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress export: %w", err)
+	}
+	defer reader.Close()
+
+	data, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress export: %w", err)
+	}
+	return data, nil
+}
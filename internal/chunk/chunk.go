@@ -0,0 +1,26 @@
+// Package chunk defines the Chunk type shared by the chunking strategies and
+// the exporters, so that neither package has to import the other's types.
+package chunk
+
+// Chunk represents a segment of text from a document, typically sized for
+// tasks like vector embedding or processing within a model's context window.
+// StartPos and EndPos are the byte range of the chunk in its document; Text is
+// that range of the source, except for synthesized pieces such as the markdown
+// strategy's split code blocks and tables, whose repeated fence or header lies
+// outside it.
+type Chunk struct {
+	ID         string    `json:"id" yaml:"id"`
+	Text       string    `json:"text" yaml:"text"`
+	StartPos   int       `json:"start_pos" yaml:"start_pos"`
+	EndPos     int       `json:"end_pos" yaml:"end_pos"`
+	TokenCount int       `json:"token_count" yaml:"token_count"`
+	Vector     []float32 `json:"vector,omitempty" yaml:"vector,omitempty"`
+
+	// HeadingPath lists the enclosing markdown headings, outermost first.
+	// Only set by strategies that track document structure.
+	HeadingPath []string `json:"heading_path,omitempty" yaml:"heading_path,omitempty"`
+
+	// Context is an optional preamble situating the chunk within its document,
+	// set by contextual enrichment.
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+}
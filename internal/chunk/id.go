@@ -0,0 +1,41 @@
+package chunk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// idHashLength is the number of hex digits of the content hash in chunk IDs.
+const idHashLength = 16
+
+// Normalize returns the form of text that chunk IDs are derived from:
+// whitespace runs collapse to a single space and leading and trailing
+// whitespace is removed, so reflowing a paragraph does not change its ID.
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// ContentID returns the ID of a chunk of document docID with the given text.
+// occurrence numbers chunks with identical normalized text within the
+// document (0 for the first), keeping their IDs distinct.
+//
+// The ID is "<docID>-<hash>", where the hash covers the normalized text and
+// the occurrence. It does not depend on the chunk's offset or index, so
+// editing one part of a document leaves the IDs of unchanged chunks intact.
+func ContentID(docID, text string, occurrence int) string {
+	sum := sha256.Sum256([]byte(Normalize(text) + "\x00" + strconv.Itoa(occurrence)))
+	return docID + "-" + hex.EncodeToString(sum[:])[:idHashLength]
+}
+
+// AssignIDs sets the ID of every chunk of document docID using ContentID.
+// Chunks must be in document order so that occurrences are numbered stably.
+func AssignIDs(docID string, chunks []Chunk) {
+	seen := make(map[string]int, len(chunks))
+	for i := range chunks {
+		normalized := Normalize(chunks[i].Text)
+		chunks[i].ID = ContentID(docID, normalized, seen[normalized])
+		seen[normalized]++
+	}
+}
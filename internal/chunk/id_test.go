@@ -0,0 +1,42 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAssignIDs tests that chunk IDs depend on content, not position.
+func TestAssignIDs(t *testing.T) {
+	original := []Chunk{{Text: "Intro."}, {Text: "Install it."}, {Text: "Use it."}}
+	edited := []Chunk{{Text: "Intro."}, {Text: "A new paragraph."}, {Text: "Install  it.\n"}, {Text: "Use it."}}
+	duplicated := []Chunk{{Text: "Same."}, {Text: "Same."}}
+
+	AssignIDs("doc", original)
+	AssignIDs("doc", edited)
+	AssignIDs("doc", duplicated)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"unchanged before the insertion", edited[0].ID, original[0].ID},
+		{"reflowed text", edited[2].ID, original[1].ID},
+		{"unchanged after the insertion", edited[3].ID, original[2].ID},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: ID = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if duplicated[0].ID == duplicated[1].ID {
+		t.Errorf("identical chunks share ID %s", duplicated[0].ID)
+	}
+	if !strings.HasPrefix(original[0].ID, "doc-") || len(original[0].ID) != len("doc-")+idHashLength {
+		t.Errorf("ID = %s, want doc-<%d hex digits>", original[0].ID, idHashLength)
+	}
+	if ContentID("other", "Intro.", 0) == original[0].ID {
+		t.Error("ContentID() ignores the document ID")
+	}
+}
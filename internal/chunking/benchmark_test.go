@@ -10,9 +10,10 @@ import (
 
 // Benchmark setup helpers
 var (
-	benchDoc     scanner.Document
-	benchTok     tokenizer.Tokenizer
-	benchContent string
+	benchDoc      scanner.Document
+	benchLargeDoc scanner.Document
+	benchTok      tokenizer.Tokenizer
+	benchContent  string
 )
 
 func init() {
@@ -26,10 +27,18 @@ func init() {
 		Content: []byte(benchContent),
 	}
 
-	var err error
-	benchTok, err = tokenizer.NewTokenizer()
-	if err != nil {
-		panic(err)
+	// Approximately 1MB, where per-probe re-tokenization dominates
+	benchLargeDoc = scanner.Document{
+		ID:      "bench-doc-large",
+		Content: []byte(strings.Repeat(benchContent, 25)),
+	}
+
+	// Benchmark cl100k_base when its data is available, and the heuristic
+	// tokenizer otherwise, so that the package's tests also run offline
+	if tok, err := tokenizer.NewTokenizer(); err == nil {
+		benchTok = tok
+	} else {
+		benchTok = tokenizer.NewHeuristicTokenizer()
 	}
 }
 
@@ -48,6 +57,48 @@ func BenchmarkFixedStrategy(b *testing.B) {
 	}
 }
 
+// BenchmarkFixedStrategy_Large benchmarks the FixedSizeStrategy on a large document.
+func BenchmarkFixedStrategy_Large(b *testing.B) {
+	strategy := NewFixedSizeStrategy(benchTok)
+
+	b.SetBytes(int64(len(benchLargeDoc.Content)))
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := strategy.Chunk(benchLargeDoc, 512, 128)
+		if err != nil {
+			b.Fatalf("FixedStrategy.Chunk() error = %v", err)
+		}
+	}
+}
+
+// BenchmarkFixedStrategy_LargeBinarySearch benchmarks the previous approach of
+// binary-searching character positions and re-tokenizing every probe, as a
+// baseline for BenchmarkFixedStrategy_Large.
+func BenchmarkFixedStrategy_LargeBinarySearch(b *testing.B) {
+	content := string(benchLargeDoc.Content)
+
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for start := 0; start < len(content); {
+			left, right := start, len(content)
+			for left < right {
+				mid := (left + right + 1) / 2
+				if benchTok.Count(content[start:mid]) <= 512 {
+					left = mid
+				} else {
+					right = mid - 1
+				}
+			}
+			start = left
+		}
+	}
+}
+
 // BenchmarkHeaderStrategy benchmarks the MarkdownHeaderStrategy.
 func BenchmarkHeaderStrategy(b *testing.B) {
 	// Add headers to content for more realistic test
@@ -96,7 +147,23 @@ func BenchmarkRecursiveStrategy(b *testing.B) {
 	}
 }
 
-// BenchmarkSemanticStrategy benchmarks the SemanticStrategy (currently a fallback).
+// BenchmarkRecursiveStrategy_Large benchmarks the RecursiveStrategy on a large document.
+func BenchmarkRecursiveStrategy_Large(b *testing.B) {
+	strategy := NewRecursiveStrategy(benchTok)
+
+	b.SetBytes(int64(len(benchLargeDoc.Content)))
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := strategy.Chunk(benchLargeDoc, 512, 128)
+		if err != nil {
+			b.Fatalf("RecursiveStrategy.Chunk() error = %v", err)
+		}
+	}
+}
+
+// BenchmarkSemanticStrategy benchmarks the SemanticStrategy using lexical similarity.
 func BenchmarkSemanticStrategy(b *testing.B) {
 	strategy := NewSemanticStrategy(benchTok)
 
@@ -10,8 +10,10 @@ import (
 // Supported strategies:
 //   - "fixed": Fixed-size token-based chunking with word boundary preservation
 //   - "headers": Markdown header-based chunking (splits at # headers)
+//   - "markdown": Structure-aware chunking that keeps code blocks, tables, lists and quotes intact
 //   - "recursive": Hierarchical text splitting (paragraph -> line -> space -> char)
-//   - "semantic": Semantic boundary detection (currently stub, falls back to fixed)
+//   - "semantic": Topic-shift boundary detection (lexical similarity or embeddings)
+//   - "contextual": Alias for "semantic"
 //
 // Parameters:
 //   - name: The strategy name (case-insensitive)
@@ -26,12 +28,14 @@ func NewChunkStrategy(name string, tok tokenizer.Tokenizer) (ChunkStrategy, erro
 		return NewFixedSizeStrategy(tok), nil
 	case "headers", "markdown-headers":
 		return NewMarkdownHeaderStrategy(tok), nil
+	case "markdown":
+		return NewMarkdownStrategy(tok), nil
 	case "recursive":
 		return NewRecursiveStrategy(tok), nil
-	case "semantic":
+	case "semantic", "contextual":
 		return NewSemanticStrategy(tok), nil
 	default:
-		return nil, fmt.Errorf("unknown chunking strategy: %s (supported: fixed, headers, recursive, semantic)", name)
+		return nil, fmt.Errorf("unknown chunking strategy: %s (supported: fixed, headers, markdown-headers, markdown, recursive, semantic, contextual)", name)
 	}
 }
 
@@ -41,8 +45,10 @@ func AvailableStrategies() []string {
 		"fixed",
 		"headers",
 		"markdown-headers", // alias for "headers"
+		"markdown",
 		"recursive",
 		"semantic",
+		"contextual", // alias for "semantic"
 	}
 }
 
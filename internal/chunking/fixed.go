@@ -1,15 +1,19 @@
 package chunking
 
 import (
-	"fmt"
-
-	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/chunk"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
 
+// maxWordSearch is how far back, in bytes, a chunk end may move to avoid
+// splitting a word.
+const maxWordSearch = 100
+
 // FixedSizeStrategy implements token-based fixed-size chunking.
 // It splits documents into chunks of approximately maxTokens size with specified overlap.
+// The document is tokenized once and chunks are sliced by token ranges, so
+// chunking is linear in the document size.
 type FixedSizeStrategy struct {
 	tokenizer tokenizer.Tokenizer
 }
@@ -22,119 +26,72 @@ func NewFixedSizeStrategy(tok tokenizer.Tokenizer) *FixedSizeStrategy {
 }
 
 // Chunk implements the ChunkStrategy interface for fixed-size chunking.
-func (s *FixedSizeStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]export.Chunk, error) {
+// Token counts are taken from the tokenization of the whole document; a chunk
+// tokenized on its own may differ by a token at its edges.
+func (s *FixedSizeStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
 	content := string(doc.Content)
+	bounds := tokenBounds(s.tokenizer, content)
+	numTokens := len(bounds) - 1
 
 	// Check if entire content fits within token limit
-	if s.tokenizer.Count(content) <= maxTokens {
-		return []export.Chunk{
+	if numTokens <= maxTokens {
+		chunks := []chunk.Chunk{
 			{
-				ID:         fmt.Sprintf("%s-chunk-0", doc.ID),
 				Text:       content,
 				StartPos:   0,
 				EndPos:     len(content),
-				TokenCount: s.tokenizer.Count(content),
+				TokenCount: numTokens,
 			},
-		}, nil
+		}
+		chunk.AssignIDs(doc.ID, chunks)
+		return chunks, nil
 	}
 
-	chunks := make([]export.Chunk, 0)
-	chunkID := 0
-	startPos := 0
-
-	for startPos < len(content) {
-		// Find end position where token count reaches maxTokens
-		endPos := len(content)
-		currentText := content[startPos:endPos]
-
-		// If current text exceeds maxTokens, find the right boundary
-		if s.tokenizer.Count(currentText) > maxTokens {
-			// Binary search for the right character position
-			left, right := startPos, len(content)
-
-			for left < right {
-				mid := (left + right + 1) / 2
-				testText := content[startPos:mid]
-
-				if s.tokenizer.Count(testText) <= maxTokens {
-					left = mid
-				} else {
-					right = mid - 1
-				}
-			}
-
-			endPos = left
+	chunks := make([]chunk.Chunk, 0)
+	startTok := 0
 
+	for startTok < numTokens {
+		endTok := startTok + maxTokens
+		if endTok >= numTokens {
+			endTok = numTokens
+		} else {
 			// Try to break at word boundary to avoid splitting words
-			if endPos < len(content) && endPos > startPos {
-				// Look backwards for space or newline within reasonable range
-				searchStart := maxInt(startPos, endPos-100)
-				for i := endPos; i > searchStart; i-- {
-					if content[i-1] == ' ' || content[i-1] == '\n' {
-						endPos = i
-						break
-					}
+			for k := endTok; k > startTok && bounds[endTok]-bounds[k] <= maxWordSearch; k-- {
+				if bounds[k] > bounds[startTok] && wordBreakBefore(content, bounds[k]) {
+					endTok = k
+					break
 				}
 			}
 		}
 
-		// Extract chunk text
+		// Ensure we make progress when boundaries were merged to keep UTF-8 intact
+		for endTok < numTokens && bounds[endTok] <= bounds[startTok] {
+			endTok++
+		}
+
+		startPos, endPos := bounds[startTok], bounds[endTok]
 		chunkText := content[startPos:endPos]
 
-		chunks = append(chunks, export.Chunk{
-			ID:         fmt.Sprintf("%s-chunk-%d", doc.ID, chunkID),
+		chunks = append(chunks, chunk.Chunk{
 			Text:       chunkText,
 			StartPos:   startPos,
 			EndPos:     endPos,
-			TokenCount: s.tokenizer.Count(chunkText),
+			TokenCount: endTok - startTok,
 		})
 
-		chunkID++
-
-		// Move to next chunk with overlap
-		if endPos >= len(content) {
+		if endTok >= numTokens {
 			break
 		}
 
-		// Calculate next start position with token-based overlap
-		if overlapTokens > 0 {
-			// Binary search for overlap position
-			targetTokens := s.tokenizer.Count(chunkText) - overlapTokens
-			if targetTokens <= 0 {
-				// If overlap is larger than chunk, just move forward minimally
-				startPos = endPos
-				continue
-			}
-
-			left, right := startPos, endPos
-			for left < right {
-				mid := (left + right + 1) / 2
-				testText := content[startPos:mid]
-
-				if s.tokenizer.Count(testText) <= targetTokens {
-					left = mid
-				} else {
-					right = mid - 1
-				}
-			}
-
-			startPos = left
+		// Move to next chunk with token-based overlap; if the overlap is
+		// larger than the chunk, just move forward
+		if overlapTokens > 0 && endTok-overlapTokens > startTok {
+			startTok = endTok - overlapTokens
 		} else {
-			startPos = endPos
-		}
-
-		// Ensure we make progress
-		if startPos >= endPos {
-			startPos = endPos
+			startTok = endTok
 		}
 	}
 
+	chunk.AssignIDs(doc.ID, chunks)
 	return chunks, nil
 }
-
-func maxInt(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
@@ -1,11 +1,10 @@
 package chunking
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
-	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/chunk"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
@@ -27,7 +26,7 @@ func NewMarkdownHeaderStrategy(tok tokenizer.Tokenizer) *MarkdownHeaderStrategy
 }
 
 // Chunk implements the ChunkStrategy interface for markdown header-based chunking.
-func (s *MarkdownHeaderStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]export.Chunk, error) {
+func (s *MarkdownHeaderStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
 	content := string(doc.Content)
 	lines := strings.Split(content, "\n")
 
@@ -76,8 +75,7 @@ func (s *MarkdownHeaderStrategy) Chunk(doc scanner.Document, maxTokens, overlapT
 	}
 
 	// Convert sections to chunks, splitting if they exceed maxTokens
-	chunks := make([]export.Chunk, 0)
-	chunkID := 0
+	chunks := make([]chunk.Chunk, 0)
 	charOffset := 0
 
 	for _, section := range sections {
@@ -85,14 +83,12 @@ func (s *MarkdownHeaderStrategy) Chunk(doc scanner.Document, maxTokens, overlapT
 
 		if sectionTokens <= maxTokens {
 			// Section fits within token limit
-			chunks = append(chunks, export.Chunk{
-				ID:         fmt.Sprintf("%s-chunk-%d", doc.ID, chunkID),
+			chunks = append(chunks, chunk.Chunk{
 				Text:       section.text,
 				StartPos:   charOffset,
 				EndPos:     charOffset + len(section.text),
 				TokenCount: sectionTokens,
 			})
-			chunkID++
 			charOffset += len(section.text) + 1 // +1 for newline
 		} else {
 			// Section exceeds limit, fall back to fixed-size chunking for this section
@@ -106,17 +102,16 @@ func (s *MarkdownHeaderStrategy) Chunk(doc scanner.Document, maxTokens, overlapT
 				return nil, err
 			}
 
-			// Adjust chunk IDs and positions
+			// Adjust positions to the whole document
 			for _, chunk := range sectionChunks {
-				chunk.ID = fmt.Sprintf("%s-chunk-%d", doc.ID, chunkID)
 				chunk.StartPos += charOffset
 				chunk.EndPos += charOffset
 				chunks = append(chunks, chunk)
-				chunkID++
 			}
 			charOffset += len(section.text) + 1
 		}
 	}
 
+	chunk.AssignIDs(doc.ID, chunks)
 	return chunks, nil
 }
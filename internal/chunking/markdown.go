@@ -0,0 +1,382 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// blockKind identifies the markdown construct a block was parsed from.
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockTable
+	blockList
+	blockQuote
+)
+
+// block is a top-level markdown construct spanning content[start:end].
+type block struct {
+	kind  blockKind
+	start int
+	end   int
+	lines []span   // Line spans within the block, used when splitting
+	path  []string // Heading path in effect at this block
+}
+
+// span is a half-open byte range of the document content.
+type span struct {
+	start int
+	end   int
+}
+
+var (
+	atxHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.+?)\s*#*\s*$`)
+	listItemRegex   = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+	tableSepRegex   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// MarkdownStrategy implements structure-aware markdown chunking.
+// Fenced code blocks, tables, lists and blockquotes are treated as atomic
+// units and are only split when a single unit exceeds the token limit:
+// code blocks are split by lines with the fence repeated on every piece,
+// tables by rows with the header repeated, and lists at item boundaries.
+// Those code and table pieces are synthesized: StartPos and EndPos cover only
+// the lines taken from the block, not the repeated fence or header.
+// Every chunk starts at a heading when one is available and carries the
+// heading path (H1 > H2 > H3) in effect at its first block.
+type MarkdownStrategy struct {
+	tokenizer tokenizer.Tokenizer
+}
+
+// NewMarkdownStrategy creates a new MarkdownStrategy with the given tokenizer.
+func NewMarkdownStrategy(tok tokenizer.Tokenizer) *MarkdownStrategy {
+	return &MarkdownStrategy{
+		tokenizer: tok,
+	}
+}
+
+// Chunk implements the ChunkStrategy interface for structure-aware chunking.
+// Overlap is only applied when a plain paragraph has to be split by the
+// fixed-size fallback; structural units are never duplicated across chunks.
+func (s *MarkdownStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
+	content := string(doc.Content)
+	blocks := parseBlocks(content)
+
+	chunks := make([]chunk.Chunk, 0)
+	var current []block
+
+	emit := func(text string, start, end int, path []string) {
+		chunks = append(chunks, chunk.Chunk{
+			Text:        text,
+			StartPos:    start,
+			EndPos:      end,
+			TokenCount:  s.tokenizer.Count(text),
+			HeadingPath: path,
+		})
+	}
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		start, end := current[0].start, current[len(current)-1].end
+		emit(content[start:end], start, end, current[0].path)
+		current = nil
+	}
+
+	for _, b := range blocks {
+		// Headings always open a new chunk so the heading path stays accurate
+		if b.kind == blockHeading {
+			flush()
+			current = []block{b}
+			continue
+		}
+
+		if len(current) > 0 && s.tokenizer.Count(content[current[0].start:b.end]) <= maxTokens {
+			current = append(current, b)
+			continue
+		}
+
+		if s.tokenizer.Count(content[b.start:b.end]) <= maxTokens {
+			flush()
+			current = []block{b}
+			continue
+		}
+
+		flush()
+		pieces, err := s.splitBlock(doc, content, b, maxTokens, overlapTokens)
+		if err != nil {
+			return nil, err
+		}
+		for _, piece := range pieces {
+			emit(piece.Text, piece.StartPos, piece.EndPos, b.path)
+		}
+	}
+	flush()
+
+	chunk.AssignIDs(doc.ID, chunks)
+	return chunks, nil
+}
+
+// splitBlock splits a block that does not fit within maxTokens on its own.
+func (s *MarkdownStrategy) splitBlock(doc scanner.Document, content string, b block, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
+	switch b.kind {
+	case blockCode:
+		// Repeat the opening and closing fence on every piece
+		open := b.lines[0]
+		body := b.lines[1:]
+		var suffix string
+		if len(body) > 0 && isFenceClose(content[open.start:open.end], content[body[len(body)-1].start:body[len(body)-1].end]) {
+			suffix = "\n" + content[body[len(body)-1].start:body[len(body)-1].end]
+			body = body[:len(body)-1]
+		} else {
+			suffix = "\n" + strings.TrimSpace(fenceMarker(content[open.start:open.end]))
+		}
+		return s.packUnits(content, body, content[open.start:open.end]+"\n", suffix, maxTokens), nil
+
+	case blockTable:
+		// Repeat the header row and delimiter row on every piece
+		if len(b.lines) > 2 && tableSepRegex.MatchString(content[b.lines[1].start:b.lines[1].end]) {
+			header := content[b.lines[0].start:b.lines[1].end] + "\n"
+			return s.packUnits(content, b.lines[2:], header, "", maxTokens), nil
+		}
+		return s.packUnits(content, b.lines, "", "", maxTokens), nil
+
+	case blockList:
+		return s.packUnits(content, listItems(content, b.lines), "", "", maxTokens), nil
+
+	case blockQuote:
+		return s.packUnits(content, b.lines, "", "", maxTokens), nil
+
+	default:
+		// Plain paragraphs fall back to fixed-size chunking
+		tempDoc := scanner.Document{
+			ID:      doc.ID,
+			Content: []byte(content[b.start:b.end]),
+		}
+		pieces, err := NewFixedSizeStrategy(s.tokenizer).Chunk(tempDoc, maxTokens, overlapTokens)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pieces {
+			pieces[i].StartPos += b.start
+			pieces[i].EndPos += b.start
+		}
+		return pieces, nil
+	}
+}
+
+// packUnits greedily groups consecutive units into pieces of at most
+// maxTokens, wrapping each piece in prefix and suffix. A unit that does not
+// fit on its own is emitted alone rather than being cut. The positions of a
+// piece span its units only, so prefix and suffix lie outside them.
+func (s *MarkdownStrategy) packUnits(content string, units []span, prefix, suffix string, maxTokens int) []chunk.Chunk {
+	pieces := make([]chunk.Chunk, 0)
+	first := -1
+
+	build := func(from, to int) string {
+		return prefix + content[units[from].start:units[to].end] + suffix
+	}
+
+	for i := range units {
+		if first < 0 {
+			first = i
+			continue
+		}
+		if s.tokenizer.Count(build(first, i)) > maxTokens {
+			pieces = append(pieces, chunk.Chunk{
+				Text:     build(first, i-1),
+				StartPos: units[first].start,
+				EndPos:   units[i-1].end,
+			})
+			first = i
+		}
+	}
+	if first >= 0 {
+		pieces = append(pieces, chunk.Chunk{
+			Text:     build(first, len(units)-1),
+			StartPos: units[first].start,
+			EndPos:   units[len(units)-1].end,
+		})
+	}
+
+	return pieces
+}
+
+// parseBlocks splits markdown content into top-level blocks. Blank lines
+// separate blocks and are not part of any block.
+func parseBlocks(content string) []block {
+	lines := lineSpans(content)
+	text := func(i int) string { return content[lines[i].start:lines[i].end] }
+
+	var blocks []block
+	var path []string
+	var levels []int
+
+	for i := 0; i < len(lines); {
+		line := text(i)
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+
+		start := i
+		var kind blockKind
+
+		switch {
+		case fenceMarker(line) != "":
+			kind = blockCode
+			i++
+			for i < len(lines) {
+				closed := isFenceClose(line, text(i))
+				i++
+				if closed {
+					break
+				}
+			}
+
+		case atxHeadingRegex.MatchString(line):
+			kind = blockHeading
+			m := atxHeadingRegex.FindStringSubmatch(line)
+			level := len(m[1])
+			for len(levels) > 0 && levels[len(levels)-1] >= level {
+				levels = levels[:len(levels)-1]
+				path = path[:len(path)-1]
+			}
+			levels = append(levels, level)
+			path = append(path, m[2])
+			i++
+
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			kind = blockTable
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(text(i)), "|") {
+				i++
+			}
+
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			kind = blockQuote
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(text(i)), ">") {
+				i++
+			}
+
+		case listItemRegex.MatchString(line):
+			kind = blockList
+			i++
+			for i < len(lines) {
+				next := text(i)
+				if strings.TrimSpace(next) == "" {
+					// A blank line continues the list only if more items or
+					// indented content follow
+					j := i
+					for j < len(lines) && strings.TrimSpace(text(j)) == "" {
+						j++
+					}
+					if j < len(lines) && (listItemRegex.MatchString(text(j)) || isIndented(text(j))) {
+						i = j
+						continue
+					}
+					break
+				}
+				if !listItemRegex.MatchString(next) && !isIndented(next) && startsBlock(next) {
+					break
+				}
+				i++
+			}
+
+		default:
+			kind = blockParagraph
+			i++
+			for i < len(lines) && strings.TrimSpace(text(i)) != "" && !startsBlock(text(i)) && !listItemRegex.MatchString(text(i)) {
+				i++
+			}
+		}
+
+		blocks = append(blocks, block{
+			kind:  kind,
+			start: lines[start].start,
+			end:   lines[i-1].end,
+			lines: lines[start:i],
+			path:  append([]string(nil), path...),
+		})
+	}
+
+	return blocks
+}
+
+// lineSpans returns the span of every line in content, excluding newlines.
+func lineSpans(content string) []span {
+	var spans []span
+	start := 0
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			spans = append(spans, span{start, i})
+			start = i + 1
+		}
+	}
+	if start < len(content) {
+		spans = append(spans, span{start, len(content)})
+	}
+	return spans
+}
+
+// listItems groups the lines of a list block into item spans.
+func listItems(content string, lines []span) []span {
+	var items []span
+	for _, line := range lines {
+		if len(items) == 0 || listItemRegex.MatchString(content[line.start:line.end]) {
+			items = append(items, line)
+			continue
+		}
+		items[len(items)-1].end = line.end
+	}
+	return items
+}
+
+// startsBlock reports whether line begins a block other than a paragraph or
+// list, which interrupts a running paragraph or list.
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return fenceMarker(line) != "" ||
+		atxHeadingRegex.MatchString(line) ||
+		strings.HasPrefix(trimmed, "|") ||
+		strings.HasPrefix(trimmed, ">")
+}
+
+// fenceMarker returns the fence characters (``` or ~~~, possibly longer) if
+// line opens a fenced code block, or "" otherwise.
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == c {
+			n++
+		}
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// isFenceClose reports whether line closes the code block opened by open.
+func isFenceClose(open, line string) bool {
+	marker := fenceMarker(open)
+	closing := fenceMarker(line)
+	return closing != "" && closing[0] == marker[0] && len(closing) >= len(marker) &&
+		strings.TrimSpace(line) == closing
+}
+
+// isIndented reports whether line starts with a space or tab.
+func isIndented(line string) bool {
+	return len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+}
@@ -0,0 +1,42 @@
+package chunking
+
+import (
+	"unicode/utf8"
+
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// tokenBounds tokenizes content once and returns the byte offset of every
+// token boundary: bounds[i] is where token i starts and the final element is
+// len(content), so token range [a, b) spans content[bounds[a]:bounds[b]].
+// Boundaries that fall inside a multi-byte UTF-8 sequence are moved back to
+// the start of that character, which keeps slices valid UTF-8 at the cost of
+// occasionally merging two tokens.
+func tokenBounds(tok tokenizer.Tokenizer, content string) []int {
+	offsets := tok.Offsets(content)
+	bounds := make([]int, 0, len(offsets)+1)
+
+	for _, offset := range offsets {
+		for offset > 0 && offset < len(content) && !utf8.RuneStart(content[offset]) {
+			offset--
+		}
+		bounds = append(bounds, offset)
+	}
+	bounds = append(bounds, len(content))
+
+	return bounds
+}
+
+// wordBreakBefore reports whether a chunk may end at byte offset pos without
+// splitting a word: pos is at the end of content or adjacent to whitespace.
+func wordBreakBefore(content string, pos int) bool {
+	if pos <= 0 || pos >= len(content) {
+		return true
+	}
+	return isBreakByte(content[pos]) || isBreakByte(content[pos-1])
+}
+
+// isBreakByte reports whether b is a space or newline.
+func isBreakByte(b byte) bool {
+	return b == ' ' || b == '\n'
+}
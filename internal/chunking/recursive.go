@@ -1,10 +1,9 @@
 package chunking
 
 import (
-	"fmt"
 	"strings"
 
-	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/chunk"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
@@ -26,43 +25,42 @@ func NewRecursiveStrategy(tok tokenizer.Tokenizer) *RecursiveStrategy {
 }
 
 // Chunk implements the ChunkStrategy interface for recursive chunking.
-func (s *RecursiveStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]export.Chunk, error) {
+func (s *RecursiveStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
 	content := string(doc.Content)
 
 	// Check if entire content fits within token limit
 	if s.tokenizer.Count(content) <= maxTokens {
-		return []export.Chunk{
+		chunks := []chunk.Chunk{
 			{
-				ID:         fmt.Sprintf("%s-chunk-0", doc.ID),
 				Text:       content,
 				StartPos:   0,
 				EndPos:     len(content),
 				TokenCount: s.tokenizer.Count(content),
 			},
-		}, nil
+		}
+		chunk.AssignIDs(doc.ID, chunks)
+		return chunks, nil
 	}
 
 	// Recursively split using separators
-	chunks := make([]export.Chunk, 0)
-	chunkID := 0
-	s.recursiveSplit(content, 0, maxTokens, &chunks, &chunkID, doc.ID, 0)
+	chunks := make([]chunk.Chunk, 0)
+	s.recursiveSplit(content, 0, maxTokens, &chunks, 0)
 
+	chunk.AssignIDs(doc.ID, chunks)
 	return chunks, nil
 }
 
 // recursiveSplit recursively splits text using hierarchical separators.
-func (s *RecursiveStrategy) recursiveSplit(text string, offset int, maxTokens int, chunks *[]export.Chunk, chunkID *int, docID string, depth int) {
+func (s *RecursiveStrategy) recursiveSplit(text string, offset int, maxTokens int, chunks *[]chunk.Chunk, depth int) {
 	// If text fits, create chunk
 	tokenCount := s.tokenizer.Count(text)
 	if tokenCount <= maxTokens {
-		*chunks = append(*chunks, export.Chunk{
-			ID:         fmt.Sprintf("%s-chunk-%d", docID, *chunkID),
+		*chunks = append(*chunks, chunk.Chunk{
 			Text:       text,
 			StartPos:   offset,
 			EndPos:     offset + len(text),
 			TokenCount: tokenCount,
 		})
-		*chunkID++
 		return
 	}
 
@@ -71,22 +69,13 @@ func (s *RecursiveStrategy) recursiveSplit(text string, offset int, maxTokens in
 		separator := s.separators[depth]
 
 		if separator == "" {
-			// Last resort: character-level splitting
-			// Use binary search to find split point
-			left, right := 0, len(text)
-			for left < right {
-				mid := (left + right + 1) / 2
-				if s.tokenizer.Count(text[:mid]) <= maxTokens {
-					left = mid
-				} else {
-					right = mid - 1
-				}
-			}
+			// Last resort: split after maxTokens tokens
+			left := s.splitPoint(text, maxTokens)
 
 			if left > 0 {
 				// Split at character boundary
-				s.recursiveSplit(text[:left], offset, maxTokens, chunks, chunkID, docID, depth)
-				s.recursiveSplit(text[left:], offset+left, maxTokens, chunks, chunkID, docID, 0)
+				s.recursiveSplit(text[:left], offset, maxTokens, chunks, depth)
+				s.recursiveSplit(text[left:], offset+left, maxTokens, chunks, 0)
 			}
 			return
 		}
@@ -94,33 +83,36 @@ func (s *RecursiveStrategy) recursiveSplit(text string, offset int, maxTokens in
 		// Split by separator
 		parts := strings.Split(text, separator)
 		if len(parts) > 1 {
-			// Build chunks by combining parts until token limit
+			// Build chunks by combining parts until token limit. Parts are
+			// counted once and their counts summed; the estimate is verified
+			// when each combined chunk is split recursively.
 			currentPart := ""
+			currentTokens := 0
 			currentOffset := offset
+			separatorTokens := s.tokenizer.Count(separator)
 
 			for i, part := range parts {
-				testText := currentPart
-				if testText != "" {
-					testText += separator
-				}
-				testText += part
+				partTokens := s.tokenizer.Count(part)
 
-				if s.tokenizer.Count(testText) <= maxTokens || currentPart == "" {
+				if currentPart == "" || currentTokens+separatorTokens+partTokens <= maxTokens {
 					// Add this part to current chunk
 					if currentPart != "" {
 						currentPart += separator
+						currentTokens += separatorTokens
 					}
 					currentPart += part
+					currentTokens += partTokens
 				} else {
 					// Current part would exceed limit, save what we have and start new
-					s.recursiveSplit(currentPart, currentOffset, maxTokens, chunks, chunkID, docID, depth+1)
+					s.recursiveSplit(currentPart, currentOffset, maxTokens, chunks, depth+1)
 					currentOffset += len(currentPart) + len(separator)
 					currentPart = part
+					currentTokens = partTokens
 				}
 
 				// If last part, process remaining
 				if i == len(parts)-1 && currentPart != "" {
-					s.recursiveSplit(currentPart, currentOffset, maxTokens, chunks, chunkID, docID, depth+1)
+					s.recursiveSplit(currentPart, currentOffset, maxTokens, chunks, depth+1)
 				}
 			}
 			return
@@ -129,22 +121,24 @@ func (s *RecursiveStrategy) recursiveSplit(text string, offset int, maxTokens in
 
 	// If no separator worked, try next level
 	if depth+1 < len(s.separators) {
-		s.recursiveSplit(text, offset, maxTokens, chunks, chunkID, docID, depth+1)
+		s.recursiveSplit(text, offset, maxTokens, chunks, depth+1)
 	} else {
 		// Fallback: force split
-		left, right := 0, len(text)
-		for left < right {
-			mid := (left + right + 1) / 2
-			if s.tokenizer.Count(text[:mid]) <= maxTokens {
-				left = mid
-			} else {
-				right = mid - 1
-			}
-		}
+		left := s.splitPoint(text, maxTokens)
 
 		if left > 0 && left < len(text) {
-			s.recursiveSplit(text[:left], offset, maxTokens, chunks, chunkID, docID, 0)
-			s.recursiveSplit(text[left:], offset+left, maxTokens, chunks, chunkID, docID, 0)
+			s.recursiveSplit(text[:left], offset, maxTokens, chunks, 0)
+			s.recursiveSplit(text[left:], offset+left, maxTokens, chunks, 0)
 		}
 	}
 }
+
+// splitPoint returns the byte offset in text just after its first maxTokens
+// tokens, tokenizing text only once.
+func (s *RecursiveStrategy) splitPoint(text string, maxTokens int) int {
+	bounds := tokenBounds(s.tokenizer, text)
+	if maxTokens >= len(bounds)-1 {
+		return len(text)
+	}
+	return bounds[maxTokens]
+}
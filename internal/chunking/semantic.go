@@ -1,68 +1,431 @@
 package chunking
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
 
-	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/embedding"
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
 
-// SemanticStrategy implements embedding-based semantic boundary detection for chunking.
-// This is a stub implementation that falls back to FixedSizeStrategy.
-// TODO: Implement actual semantic chunking using embeddings to detect topic boundaries.
+// DefaultLexicalThreshold is the similarity below which a topic boundary is
+// placed when comparing segments by shared vocabulary.
+const DefaultLexicalThreshold = 0.1
+
+// DefaultEmbeddingThreshold is the cosine similarity below which a topic
+// boundary is placed when comparing segments by embedding.
+const DefaultEmbeddingThreshold = 0.6
+
+// similarityWindow is the number of segments on each side of a candidate
+// boundary that are compared. Comparing small blocks rather than single
+// sentences smooths out noise, as in TextTiling.
+const similarityWindow = 2
+
+var (
+	semanticHeaderRegex = regexp.MustCompile(`^#{1,6}\s+`)
+	sentenceEndRegex    = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+)
+
+// SemanticStrategy implements topic-aware chunking. The document is split into
+// paragraphs (and sentences, for paragraphs too large to fit in a chunk), the
+// similarity between neighbouring segments is scored, and chunks are cut where
+// the similarity drops below a threshold, i.e. where the topic shifts.
 //
-// Future implementation should:
-// - Generate embeddings for sentences or paragraphs
-// - Calculate cosine similarity between consecutive segments
-// - Identify low-similarity boundaries as natural chunk splits
-// - Reference: Anthropic Contextual Retrieval (2025) for best practices
+// Similarity is computed with an embedding.Embedder when one is configured,
+// and otherwise from shared vocabulary, which needs no model at all. Headings
+// always start a new chunk. Chunks never exceed maxTokens; when a single topic
+// is too large it is split at segment boundaries with overlapTokens of
+// trailing segments repeated at the start of the next chunk.
 type SemanticStrategy struct {
-	tokenizer     tokenizer.Tokenizer
-	fallbackStrat *FixedSizeStrategy
+	tokenizer tokenizer.Tokenizer
+	embedder  embedding.Embedder
+	threshold float64
 }
 
 // NewSemanticStrategy creates a new SemanticStrategy with the given tokenizer.
-// Currently uses FixedSizeStrategy as fallback until embeddings are implemented.
+// It uses lexical similarity until an embedder is configured with SetEmbedder.
 func NewSemanticStrategy(tok tokenizer.Tokenizer) *SemanticStrategy {
 	return &SemanticStrategy{
-		tokenizer:     tok,
-		fallbackStrat: NewFixedSizeStrategy(tok),
+		tokenizer: tok,
 	}
 }
 
+// SetEmbedder configures the embedder used to score segment similarity.
+// Passing nil restores the lexical similarity fallback.
+func (s *SemanticStrategy) SetEmbedder(embedder embedding.Embedder) {
+	s.embedder = embedder
+}
+
+// SetThreshold sets the similarity below which a topic boundary is placed.
+// A value of 0 selects the default for the configured similarity measure.
+func (s *SemanticStrategy) SetThreshold(threshold float64) {
+	s.threshold = threshold
+}
+
+// segment is a contiguous span of the document content.
+type segment struct {
+	start int
+	end   int
+}
+
 // Chunk implements the ChunkStrategy interface for semantic chunking.
-// TODO: Replace this fallback implementation with actual embedding-based semantic boundary detection.
-//
-// Planned approach:
-// 1. Split text into sentences/paragraphs
-// 2. Generate embeddings for each segment using an embedding model (e.g., OpenAI, Anthropic, local)
-// 3. Calculate cosine similarity between consecutive embeddings
-// 4. Find low-similarity boundaries (topic changes)
-// 5. Split at semantic boundaries while respecting maxTokens
-// 6. Combine small segments that belong to the same topic
-func (s *SemanticStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]export.Chunk, error) {
-	// TODO: Implement semantic chunking with embeddings
-	// For now, fall back to fixed-size strategy
-	return s.fallbackStrat.Chunk(doc, maxTokens, overlapTokens)
-}
-
-// semanticBoundaryDetection is a placeholder for future implementation.
-// TODO: Implement this function to detect semantic boundaries using embeddings.
-//
-// Expected signature:
-//   func (s *SemanticStrategy) semanticBoundaryDetection(sentences []string) ([]int, error)
-//
-// Expected behavior:
-//   - Input: List of sentences or paragraphs
-//   - Output: Indices where semantic boundaries occur (low similarity scores)
-//   - Error: If embedding API fails or other issues occur
-//
-// Implementation notes:
-//   - Use batched embedding API calls for efficiency
-//   - Cache embeddings to avoid redundant API calls
-//   - Consider using a similarity threshold (e.g., cosine similarity < 0.7)
-//   - Ensure chunk boundaries respect maxTokens constraints
+func (s *SemanticStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
+	content := string(doc.Content)
+
+	segments := s.splitSegments(content, maxTokens)
+	if len(segments) == 0 {
+		return []chunk.Chunk{}, nil
+	}
+
+	texts := make([]string, len(segments))
+	for i, seg := range segments {
+		texts[i] = content[seg.start:seg.end]
+	}
+
+	boundaries, err := s.semanticBoundaryDetection(texts)
+	if err != nil {
+		return nil, err
+	}
+	isBoundary := make(map[int]bool, len(boundaries))
+	for _, b := range boundaries {
+		isBoundary[b] = true
+	}
+
+	chunks := make([]chunk.Chunk, 0)
+	emit := func(start, end int) {
+		text := content[start:end]
+		chunks = append(chunks, chunk.Chunk{
+			Text:       text,
+			StartPos:   start,
+			EndPos:     end,
+			TokenCount: s.tokenizer.Count(text),
+		})
+	}
+
+	first := -1 // index of the first segment in the current chunk
+	for i, seg := range segments {
+		if first >= 0 && isBoundary[i] {
+			emit(segments[first].start, segments[i-1].end)
+			first = -1
+		}
+
+		if first >= 0 && s.tokenizer.Count(content[segments[first].start:seg.end]) > maxTokens {
+			emit(segments[first].start, segments[i-1].end)
+			first = s.overlapStart(content, segments, first, i, overlapTokens, maxTokens)
+		}
+
+		if first < 0 {
+			if s.tokenizer.Count(texts[i]) > maxTokens {
+				// A single sentence larger than a chunk can only be split by size
+				oversized, err := s.splitOversized(doc.ID, texts[i], seg.start, maxTokens, overlapTokens)
+				if err != nil {
+					return nil, err
+				}
+				chunks = append(chunks, oversized...)
+				continue
+			}
+			first = i
+		}
+	}
+
+	if first >= 0 {
+		emit(segments[first].start, segments[len(segments)-1].end)
+	}
+
+	chunk.AssignIDs(doc.ID, chunks)
+	return chunks, nil
+}
+
+// overlapStart returns the index of the first segment of the next chunk after
+// a size-forced split before segment next. It steps back over as many trailing
+// segments of the previous chunk as fit in overlapTokens, while leaving room
+// for segment next itself. It returns -1 if no overlap is possible.
+func (s *SemanticStrategy) overlapStart(content string, segments []segment, first, next, overlapTokens, maxTokens int) int {
+	if overlapTokens <= 0 {
+		return -1
+	}
+
+	start := -1
+	for j := next - 1; j > first; j-- {
+		if s.tokenizer.Count(content[segments[j].start:segments[next-1].end]) > overlapTokens {
+			break
+		}
+		if s.tokenizer.Count(content[segments[j].start:segments[next].end]) > maxTokens {
+			break
+		}
+		start = j
+	}
+	return start
+}
+
+// splitOversized splits a single segment that exceeds maxTokens using the
+// fixed-size strategy, shifting positions to be relative to the document.
+func (s *SemanticStrategy) splitOversized(docID, text string, offset, maxTokens, overlapTokens int) ([]chunk.Chunk, error) {
+	fixed := NewFixedSizeStrategy(s.tokenizer)
+	chunks, err := fixed.Chunk(scanner.Document{ID: docID, Content: []byte(text)}, maxTokens, overlapTokens)
+	if err != nil {
+		return nil, err
+	}
+	for i := range chunks {
+		chunks[i].StartPos += offset
+		chunks[i].EndPos += offset
+	}
+	return chunks, nil
+}
+
+// splitSegments divides content into paragraphs, keeping fenced code blocks
+// whole. Paragraphs that do not fit in maxTokens are further divided into
+// sentences. Surrounding whitespace is excluded from each segment.
+func (s *SemanticStrategy) splitSegments(content string, maxTokens int) []segment {
+	var segments []segment
+
+	inFence := false
+	paraStart := -1
+	pos := 0
+
+	closeParagraph := func(end int) {
+		if paraStart < 0 {
+			return
+		}
+		segments = append(segments, s.splitParagraph(content, paraStart, end, maxTokens)...)
+		paraStart = -1
+	}
+
+	for pos < len(content) {
+		lineEnd := strings.IndexByte(content[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += pos
+		}
+		line := strings.TrimSpace(content[pos:lineEnd])
+
+		switch {
+		case strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~"):
+			if !inFence {
+				closeParagraph(pos)
+				paraStart = pos
+			}
+			inFence = !inFence
+		case inFence:
+			// Blank lines inside code blocks do not end the paragraph
+		case line == "":
+			closeParagraph(pos)
+		case semanticHeaderRegex.MatchString(line):
+			// Headings are always a segment of their own
+			closeParagraph(pos)
+			segments = append(segments, trimSegment(content, pos, lineEnd))
+		default:
+			if paraStart < 0 {
+				paraStart = pos
+			}
+		}
+
+		pos = lineEnd + 1
+	}
+	closeParagraph(len(content))
+
+	// Drop any empty segments left over from trimming
+	result := segments[:0]
+	for _, seg := range segments {
+		if seg.end > seg.start {
+			result = append(result, seg)
+		}
+	}
+	return result
+}
+
+// splitParagraph returns the paragraph content[start:end] as a single segment,
+// or as one segment per sentence if it exceeds maxTokens.
+func (s *SemanticStrategy) splitParagraph(content string, start, end, maxTokens int) []segment {
+	para := trimSegment(content, start, end)
+	text := content[para.start:para.end]
+	if s.tokenizer.Count(text) <= maxTokens || strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+		return []segment{para}
+	}
+
+	var sentences []segment
+	sentStart := 0
+	for _, loc := range sentenceEndRegex.FindAllStringIndex(text, -1) {
+		sentences = append(sentences, trimSegment(content, para.start+sentStart, para.start+loc[1]))
+		sentStart = loc[1]
+	}
+	if sentStart < len(text) {
+		sentences = append(sentences, trimSegment(content, para.start+sentStart, para.end))
+	}
+	return sentences
+}
+
+// trimSegment returns the span content[start:end] without surrounding whitespace.
+func trimSegment(content string, start, end int) segment {
+	for start < end && isASCIISpace(content[start]) {
+		start++
+	}
+	for end > start && isASCIISpace(content[end-1]) {
+		end--
+	}
+	return segment{start: start, end: end}
+}
+
+// isASCIISpace reports whether b is an ASCII whitespace byte. Checking bytes
+// rather than runes keeps multi-byte UTF-8 sequences intact.
+func isASCIISpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
+
+// semanticBoundaryDetection scores the similarity between neighbouring
+// segments and returns the indices of segments that begin a new topic.
+// A boundary is always placed before a heading and never directly after one.
 func (s *SemanticStrategy) semanticBoundaryDetection(sentences []string) ([]int, error) {
-	return nil, fmt.Errorf("semantic boundary detection not yet implemented - requires embedding model integration")
+	if len(sentences) < 2 {
+		return nil, nil
+	}
+
+	similarities, threshold, err := s.similarities(sentences)
+	if err != nil {
+		return nil, err
+	}
+
+	var boundaries []int
+	for i := 1; i < len(sentences); i++ {
+		switch {
+		case semanticHeaderRegex.MatchString(sentences[i]):
+			boundaries = append(boundaries, i)
+		case semanticHeaderRegex.MatchString(sentences[i-1]):
+			// Keep a heading together with the content it introduces
+		case similarities[i-1] < threshold:
+			boundaries = append(boundaries, i)
+		}
+	}
+
+	return boundaries, nil
+}
+
+// similarities returns, for each gap between segments i and i+1, the
+// similarity of the windows of segments on either side, together with the
+// threshold that applies to the similarity measure in use.
+func (s *SemanticStrategy) similarities(sentences []string) ([]float64, float64, error) {
+	result := make([]float64, len(sentences)-1)
+
+	if s.embedder != nil {
+		vectors, err := s.embedder.Embed(context.Background(), sentences)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to embed segments: %w", err)
+		}
+		if len(vectors) != len(sentences) {
+			return nil, 0, fmt.Errorf("embedder %s returned %d vectors for %d segments", s.embedder.Name(), len(vectors), len(sentences))
+		}
+
+		for i := range result {
+			left := sumVectors(vectors[windowStart(i) : i+1])
+			right := sumVectors(vectors[i+1 : windowEnd(i, len(vectors))])
+			result[i] = embedding.CosineSimilarity(left, right)
+		}
+		return result, s.thresholdOr(DefaultEmbeddingThreshold), nil
+	}
+
+	terms := make([]map[string]float64, len(sentences))
+	for i, sentence := range sentences {
+		terms[i] = termFrequencies(sentence)
+	}
+	for i := range result {
+		left := sumTerms(terms[windowStart(i) : i+1])
+		right := sumTerms(terms[i+1 : windowEnd(i, len(terms))])
+		result[i] = lexicalSimilarity(left, right)
+	}
+	return result, s.thresholdOr(DefaultLexicalThreshold), nil
+}
+
+// thresholdOr returns the configured threshold, or def if none is set.
+func (s *SemanticStrategy) thresholdOr(def float64) float64 {
+	if s.threshold > 0 {
+		return s.threshold
+	}
+	return def
+}
+
+// windowStart returns the first segment index of the window ending at gap i.
+func windowStart(i int) int {
+	if i-similarityWindow+1 < 0 {
+		return 0
+	}
+	return i - similarityWindow + 1
+}
+
+// windowEnd returns the index one past the window starting after gap i.
+func windowEnd(i, n int) int {
+	if i+1+similarityWindow > n {
+		return n
+	}
+	return i + 1 + similarityWindow
+}
+
+// sumVectors returns the element-wise sum of equally sized vectors.
+func sumVectors(vectors [][]float32) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	sum := make([]float32, len(vectors[0]))
+	for _, v := range vectors {
+		for i := range sum {
+			if i < len(v) {
+				sum[i] += v[i]
+			}
+		}
+	}
+	return sum
+}
+
+// termFrequencies counts the content words of text, ignoring stop words,
+// very short words and markdown syntax.
+func termFrequencies(text string) map[string]float64 {
+	tf := make(map[string]float64)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len(word) < 3 || keywords.IsStopWord(word) {
+			continue
+		}
+		// Crude plural folding so "chunk" and "chunks" count as the same term
+		if len(word) > 4 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = word[:len(word)-1]
+		}
+		tf[word]++
+	}
+	return tf
+}
+
+// sumTerms merges term frequency maps.
+func sumTerms(maps []map[string]float64) map[string]float64 {
+	sum := make(map[string]float64)
+	for _, m := range maps {
+		for term, count := range m {
+			sum[term] += count
+		}
+	}
+	return sum
+}
+
+// lexicalSimilarity returns the cosine similarity of two term frequency maps.
+func lexicalSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, count := range a {
+		normA += count * count
+		dot += count * b[term]
+	}
+	for _, count := range b {
+		normB += count * count
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
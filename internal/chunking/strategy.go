@@ -2,7 +2,7 @@
 package chunking
 
 import (
-	"github.com/onedusk/jot/internal/export"
+	"github.com/onedusk/jot/internal/chunk"
 	"github.com/onedusk/jot/internal/scanner"
 )
 
@@ -18,5 +18,5 @@ type ChunkStrategy interface {
 	// Returns:
 	//   - A slice of chunks
 	//   - An error if the chunking operation fails
-	Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]export.Chunk, error)
+	Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]chunk.Chunk, error)
 }
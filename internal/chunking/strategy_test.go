@@ -3,17 +3,16 @@ package chunking
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/onedusk/jot/internal/embedding"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
 
 // TestFixedStrategy tests the FixedSizeStrategy implementation.
 func TestFixedStrategy(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
-	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
-	}
+	tok := tokenizer.NewHeuristicTokenizer()
 
 	strategy := NewFixedSizeStrategy(tok)
 
@@ -75,10 +74,7 @@ func TestFixedStrategy(t *testing.T) {
 
 // TestHeaderStrategy tests the MarkdownHeaderStrategy implementation.
 func TestHeaderStrategy(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
-	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
-	}
+	tok := tokenizer.NewHeuristicTokenizer()
 
 	strategy := NewMarkdownHeaderStrategy(tok)
 
@@ -134,10 +130,7 @@ Even more content.`,
 
 // TestRecursiveStrategy tests the RecursiveStrategy implementation.
 func TestRecursiveStrategy(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
-	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
-	}
+	tok := tokenizer.NewHeuristicTokenizer()
 
 	strategy := NewRecursiveStrategy(tok)
 
@@ -188,12 +181,9 @@ Third paragraph here too.`,
 	}
 }
 
-// TestSemanticStrategy tests the SemanticStrategy stub implementation.
+// TestSemanticStrategy tests the SemanticStrategy implementation.
 func TestSemanticStrategy(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
-	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
-	}
+	tok := tokenizer.NewHeuristicTokenizer()
 
 	strategy := NewSemanticStrategy(tok)
 
@@ -202,7 +192,6 @@ func TestSemanticStrategy(t *testing.T) {
 		Content: []byte("This is test content for semantic chunking."),
 	}
 
-	// Should fall back to fixed strategy
 	chunks, err := strategy.Chunk(doc, 100, 10)
 	if err != nil {
 		t.Fatalf("SemanticStrategy.Chunk() error = %v", err)
@@ -213,23 +202,342 @@ func TestSemanticStrategy(t *testing.T) {
 	}
 }
 
-// TestNewChunkStrategy tests the factory function.
-func TestNewChunkStrategy(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
+// wordTokenizer counts whitespace-separated words as tokens. It lets tests
+// exercise chunk boundaries without loading BPE data.
+type wordTokenizer struct{}
+
+func (wordTokenizer) Encode(text string) []int {
+	return make([]int, len(strings.Fields(text)))
+}
+
+func (wordTokenizer) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+func (wordTokenizer) Encoding() string {
+	return "words"
+}
+
+func (wordTokenizer) Offsets(text string) []int {
+	var offsets []int
+	for i := 0; i < len(text); i++ {
+		if !isASCIISpace(text[i]) && (i == 0 || isASCIISpace(text[i-1])) {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+// TestSemanticStrategy_TopicShift tests that chunks are cut where the topic changes.
+func TestSemanticStrategy_TopicShift(t *testing.T) {
+	content := "Kubernetes pods run containers. Pods share kubernetes networking.\n\n" +
+		"Kubernetes schedules pods onto nodes. Containers in pods restart automatically.\n\n" +
+		"Sourdough bread needs flour and starter. Bread dough rises overnight.\n\n" +
+		"Bake the bread dough in a hot oven. Flour the sourdough loaf first."
+
+	strategy := NewSemanticStrategy(wordTokenizer{})
+	doc := scanner.Document{ID: "doc", Content: []byte(content)}
+
+	chunks, err := strategy.Chunk(doc, 200, 0)
+	if err != nil {
+		t.Fatalf("SemanticStrategy.Chunk() error = %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("SemanticStrategy.Chunk() got %d chunks, want 2: %+v", len(chunks), chunks)
+	}
+	if !strings.Contains(chunks[0].Text, "Kubernetes") || strings.Contains(chunks[0].Text, "bread") {
+		t.Errorf("first chunk should contain only the kubernetes topic: %q", chunks[0].Text)
+	}
+	if !strings.HasPrefix(chunks[1].Text, "Sourdough") {
+		t.Errorf("second chunk should start at the topic shift: %q", chunks[1].Text)
+	}
+
+	// Positions map back to the original content
+	for i, chunk := range chunks {
+		if content[chunk.StartPos:chunk.EndPos] != chunk.Text {
+			t.Errorf("chunk %d positions do not match text", i)
+		}
+	}
+}
+
+// TestSemanticStrategy_RespectsLimits tests that chunks stay within maxTokens,
+// that headings start new chunks and that code blocks are never split.
+func TestSemanticStrategy_RespectsLimits(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("# Introduction\n\n")
+	for i := 0; i < 20; i++ {
+		builder.WriteString("The chunking engine splits markdown documents into chunks. ")
+	}
+	builder.WriteString("\n\n## Example\n\n```go\nfunc main() {\n\n\tfmt.Println(\"chunks\")\n}\n```\n")
+	content := builder.String()
+
+	strategy := NewSemanticStrategy(wordTokenizer{})
+	doc := scanner.Document{ID: "doc", Content: []byte(content)}
+
+	chunks, err := strategy.Chunk(doc, 40, 10)
+	if err != nil {
+		t.Fatalf("SemanticStrategy.Chunk() error = %v", err)
+	}
+
+	for i, chunk := range chunks {
+		if chunk.TokenCount > 40 {
+			t.Errorf("chunk %d exceeds maxTokens: %d > 40", i, chunk.TokenCount)
+		}
+		if strings.Count(chunk.Text, "```")%2 != 0 {
+			t.Errorf("chunk %d splits a code block: %q", i, chunk.Text)
+		}
+	}
+
+	last := chunks[len(chunks)-1]
+	if !strings.HasPrefix(last.Text, "## Example") {
+		t.Errorf("heading should start a new chunk, got %q", last.Text)
+	}
+
+	// Size-forced splits within one topic overlap
+	if chunks[1].StartPos >= chunks[0].EndPos {
+		t.Errorf("expected overlap between chunks 0 and 1 (start %d, prev end %d)", chunks[1].StartPos, chunks[0].EndPos)
+	}
+}
+
+// TestSemanticStrategy_Embedder tests boundary detection with an embedder.
+func TestSemanticStrategy_Embedder(t *testing.T) {
+	strategy := NewSemanticStrategy(wordTokenizer{})
+	strategy.SetEmbedder(embedding.NewHashEmbedder(512))
+	strategy.SetThreshold(0.2)
+
+	boundaries, err := strategy.semanticBoundaryDetection([]string{
+		"vector search uses embeddings",
+		"embeddings power vector search",
+		"the orchestra played a symphony",
+	})
+	if err != nil {
+		t.Fatalf("semanticBoundaryDetection() error = %v", err)
+	}
+	if len(boundaries) != 1 || boundaries[0] != 2 {
+		t.Errorf("semanticBoundaryDetection() = %v, want [2]", boundaries)
+	}
+}
+
+// TestMarkdownStrategy_AtomicBlocks tests that code blocks, tables and lists
+// are never split when they fit within the token limit, and that each chunk
+// carries its heading path.
+func TestMarkdownStrategy_AtomicBlocks(t *testing.T) {
+	content := "# Guide\n\nIntro paragraph with a few words.\n\n" +
+		"## Install\n\n```sh\n# not a heading\ngo install ./cmd/jot\ngo test ./...\n```\n\n" +
+		"### Options\n\n| Flag | Meaning |\n| --- | --- |\n| -o | output file |\n| -s | strategy name |\n\n" +
+		"- first item\n  continued line\n- second item\n\n" +
+		"> quoted advice\n> on two lines\n"
+
+	strategy := NewMarkdownStrategy(wordTokenizer{})
+	doc := scanner.Document{ID: "doc", Content: []byte(content)}
+
+	chunks, err := strategy.Chunk(doc, 30, 0)
+	if err != nil {
+		t.Fatalf("Chunk() error = %v", err)
+	}
+
+	atoms := []string{
+		"```sh\n# not a heading\ngo install ./cmd/jot\ngo test ./...\n```",
+		"| Flag | Meaning |\n| --- | --- |\n| -o | output file |\n| -s | strategy name |",
+		"- first item\n  continued line\n- second item",
+		"> quoted advice\n> on two lines",
+	}
+	for _, atom := range atoms {
+		found := false
+		for _, c := range chunks {
+			if strings.Contains(c.Text, atom) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no chunk contains block %q intact", atom)
+		}
+	}
+
+	for _, c := range chunks {
+		if strings.Contains(c.Text, "go install") {
+			if got := strings.Join(c.HeadingPath, " > "); got != "Guide > Install" {
+				t.Errorf("code chunk heading path = %q, want %q", got, "Guide > Install")
+			}
+		}
+		if strings.Contains(c.Text, "| Flag |") {
+			if got := strings.Join(c.HeadingPath, " > "); got != "Guide > Install > Options" {
+				t.Errorf("table chunk heading path = %q, want %q", got, "Guide > Install > Options")
+			}
+		}
+		if c.Text != content[c.StartPos:c.EndPos] {
+			t.Errorf("chunk %s text does not match its source span", c.ID)
+		}
+	}
+}
+
+// TestMarkdownStrategy_OversizedBlocks tests that oversized code blocks are
+// split by lines with the fence repeated and tables with the header repeated,
+// and that the positions of those pieces span the lines taken from the block.
+func TestMarkdownStrategy_OversizedBlocks(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("```go\n")
+	for i := 0; i < 12; i++ {
+		builder.WriteString("x := compute(a, b)\n")
+	}
+	builder.WriteString("```\n\n| Name | Value |\n|------|-------|\n")
+	for i := 0; i < 8; i++ {
+		builder.WriteString("| key | value |\n")
+	}
+
+	strategy := NewMarkdownStrategy(wordTokenizer{})
+	content := builder.String()
+	doc := scanner.Document{ID: "doc", Content: []byte(content)}
+
+	chunks, err := strategy.Chunk(doc, 20, 0)
+	if err != nil {
+		t.Fatalf("Chunk() error = %v", err)
+	}
+
+	var codeChunks, tableChunks int
+	for _, c := range chunks {
+		if c.TokenCount > 20 {
+			t.Errorf("chunk %s has %d tokens, exceeds limit 20", c.ID, c.TokenCount)
+		}
+		switch {
+		case strings.Contains(c.Text, "compute"):
+			codeChunks++
+			if !strings.HasPrefix(c.Text, "```go\n") || !strings.HasSuffix(c.Text, "\n```") {
+				t.Errorf("code chunk %s is not wrapped in fences: %q", c.ID, c.Text)
+			}
+			if body := strings.TrimSuffix(strings.TrimPrefix(c.Text, "```go\n"), "\n```"); body != content[c.StartPos:c.EndPos] {
+				t.Errorf("code chunk %s body %q does not match its source span %q", c.ID, body, content[c.StartPos:c.EndPos])
+			}
+		case strings.Contains(c.Text, "| key |"):
+			tableChunks++
+			if !strings.HasPrefix(c.Text, "| Name | Value |\n|------|-------|\n") {
+				t.Errorf("table chunk %s does not repeat the header: %q", c.ID, c.Text)
+			}
+			if rows := strings.TrimPrefix(c.Text, "| Name | Value |\n|------|-------|\n"); rows != content[c.StartPos:c.EndPos] {
+				t.Errorf("table chunk %s rows %q do not match their source span %q", c.ID, rows, content[c.StartPos:c.EndPos])
+			}
+		}
+	}
+
+	if codeChunks < 2 || tableChunks < 2 {
+		t.Errorf("got %d code chunks and %d table chunks, want at least 2 of each", codeChunks, tableChunks)
+	}
+}
+
+// byteTokenizer treats every byte as a token, so token boundaries fall inside
+// multi-byte UTF-8 characters.
+type byteTokenizer struct{}
+
+func (byteTokenizer) Encode(text string) []int {
+	return make([]int, len(text))
+}
+
+func (byteTokenizer) Count(text string) int {
+	return len(text)
+}
+
+func (byteTokenizer) Encoding() string {
+	return "bytes"
+}
+
+func (byteTokenizer) Offsets(text string) []int {
+	offsets := make([]int, len(text))
+	for i := range offsets {
+		offsets[i] = i
+	}
+	return offsets
+}
+
+// TestFixedStrategy_TokenRanges tests that fixed-size chunks are sliced by
+// token ranges with the requested overlap and never split UTF-8 characters.
+func TestFixedStrategy_TokenRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		tok       tokenizer.Tokenizer
+		content   string
+		maxTokens int
+		overlap   int
+	}{
+		{"words", wordTokenizer{}, strings.Repeat("alpha beta gamma delta ", 50), 20, 5},
+		{"multi-byte characters", byteTokenizer{}, strings.Repeat("größe naïve 日本語 ", 20), 16, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := scanner.Document{ID: "doc", Content: []byte(tt.content)}
+			chunks, err := NewFixedSizeStrategy(tt.tok).Chunk(doc, tt.maxTokens, tt.overlap)
+			if err != nil {
+				t.Fatalf("Chunk() error = %v", err)
+			}
+			if len(chunks) < 2 {
+				t.Fatalf("Chunk() returned %d chunks, want several", len(chunks))
+			}
+
+			for i, c := range chunks {
+				if !utf8.ValidString(c.Text) {
+					t.Errorf("chunk %d is not valid UTF-8: %q", i, c.Text)
+				}
+				if c.Text != tt.content[c.StartPos:c.EndPos] {
+					t.Errorf("chunk %d text does not match its source span", i)
+				}
+				if c.TokenCount > tt.maxTokens {
+					t.Errorf("chunk %d has %d tokens, exceeds %d", i, c.TokenCount, tt.maxTokens)
+				}
+				if i > 0 && c.StartPos >= chunks[i-1].EndPos {
+					t.Errorf("chunk %d does not overlap the previous chunk", i)
+				}
+			}
+			if last := chunks[len(chunks)-1]; last.EndPos != len(tt.content) {
+				t.Errorf("last chunk ends at %d, want %d", last.EndPos, len(tt.content))
+			}
+		})
+	}
+}
+
+// TestRecursiveStrategy_TokenRanges tests recursive splitting down to the
+// token level without splitting UTF-8 characters.
+func TestRecursiveStrategy_TokenRanges(t *testing.T) {
+	content := strings.Repeat("日本語", 30)
+	doc := scanner.Document{ID: "doc", Content: []byte(content)}
+
+	chunks, err := NewRecursiveStrategy(byteTokenizer{}).Chunk(doc, 20, 0)
 	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
+		t.Fatalf("Chunk() error = %v", err)
 	}
 
+	var rebuilt strings.Builder
+	for i, c := range chunks {
+		if !utf8.ValidString(c.Text) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, c.Text)
+		}
+		if c.TokenCount > 20 {
+			t.Errorf("chunk %d has %d tokens, exceeds 20", i, c.TokenCount)
+		}
+		rebuilt.WriteString(c.Text)
+	}
+	if rebuilt.String() != content {
+		t.Error("chunks do not reassemble into the original content")
+	}
+}
+
+// TestNewChunkStrategy tests the factory function.
+func TestNewChunkStrategy(t *testing.T) {
+	tok := tokenizer.NewHeuristicTokenizer()
+
 	tests := []struct {
-		name        string
+		name         string
 		strategyName string
-		wantErr     bool
+		wantErr      bool
 	}{
 		{"fixed strategy", "fixed", false},
 		{"headers strategy", "headers", false},
 		{"markdown-headers alias", "markdown-headers", false},
+		{"markdown strategy", "markdown", false},
 		{"recursive strategy", "recursive", false},
 		{"semantic strategy", "semantic", false},
+		{"contextual alias", "contextual", false},
 		{"unknown strategy", "unknown", true},
 	}
 
@@ -10,6 +10,7 @@ import (
 	"github.com/onedusk/jot/internal/renderer"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/search"
+	"github.com/onedusk/jot/internal/site"
 	"github.com/onedusk/jot/internal/toc"
 )
 
@@ -29,6 +30,36 @@ func NewCompiler(outputPath string) *Compiler {
 	}
 }
 
+// SetMarkdownMirror links every rendered page to its markdown twin, which
+// MarkdownCompiler writes next to it.
+func (c *Compiler) SetMarkdownMirror(enabled bool) {
+	c.renderer.SetMarkdownAlternate(enabled)
+}
+
+// SetVersions renders pages as part of one version directory of a versioned
+// site; see renderer.HTMLRenderer.SetVersions.
+func (c *Compiler) SetVersions(info *renderer.VersionInfo) {
+	c.renderer.SetVersions(info)
+}
+
+// SetEditURL adds "Edit this page" links to pages; see
+// renderer.HTMLRenderer.SetEditURL.
+func (c *Compiler) SetEditURL(urlTemplate string) {
+	c.renderer.SetEditURL(urlTemplate)
+}
+
+// SetLanguage sets the language of rendered pages; see
+// renderer.HTMLRenderer.SetLanguage.
+func (c *Compiler) SetLanguage(language string) {
+	c.renderer.SetLanguage(language)
+}
+
+// SetLocales renders pages as part of one locale directory of a
+// multi-language site; see renderer.HTMLRenderer.SetLocales.
+func (c *Compiler) SetLocales(info *renderer.LocaleInfo) {
+	c.renderer.SetLocales(info)
+}
+
 // Compile processes a slice of documents, generates HTML output, and creates a search index.
 // It also ensures that an index page is created if one doesn't exist.
 func (c *Compiler) Compile(documents []scanner.Document, tableOfContents *toc.TableOfContents) error {
@@ -44,11 +75,14 @@ func (c *Compiler) Compile(documents []scanner.Document, tableOfContents *toc.Ta
 		}
 	}
 
-	// Generate index page if not present
+	// Generate index page if not present, or copy README.html to it so that
+	// index.html always exists
 	if !c.hasIndexPage(documents) {
 		if err := c.generateIndexPage(tableOfContents); err != nil {
 			return fmt.Errorf("failed to generate index page: %w", err)
 		}
+	} else if err := c.copyReadmeIndex(documents); err != nil {
+		return fmt.Errorf("failed to write index page: %w", err)
 	}
 
 	// Generate search index
@@ -105,6 +139,22 @@ func (c *Compiler) hasIndexPage(documents []scanner.Document) bool {
 	return false
 }
 
+// copyReadmeIndex writes README.html to site.IndexPage when README.md is
+// the home page, since versioned and multi-language sites link to index.html.
+func (c *Compiler) copyReadmeIndex(documents []scanner.Document) error {
+	for _, doc := range documents {
+		if doc.RelativePath == "index.md" {
+			return nil
+		}
+	}
+
+	content, err := os.ReadFile(c.getOutputPath("README.md"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.outputPath, site.IndexPage), content, 0644)
+}
+
 // generateIndexPage creates a default index page if one is not found in the documents.
 // It includes a table of contents.
 func (c *Compiler) generateIndexPage(tableOfContents *toc.TableOfContents) error {
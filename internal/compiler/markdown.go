@@ -4,17 +4,26 @@ package compiler
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/toc"
 )
 
-// MarkdownCompiler generates markdown output with enhanced navigation features
-// like breadcrumbs and consolidated files for LLM consumption.
+// markdownLinkRegex matches the target of inline markdown links and images,
+// with an optional title: [text](target "title").
+var markdownLinkRegex = regexp.MustCompile(`(\]\()([^)\s]+)((?:\s+"[^"]*")?\))`)
+
+// MarkdownCompiler generates a markdown mirror of the site: every page gets
+// a markdown twin at the same path with a .md extension (page.html next to
+// page.md), with navigation for agents and a consolidated file for LLMs.
 type MarkdownCompiler struct {
 	outputPath string
+	documents  map[string]bool   // Relative paths of mirrored documents
+	indexes    map[string]string // Directory -> file name of its index twin
 }
 
 // NewMarkdownCompiler creates a new markdown compiler with the specified output path.
@@ -24,39 +33,68 @@ func NewMarkdownCompiler(outputPath string) *MarkdownCompiler {
 	}
 }
 
-// Compile processes documents and generates enhanced markdown output. This includes
-// individual files with navigation, a consolidated file, and a markdown TOC.
+// Compile processes documents and writes their markdown twins, a consolidated
+// all-docs.md and, when the documents have no root index, a markdown TOC as
+// index.md (matching the generated index.html).
 func (m *MarkdownCompiler) Compile(documents []scanner.Document, tableOfContents *toc.TableOfContents) error {
-	// Create markdown output directory
-	markdownDir := filepath.Join(m.outputPath, "markdown")
-	if err := os.MkdirAll(markdownDir, 0755); err != nil {
-		return fmt.Errorf("failed to create markdown directory: %w", err)
+	if err := os.MkdirAll(m.outputPath, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	m.indexDocuments(documents)
+	generateTOC := m.indexes["."] == ""
+	if generateTOC {
+		m.indexes["."] = "index.md"
 	}
 
 	// Process each document
 	for _, doc := range documents {
-		if err := m.compileDocument(doc, tableOfContents, markdownDir); err != nil {
+		if err := m.compileDocument(doc); err != nil {
 			return fmt.Errorf("failed to compile markdown %s: %w", doc.RelativePath, err)
 		}
 	}
 
 	// Generate consolidated markdown for LLMs
-	if err := m.generateConsolidatedMarkdown(documents, markdownDir); err != nil {
+	if err := m.generateConsolidatedMarkdown(documents, m.outputPath); err != nil {
 		return fmt.Errorf("failed to generate consolidated markdown: %w", err)
 	}
 
 	// Generate TOC in markdown format
-	if err := m.generateMarkdownTOC(tableOfContents, markdownDir); err != nil {
-		return fmt.Errorf("failed to generate markdown TOC: %w", err)
+	if generateTOC {
+		if err := m.generateMarkdownTOC(tableOfContents, m.outputPath); err != nil {
+			return fmt.Errorf("failed to generate markdown TOC: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// compileDocument copies and enhances a single markdown document with navigation metadata.
-func (m *MarkdownCompiler) compileDocument(doc scanner.Document, toc *toc.TableOfContents, outputDir string) error {
-	// Determine output path
-	outputPath := filepath.Join(outputDir, doc.RelativePath)
+// indexDocuments records the mirrored documents and the index page
+// (index.md, else README.md) of each directory.
+func (m *MarkdownCompiler) indexDocuments(documents []scanner.Document) {
+	m.documents = make(map[string]bool, len(documents))
+	m.indexes = make(map[string]string)
+
+	for _, doc := range documents {
+		relativePath := filepath.ToSlash(doc.RelativePath)
+		m.documents[relativePath] = true
+
+		dir, name := path.Split(relativePath)
+		dir = path.Clean("./" + dir)
+		switch {
+		case strings.EqualFold(name, "index.md"):
+			m.indexes[dir] = name
+		case strings.EqualFold(name, "readme.md") && m.indexes[dir] == "":
+			m.indexes[dir] = name
+		}
+	}
+}
+
+// compileDocument writes the markdown twin of a document with navigation
+// metadata and internal links pointing at other twins.
+func (m *MarkdownCompiler) compileDocument(doc scanner.Document) error {
+	relativePath := filepath.ToSlash(doc.RelativePath)
+	outputPath := filepath.Join(m.outputPath, filepath.FromSlash(relativePath))
 
 	// Create directory if needed
 	dir := filepath.Dir(outputPath)
@@ -66,49 +104,99 @@ func (m *MarkdownCompiler) compileDocument(doc scanner.Document, toc *toc.TableO
 
 	// Enhanced markdown with navigation
 	var enhanced strings.Builder
+	prefix := strings.Repeat("../", strings.Count(relativePath, "/"))
 
 	// Add navigation header
 	enhanced.WriteString("---\n")
 	fmt.Fprintf(&enhanced, "title: %s\n", doc.Title)
-	fmt.Fprintf(&enhanced, "path: %s\n", doc.RelativePath)
+	fmt.Fprintf(&enhanced, "path: %s\n", relativePath)
+	fmt.Fprintf(&enhanced, "html: %s\n", strings.TrimSuffix(path.Base(relativePath), path.Ext(relativePath))+".html")
 	fmt.Fprintf(&enhanced, "modified: %s\n", doc.ModTime.Format("2006-01-02"))
 	enhanced.WriteString("---\n\n")
 
 	// Add breadcrumb navigation
-	enhanced.WriteString(m.generateBreadcrumb(doc.RelativePath))
+	enhanced.WriteString(m.generateBreadcrumb(relativePath))
 	enhanced.WriteString("\n\n")
 
 	// Add original content
-	enhanced.Write(doc.Content)
+	enhanced.WriteString(m.rewriteLinks(string(doc.Content), relativePath))
 
 	// Add footer navigation
 	enhanced.WriteString("\n\n---\n")
-	enhanced.WriteString("[← Back to TOC](./index.md) | ")
-	enhanced.WriteString("[View All Docs](./all-docs.md)\n")
+	fmt.Fprintf(&enhanced, "[← Home](%s%s) | ", prefix, m.indexes["."])
+	fmt.Fprintf(&enhanced, "[View All Docs](%sall-docs.md)\n", prefix)
 
 	// Write enhanced markdown file
 	return os.WriteFile(outputPath, []byte(enhanced.String()), 0644)
 }
 
-// generateBreadcrumb creates breadcrumb navigation as a string for a given file path.
-func (m *MarkdownCompiler) generateBreadcrumb(path string) string {
-	parts := strings.Split(filepath.ToSlash(path), "/")
-	breadcrumbs := []string{"[Home](index.md)"}
-
-	for i, part := range parts[:len(parts)-1] {
+// generateBreadcrumb creates breadcrumb navigation as a string for a given
+// file path. Directories link to their index twin when they have one and are
+// plain text otherwise.
+func (m *MarkdownCompiler) generateBreadcrumb(relativePath string) string {
+	parts := strings.Split(relativePath, "/")
+	depth := len(parts) - 1
+	breadcrumbs := []string{fmt.Sprintf("[Home](%s%s)", strings.Repeat("../", depth), m.indexes["."])}
+
+	for i, part := range parts[:depth] {
+		dir := strings.Join(parts[:i+1], "/")
+		index, ok := m.indexes[dir]
+		if !ok || dir+"/"+index == relativePath {
+			breadcrumbs = append(breadcrumbs, part)
+			continue
+		}
 		// Build relative path to directory index
-		relPath := strings.Repeat("../", len(parts)-i-2)
-		breadcrumbs = append(breadcrumbs, fmt.Sprintf("[%s](%sindex.md)", part, relPath))
+		relPath := strings.Repeat("../", depth-i-1)
+		breadcrumbs = append(breadcrumbs, fmt.Sprintf("[%s](%s%s)", part, relPath, index))
 	}
 
 	// Add current file
-	if len(parts) > 0 {
-		breadcrumbs = append(breadcrumbs, parts[len(parts)-1])
-	}
+	breadcrumbs = append(breadcrumbs, parts[depth])
 
 	return "📍 " + strings.Join(breadcrumbs, " / ")
 }
 
+// rewriteLinks points internal links of a document at markdown twins: links
+// to rendered pages (.html) and to directories with an index become links to
+// the corresponding .md file. External links and links to files outside the
+// mirror are left unchanged.
+func (m *MarkdownCompiler) rewriteLinks(content, relativePath string) string {
+	baseDir := path.Dir(relativePath)
+
+	return markdownLinkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		groups := markdownLinkRegex.FindStringSubmatch(match)
+		target, fragment := groups[2], ""
+		if i := strings.Index(target, "#"); i >= 0 {
+			target, fragment = target[:i], target[i:]
+		}
+		if target == "" || strings.Contains(target, "://") || strings.HasPrefix(target, "//") ||
+			strings.HasPrefix(target, "/") || strings.HasPrefix(target, "mailto:") {
+			return match
+		}
+
+		resolved := path.Clean(path.Join(baseDir, target))
+		var rewritten string
+		switch {
+		case strings.HasSuffix(target, ".html"):
+			twin := strings.TrimSuffix(target, ".html") + ".md"
+			if !m.documents[strings.TrimSuffix(resolved, ".html")+".md"] {
+				return match
+			}
+			rewritten = twin
+		case strings.HasSuffix(target, "/") || path.Ext(target) == "":
+			index, ok := m.indexes[resolved]
+			if !ok {
+				return match
+			}
+			rewritten = strings.TrimSuffix(target, "/") + "/" + index
+		default:
+			return match
+		}
+
+		return groups[1] + rewritten + fragment + groups[3]
+	})
+}
+
 // generateConsolidatedMarkdown creates a single markdown file containing all documents.
 // This is useful for consumption by Large Language Models (LLMs).
 func (m *MarkdownCompiler) generateConsolidatedMarkdown(documents []scanner.Document, outputDir string) error {
@@ -152,8 +240,8 @@ func (m *MarkdownCompiler) generateMarkdownTOC(toc *toc.TableOfContents, outputD
 
 	tocMarkdown.WriteString("\n---\n\n")
 	tocMarkdown.WriteString("[View All Documentation](all-docs.md) | ")
-	tocMarkdown.WriteString("[XML TOC](../toc.xml) | ")
-	tocMarkdown.WriteString("[HTML Version](../index.html)\n")
+	tocMarkdown.WriteString("[XML TOC](toc.xml) | ")
+	tocMarkdown.WriteString("[HTML Version](index.html)\n")
 
 	return os.WriteFile(outputPath, []byte(tocMarkdown.String()), 0644)
 }
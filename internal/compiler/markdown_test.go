@@ -0,0 +1,61 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
+)
+
+// TestMarkdownCompiler_Compile tests the markdown mirror: twins next to HTML
+// pages, rewritten internal links and breadcrumbs to existing indexes.
+func TestMarkdownCompiler_Compile(t *testing.T) {
+	documents := []scanner.Document{
+		{Title: "Guide", RelativePath: "guide/README.md", Content: []byte("# Guide")},
+		{Title: "Install", RelativePath: "guide/setup/install.md",
+			Content: []byte("See [the guide](../index.html#intro), [setup](../../guide/), [api](../../api/ref.html) and [site](https://example.com/a.html).")},
+	}
+	outputDir := t.TempDir()
+
+	mirror := NewMarkdownCompiler(outputDir)
+	if err := mirror.Compile(documents, toc.NewBuilder().Build(documents)); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "guide", "setup", "install.md"))
+	if err != nil {
+		t.Fatalf("markdown twin not written: %v", err)
+	}
+	twin := string(data)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"frontmatter", "title: Install\npath: guide/setup/install.md\nhtml: install.html\n"},
+		{"breadcrumb", "📍 [Home](../../index.md) / [guide](../README.md) / setup / install.md"},
+		{"directory link", "[setup](../../guide/README.md)"},
+		{"unknown page kept", "[api](../../api/ref.html)"},
+		{"external link kept", "[site](https://example.com/a.html)"},
+		{"footer", "[← Home](../../index.md) | [View All Docs](../../all-docs.md)"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(twin, tt.want) {
+			t.Errorf("%s: twin missing %q in:\n%s", tt.name, tt.want, twin)
+		}
+	}
+
+	// guide/index.html does not exist, so the link is kept
+	if !strings.Contains(twin, "[the guide](../index.html#intro)") {
+		t.Errorf("twin rewrote a link to a page without a twin:\n%s", twin)
+	}
+
+	for _, name := range []string{"index.md", "all-docs.md", filepath.Join("guide", "README.md")} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+}
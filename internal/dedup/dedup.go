@@ -0,0 +1,210 @@
+// Package dedup detects near-duplicate texts, such as documents copied
+// between product versions, using MinHash signatures over word shingles and
+// locality-sensitive hashing to find candidates without comparing every pair.
+package dedup
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is the default estimated Jaccard similarity above which
+// two texts are near-duplicates.
+const DefaultThreshold = 0.8
+
+const (
+	shingleSize = 3   // Words per shingle
+	numHashes   = 128 // MinHash signature length
+	bandRows    = 4   // Signature rows per LSH band
+	numBands    = numHashes / bandRows
+)
+
+// seeds holds one seed per MinHash function, derived deterministically so
+// that signatures are stable between runs.
+var seeds = func() [numHashes]uint64 {
+	var s [numHashes]uint64
+	for i := range s {
+		s[i] = mix(uint64(i) + 1)
+	}
+	return s
+}()
+
+// Match is the result of adding a near-duplicate to a Detector.
+type Match struct {
+	CanonicalID string  // ID of the earlier text this one duplicates
+	Similarity  float64 // Estimated Jaccard similarity of the two texts (0 to 1)
+}
+
+// Duplicate is a near-duplicate member of a Group.
+type Duplicate struct {
+	ID         string
+	Similarity float64 // Estimated Jaccard similarity to the group's canonical text
+}
+
+// Group is a canonical text together with its near-duplicates, in the order
+// they were added.
+type Group struct {
+	CanonicalID string
+	Duplicates  []Duplicate
+}
+
+// Detector finds near-duplicates among texts added one at a time. The first
+// text of a group is canonical; every later text whose similarity to a
+// canonical text reaches the threshold is its duplicate. Duplicates are
+// matched against canonical texts only, so a group never drifts away from
+// its canonical text through a chain of small edits.
+type Detector struct {
+	threshold  float64
+	ids        []string            // Canonical IDs by index
+	signatures [][numHashes]uint64 // Canonical signatures by index
+	buckets    map[[2]uint64][]int // (band, band hash) -> canonical indexes
+	groups     map[string]*Group   // Canonical ID -> group, once it has a duplicate
+	order      []string            // Canonical IDs of groups in creation order
+}
+
+// NewDetector creates a Detector that reports texts with an estimated
+// similarity of at least threshold (0 to 1) as near-duplicates.
+func NewDetector(threshold float64) *Detector {
+	return &Detector{
+		threshold: threshold,
+		buckets:   make(map[[2]uint64][]int),
+		groups:    make(map[string]*Group),
+	}
+}
+
+// Add records the text with the given ID. If it is a near-duplicate of an
+// earlier canonical text it returns the best match and true; otherwise the
+// text becomes canonical. Texts without words are never duplicates.
+func (d *Detector) Add(id, text string) (Match, bool) {
+	shingles := Shingles(text)
+	if len(shingles) == 0 {
+		return Match{}, false
+	}
+	signature := signatureOf(shingles)
+
+	// Candidates share at least one band with the text
+	best, bestSimilarity := -1, 0.0
+	checked := make(map[int]bool)
+	for band := 0; band < numBands; band++ {
+		for _, candidate := range d.buckets[bandKey(signature, band)] {
+			if checked[candidate] {
+				continue
+			}
+			checked[candidate] = true
+			similarity := estimate(signature, d.signatures[candidate])
+			if similarity >= d.threshold && similarity > bestSimilarity {
+				best, bestSimilarity = candidate, similarity
+			}
+		}
+	}
+
+	if best >= 0 {
+		canonical := d.ids[best]
+		group, ok := d.groups[canonical]
+		if !ok {
+			group = &Group{CanonicalID: canonical}
+			d.groups[canonical] = group
+			d.order = append(d.order, canonical)
+		}
+		group.Duplicates = append(group.Duplicates, Duplicate{ID: id, Similarity: bestSimilarity})
+		return Match{CanonicalID: canonical, Similarity: bestSimilarity}, true
+	}
+
+	index := len(d.ids)
+	d.ids = append(d.ids, id)
+	d.signatures = append(d.signatures, signature)
+	for band := 0; band < numBands; band++ {
+		key := bandKey(signature, band)
+		d.buckets[key] = append(d.buckets[key], index)
+	}
+	return Match{}, false
+}
+
+// Groups returns every canonical text that has near-duplicates, in the order
+// their first duplicate was found.
+func (d *Detector) Groups() []Group {
+	groups := make([]Group, 0, len(d.order))
+	for _, id := range d.order {
+		groups = append(groups, *d.groups[id])
+	}
+	return groups
+}
+
+// Similarity estimates the Jaccard similarity of the shingles of two texts.
+func Similarity(a, b string) float64 {
+	shinglesA, shinglesB := Shingles(a), Shingles(b)
+	if len(shinglesA) == 0 || len(shinglesB) == 0 {
+		return 0
+	}
+	return estimate(signatureOf(shinglesA), signatureOf(shinglesB))
+}
+
+// Shingles returns the set of overlapping three-word sequences of text,
+// compared case-insensitively and ignoring punctuation. Texts shorter than
+// three words yield a single shingle of all their words.
+func Shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	shingles := make(map[string]bool)
+	if len(words) == 0 {
+		return shingles
+	}
+	if len(words) < shingleSize {
+		shingles[strings.Join(words, " ")] = true
+		return shingles
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+	return shingles
+}
+
+// signatureOf computes the MinHash signature of a shingle set.
+func signatureOf(shingles map[string]bool) [numHashes]uint64 {
+	var signature [numHashes]uint64
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+	for shingle := range shingles {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(shingle))
+		base := hasher.Sum64()
+		for i, seed := range seeds {
+			if h := mix(base ^ seed); h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+	return signature
+}
+
+// estimate returns the fraction of equal signature slots, which estimates
+// the Jaccard similarity of the underlying shingle sets.
+func estimate(a, b [numHashes]uint64) float64 {
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / numHashes
+}
+
+// bandKey hashes one band of a signature for the LSH buckets.
+func bandKey(signature [numHashes]uint64, band int) [2]uint64 {
+	h := uint64(band)
+	for _, value := range signature[band*bandRows : (band+1)*bandRows] {
+		h = mix(h ^ value)
+	}
+	return [2]uint64{uint64(band), h}
+}
+
+// mix is the SplitMix64 finalizer, a fast bijective 64-bit hash.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
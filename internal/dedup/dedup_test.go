@@ -0,0 +1,66 @@
+package dedup
+
+import (
+	"strings"
+	"testing"
+)
+
+const installGuide = `To install the command line tool, download the latest release archive for your
+platform from the releases page, extract it into a directory on your PATH and run the
+version command to check that the binary works. On macOS you can also use Homebrew, and
+on Linux the distribution packages are updated within a day of every release.`
+
+// TestSimilarity tests similarity estimates of identical, edited and unrelated texts.
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		min, max float64
+	}{
+		{"identical", installGuide, installGuide, 1, 1},
+		{"whitespace and case", installGuide, strings.ToUpper(strings.Join(strings.Fields(installGuide), "  ")), 1, 1},
+		{"small edit", installGuide, strings.Replace(installGuide, "Homebrew", "MacPorts", 1), 0.8, 0.99},
+		{"unrelated", installGuide, "Search indexes are rebuilt whenever a page changes, and stale entries expire after a week.", 0, 0.1},
+		{"empty", installGuide, "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(tt.a, tt.b)
+			if got < tt.min || got > tt.max {
+				t.Errorf("Similarity() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+// TestDetector tests grouping near-duplicates under the first canonical text.
+func TestDetector(t *testing.T) {
+	detector := NewDetector(DefaultThreshold)
+
+	texts := []struct {
+		id, text  string
+		canonical string // Expected canonical ID, empty when the text is new
+	}{
+		{"v1/install", installGuide, ""},
+		{"search", "Search indexes are rebuilt whenever a page changes, and stale entries expire after a week.", ""},
+		{"v2/install", strings.Replace(installGuide, "Homebrew", "MacPorts", 1), "v1/install"},
+		{"v3/install", installGuide, "v1/install"},
+		{"empty", "  ", ""},
+	}
+
+	for _, tt := range texts {
+		match, ok := detector.Add(tt.id, tt.text)
+		if ok != (tt.canonical != "") || match.CanonicalID != tt.canonical {
+			t.Errorf("Add(%q) = %+v, %v; want canonical %q", tt.id, match, ok, tt.canonical)
+		}
+	}
+
+	groups := detector.Groups()
+	if len(groups) != 1 || groups[0].CanonicalID != "v1/install" || len(groups[0].Duplicates) != 2 {
+		t.Fatalf("Groups() = %+v, want v1/install with two duplicates", groups)
+	}
+	if duplicate := groups[0].Duplicates[1]; duplicate.ID != "v3/install" || duplicate.Similarity != 1 {
+		t.Errorf("Groups() duplicate = %+v, want v3/install with similarity 1", duplicate)
+	}
+}
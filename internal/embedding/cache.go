@@ -0,0 +1,119 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CachedEmbedder wraps another Embedder with an on-disk vector cache keyed by
+// a hash of the provider name and the exact text. Only texts missing from the
+// cache are sent to the wrapped embedder. Failing to write the cache only
+// logs a warning, since the vectors have been computed either way.
+type CachedEmbedder struct {
+	inner  Embedder
+	dir    string
+	warned sync.Once
+}
+
+// NewCachedEmbedder creates a CachedEmbedder storing vectors below dir.
+func NewCachedEmbedder(inner Embedder, dir string) *CachedEmbedder {
+	return &CachedEmbedder{
+		inner: inner,
+		dir:   dir,
+	}
+}
+
+// Name implements the Embedder interface.
+func (c *CachedEmbedder) Name() string {
+	return c.inner.Name()
+}
+
+// Embed implements the Embedder interface.
+func (c *CachedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+
+	var missing []string
+	var missingIdx []int
+	for i, text := range texts {
+		if vector, ok := c.load(text); ok {
+			vectors[i] = vector
+			continue
+		}
+		missing = append(missing, text)
+		missingIdx = append(missingIdx, i)
+	}
+
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	computed, err := c.inner.Embed(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(computed) != len(missing) {
+		return nil, fmt.Errorf("embedder %s returned %d vectors for %d inputs", c.inner.Name(), len(computed), len(missing))
+	}
+
+	for j, vector := range computed {
+		vectors[missingIdx[j]] = vector
+		if err := c.store(missing[j], vector); err != nil {
+			c.warned.Do(func() {
+				log.Printf("Warning: failed to write embedding cache, vectors will be computed again next time: %v", err)
+			})
+		}
+	}
+
+	return vectors, nil
+}
+
+// path returns the cache file path for text.
+func (c *CachedEmbedder) path(text string) string {
+	sum := sha256.Sum256([]byte(c.inner.Name() + "\x00" + text))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".bin")
+}
+
+// load reads a cached vector for text. Unreadable or corrupt entries are
+// treated as cache misses.
+func (c *CachedEmbedder) load(text string) ([]float32, bool) {
+	data, err := os.ReadFile(c.path(text))
+	if err != nil || len(data) == 0 || len(data)%4 != 0 {
+		return nil, false
+	}
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector, true
+}
+
+// store writes vector to the cache as little-endian float32 values. The file
+// is written to a temporary name first so concurrent readers never see a
+// partial entry.
+func (c *CachedEmbedder) store(text string, vector []float32) error {
+	path := c.path(text)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data := make([]byte, len(vector)*4)
+	for i, x := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(x))
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,54 @@
+// Package embedding provides pluggable providers for turning text into vector
+// embeddings, along with an on-disk cache so unchanged chunks are never
+// embedded twice.
+package embedding
+
+import (
+	"context"
+	"math"
+)
+
+// Embedder defines the interface for embedding providers.
+type Embedder interface {
+	// Name identifies the provider and model. It is part of every cache key, so
+	// vectors produced by different models are never mixed up.
+	Name() string
+
+	// Embed returns one vector per input text, in the same order as texts.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors, or 0 if either
+// vector is empty, all zeros, or the lengths differ.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// normalize scales v to unit length in place.
+func normalize(v []float32) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+}
@@ -0,0 +1,202 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// newStubServer returns an httptest server implementing the OpenAI embeddings
+// endpoint. Each input is embedded as [len(text), index]. The first failures
+// requests are answered with 503.
+func newStubServer(t *testing.T, failures int32, requests *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(requests, 1)
+		if r.URL.Path != "/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		if n <= failures {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp embeddingResponse
+		// Respond in reverse order to exercise index sorting
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}{Index: i, Embedding: []float32{float32(len(req.Input[i])), float32(i)}})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+// TestHTTPEmbedder_Batching verifies that inputs are split into batches and
+// that results are returned in input order.
+func TestHTTPEmbedder_Batching(t *testing.T) {
+	var requests int32
+	server := newStubServer(t, 0, &requests)
+	defer server.Close()
+
+	embedder := NewHTTPEmbedder(server.URL, "test-model", "")
+	embedder.SetBatchSize(2)
+
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	vectors, err := embedder.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Embed() made %d requests, want 3", got)
+	}
+	if len(vectors) != len(texts) {
+		t.Fatalf("Embed() returned %d vectors, want %d", len(vectors), len(texts))
+	}
+	for i, text := range texts {
+		if vectors[i][0] != float32(len(text)) {
+			t.Errorf("vector %d = %v, want first component %d", i, vectors[i], len(text))
+		}
+	}
+}
+
+// TestHTTPEmbedder_Retries verifies that transient server errors are retried.
+func TestHTTPEmbedder_Retries(t *testing.T) {
+	var requests int32
+	server := newStubServer(t, 2, &requests)
+	defer server.Close()
+
+	embedder := NewHTTPEmbedder(server.URL, "test-model", "")
+	embedder.retryDelay = 0
+
+	if _, err := embedder.Embed(context.Background(), []string{"hello"}); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Embed() made %d requests, want 3", got)
+	}
+
+	// Exhausting retries surfaces the error
+	requests = 0
+	failing := newStubServer(t, 100, &requests)
+	defer failing.Close()
+
+	embedder = NewHTTPEmbedder(failing.URL, "test-model", "")
+	embedder.retryDelay = 0
+	embedder.SetMaxRetries(1)
+	if _, err := embedder.Embed(context.Background(), []string{"hello"}); err == nil {
+		t.Error("Embed() expected error after exhausting retries")
+	}
+}
+
+// TestHashEmbedder verifies that the hash embedder is deterministic and that
+// texts sharing vocabulary are more similar than unrelated texts.
+func TestHashEmbedder(t *testing.T) {
+	embedder := NewHashEmbedder(128)
+
+	vectors, err := embedder.Embed(context.Background(), []string{
+		"vector database stores embeddings",
+		"a vector database stores embeddings for search",
+		"baking bread requires flour and yeast",
+	})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	again, _ := embedder.Embed(context.Background(), []string{"vector database stores embeddings"})
+	if CosineSimilarity(vectors[0], again[0]) < 0.9999 {
+		t.Error("HashEmbedder is not deterministic")
+	}
+
+	related := CosineSimilarity(vectors[0], vectors[1])
+	unrelated := CosineSimilarity(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("related similarity %.3f should exceed unrelated similarity %.3f", related, unrelated)
+	}
+}
+
+// countingEmbedder records how many texts it has been asked to embed.
+type countingEmbedder struct {
+	inner Embedder
+	count int
+}
+
+func (c *countingEmbedder) Name() string { return c.inner.Name() }
+
+func (c *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	c.count += len(texts)
+	return c.inner.Embed(ctx, texts)
+}
+
+// TestCachedEmbedder verifies that cached texts are not embedded again.
+func TestCachedEmbedder(t *testing.T) {
+	inner := &countingEmbedder{inner: NewHashEmbedder(16)}
+	cached := NewCachedEmbedder(inner, t.TempDir())
+
+	first, err := cached.Embed(context.Background(), []string{"alpha", "beta"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	second, err := cached.Embed(context.Background(), []string{"beta", "gamma", "alpha"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+
+	if inner.count != 3 {
+		t.Errorf("inner embedder called for %d texts, want 3", inner.count)
+	}
+	if CosineSimilarity(first[0], second[2]) < 0.9999 || CosineSimilarity(first[1], second[0]) < 0.9999 {
+		t.Error("cached vectors differ from originally computed vectors")
+	}
+}
+
+// TestCachedEmbedder_WriteFailure verifies that an unwritable cache does not
+// fail an embedding request.
+func TestCachedEmbedder_WriteFailure(t *testing.T) {
+	// A file where the cache directory should be makes every write fail
+	dir := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cached := NewCachedEmbedder(NewHashEmbedder(16), dir)
+
+	vectors, err := cached.Embed(context.Background(), []string{"alpha", "beta"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v, want vectors despite the cache failure", err)
+	}
+	if len(vectors) != 2 || len(vectors[0]) != 16 {
+		t.Errorf("Embed() = %d vectors, want 2 of 16 dimensions", len(vectors))
+	}
+}
+
+// TestNewEmbedder verifies provider selection and configuration errors.
+func TestNewEmbedder(t *testing.T) {
+	if _, err := NewEmbedder(Config{Provider: "hash"}); err != nil {
+		t.Errorf("NewEmbedder(hash) error = %v", err)
+	}
+	if _, err := NewEmbedder(Config{Provider: "openai", BaseURL: "http://localhost:11434/v1"}); err != nil {
+		t.Errorf("NewEmbedder(local openai) error = %v", err)
+	}
+	if _, err := NewEmbedder(Config{Provider: "openai"}); err == nil {
+		t.Error("NewEmbedder(openai) without API key should fail")
+	}
+	if _, err := NewEmbedder(Config{Provider: "unknown"}); err == nil {
+		t.Error("NewEmbedder(unknown) should fail")
+	}
+}
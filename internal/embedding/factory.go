@@ -0,0 +1,63 @@
+package embedding
+
+import (
+	"fmt"
+)
+
+// Config describes which embedding provider to use and how to reach it.
+type Config struct {
+	Provider   string // Provider name: "openai" (any OpenAI-compatible server) or "hash"
+	BaseURL    string // Base URL of the OpenAI-compatible API (e.g. http://localhost:11434/v1)
+	Model      string // Embedding model name
+	APIKey     string // API key, if the server requires one
+	BatchSize  int    // Maximum texts per request (0 uses DefaultBatchSize)
+	Dimensions int    // Vector size for the hash provider (0 uses DefaultHashDimensions)
+	CacheDir   string // Directory for the vector cache; caching is disabled when empty
+}
+
+// NewEmbedder creates an Embedder from the given configuration.
+// Supported providers:
+//   - "openai": OpenAI-compatible HTTP API (also Ollama, LM Studio, llama.cpp)
+//   - "hash": Local hashed bag-of-words vectors, no network required
+//
+// Returns an error if the provider is unknown or the configuration is incomplete.
+func NewEmbedder(cfg Config) (Embedder, error) {
+	var embedder Embedder
+
+	switch cfg.Provider {
+	case "", "openai":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = DefaultBaseURL
+		}
+		if baseURL == DefaultBaseURL && cfg.APIKey == "" {
+			return nil, fmt.Errorf("embedding provider %q requires an API key (set OPENAI_API_KEY or llm.embeddings.api_key), or point llm.embeddings.url at a local server, or use the offline hash provider (--embeddings-provider hash)", "openai")
+		}
+		httpEmbedder := NewHTTPEmbedder(baseURL, cfg.Model, cfg.APIKey)
+		httpEmbedder.SetBatchSize(cfg.BatchSize)
+		embedder = httpEmbedder
+	case "hash":
+		embedder = NewHashEmbedder(cfg.Dimensions)
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s (supported: %v)", cfg.Provider, AvailableProviders())
+	}
+
+	if cfg.CacheDir != "" {
+		embedder = NewCachedEmbedder(embedder, cfg.CacheDir)
+	}
+
+	return embedder, nil
+}
+
+// AvailableProviders returns a list of all available embedding provider names.
+func AvailableProviders() []string {
+	return []string{
+		"openai",
+		"hash",
+	}
+}
+
+// IsRemote reports whether the configured provider sends text over the network.
+func (cfg Config) IsRemote() bool {
+	return cfg.Provider != "hash"
+}
@@ -0,0 +1,76 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// DefaultHashDimensions is the vector size used by the hash provider when none is configured.
+const DefaultHashDimensions = 256
+
+// HashEmbedder is a purely local, deterministic embedder based on the hashing
+// trick: every lower-cased word is hashed into one of a fixed number of
+// buckets and the resulting bag-of-words vector is normalized to unit length.
+// It needs no model or network access, which makes it suitable for offline
+// builds and tests; texts sharing vocabulary end up with similar vectors.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a HashEmbedder producing vectors of the given size.
+// A non-positive size selects DefaultHashDimensions.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultHashDimensions
+	}
+	return &HashEmbedder{
+		dimensions: dimensions,
+	}
+}
+
+// Name implements the Embedder interface.
+func (h *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-%d", h.dimensions)
+}
+
+// Embed implements the Embedder interface.
+func (h *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed computes the hashed bag-of-words vector for a single text.
+func (h *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, h.dimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(word))
+		sum := hasher.Sum64()
+
+		// The low bits pick the bucket and the top bit picks the sign, which
+		// keeps collisions from systematically inflating similarity.
+		bucket := int(sum % uint64(h.dimensions))
+		if sum>>63 == 1 {
+			vector[bucket]--
+		} else {
+			vector[bucket]++
+		}
+	}
+
+	normalize(vector)
+	return vector
+}
@@ -0,0 +1,195 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the OpenAI API endpoint used when no base URL is configured.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// DefaultModel is the embedding model requested when none is configured.
+const DefaultModel = "text-embedding-3-small"
+
+// DefaultBatchSize is the number of texts sent per embeddings request.
+const DefaultBatchSize = 64
+
+// DefaultMaxRetries is the number of times a failed request is retried.
+const DefaultMaxRetries = 3
+
+// HTTPEmbedder calls an OpenAI-compatible /embeddings endpoint. Besides the
+// OpenAI API it works with local servers exposing the same API, such as
+// Ollama, LM Studio or llama.cpp, and with test stubs.
+type HTTPEmbedder struct {
+	baseURL    string
+	model      string
+	apiKey     string
+	batchSize  int
+	maxRetries int
+	retryDelay time.Duration
+	client     *http.Client
+}
+
+// NewHTTPEmbedder creates an HTTPEmbedder for the given endpoint and model.
+// The API key may be empty for local servers that do not require one.
+func NewHTTPEmbedder(baseURL, model, apiKey string) *HTTPEmbedder {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if model == "" {
+		model = DefaultModel
+	}
+	return &HTTPEmbedder{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		apiKey:     apiKey,
+		batchSize:  DefaultBatchSize,
+		maxRetries: DefaultMaxRetries,
+		retryDelay: 500 * time.Millisecond,
+		client:     &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// SetBatchSize sets the maximum number of texts sent in a single request.
+func (h *HTTPEmbedder) SetBatchSize(size int) {
+	if size > 0 {
+		h.batchSize = size
+	}
+}
+
+// SetMaxRetries sets how many times a failed request is retried.
+func (h *HTTPEmbedder) SetMaxRetries(retries int) {
+	if retries >= 0 {
+		h.maxRetries = retries
+	}
+}
+
+// Name implements the Embedder interface.
+func (h *HTTPEmbedder) Name() string {
+	return "http:" + h.baseURL + ":" + h.model
+}
+
+// embeddingRequest is the request body of an OpenAI-compatible embeddings call.
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the response body of an OpenAI-compatible embeddings call.
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed implements the Embedder interface. Texts are sent in batches of at
+// most the configured batch size.
+func (h *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+
+	for start := 0; start < len(texts); start += h.batchSize {
+		end := start + h.batchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		batch, err := h.embedBatchWithRetry(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+
+	return vectors, nil
+}
+
+// embedBatchWithRetry embeds a single batch, retrying transient failures with
+// exponential backoff.
+func (h *HTTPEmbedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+	var lastErr error
+	delay := h.retryDelay
+
+	for attempt := 0; attempt <= h.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		vectors, retryable, err := h.embedBatch(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		if !retryable {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("embedding request failed after %d attempts: %w", h.maxRetries+1, lastErr)
+}
+
+// embedBatch performs a single embeddings request. The returned bool reports
+// whether the failure is worth retrying (network errors, 429 and 5xx).
+func (h *HTTPEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, bool, error) {
+	body, err := json.Marshal(embeddingRequest{Model: h.model, Input: texts})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read embedding response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, fmt.Errorf("embedding request returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var parsed embeddingResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, false, fmt.Errorf("failed to parse embedding response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, false, fmt.Errorf("embedding response contained %d vectors for %d inputs", len(parsed.Data), len(texts))
+	}
+
+	// Servers are allowed to return results out of order
+	sort.Slice(parsed.Data, func(i, j int) bool {
+		return parsed.Data[i].Index < parsed.Data[j].Index
+	})
+
+	vectors := make([][]float32, len(parsed.Data))
+	for i, item := range parsed.Data {
+		vectors[i] = item.Embedding
+	}
+
+	return vectors, false, nil
+}
@@ -0,0 +1,174 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultMaxTokens limits the length of generated context blurbs.
+const DefaultMaxTokens = 150
+
+// Completer defines the interface for text completion providers used to
+// generate per-chunk context blurbs.
+type Completer interface {
+	// Name identifies the provider and model. It is part of every cache key, so
+	// blurbs produced by different models are never mixed up.
+	Name() string
+
+	// Complete returns the model's answer to prompt.
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// HTTPCompleter calls an OpenAI-compatible /chat/completions endpoint, such as
+// the OpenAI API or a local Ollama, LM Studio or llama.cpp server.
+type HTTPCompleter struct {
+	baseURL   string
+	model     string
+	apiKey    string
+	maxTokens int
+	client    *http.Client
+}
+
+// NewHTTPCompleter creates an HTTPCompleter for the given endpoint and model.
+// The API key may be empty for local servers that do not require one.
+func NewHTTPCompleter(baseURL, model, apiKey string) *HTTPCompleter {
+	return &HTTPCompleter{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		model:     model,
+		apiKey:    apiKey,
+		maxTokens: DefaultMaxTokens,
+		client:    &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// Name implements the Completer interface.
+func (h *HTTPCompleter) Name() string {
+	return "http:" + h.baseURL + ":" + h.model
+}
+
+// chatMessage is a single message of an OpenAI-compatible chat request.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest is the request body of an OpenAI-compatible chat completion call.
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+// chatResponse is the response body of an OpenAI-compatible chat completion call.
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Complete implements the Completer interface. Generation is deterministic
+// (temperature 0) so repeated exports produce the same blurbs.
+func (h *HTTPCompleter) Complete(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:     h.model,
+		Messages:  []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens: h.maxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create completion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("completion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read completion response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("completion request returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse completion response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("completion response contained no choices")
+	}
+
+	return strings.TrimSpace(parsed.Choices[0].Message.Content), nil
+}
+
+// CachedCompleter wraps another Completer with an on-disk cache keyed by a
+// hash of the provider name and the prompt. Because the prompt embeds the
+// chunk text, every chunk is completed at most once until it changes.
+type CachedCompleter struct {
+	inner Completer
+	dir   string
+}
+
+// NewCachedCompleter creates a CachedCompleter storing answers below dir.
+func NewCachedCompleter(inner Completer, dir string) *CachedCompleter {
+	return &CachedCompleter{
+		inner: inner,
+		dir:   dir,
+	}
+}
+
+// Name implements the Completer interface.
+func (c *CachedCompleter) Name() string {
+	return c.inner.Name()
+}
+
+// Complete implements the Completer interface.
+func (c *CachedCompleter) Complete(ctx context.Context, prompt string) (string, error) {
+	sum := sha256.Sum256([]byte(c.inner.Name() + "\x00" + prompt))
+	key := hex.EncodeToString(sum[:])
+	path := filepath.Join(c.dir, key[:2], key+".txt")
+
+	if data, err := os.ReadFile(path); err == nil {
+		return string(data), nil
+	}
+
+	answer, err := c.inner.Complete(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to write completion cache: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(answer), 0644); err != nil {
+		return "", fmt.Errorf("failed to write completion cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("failed to write completion cache: %w", err)
+	}
+
+	return answer, nil
+}
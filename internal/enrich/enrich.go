@@ -0,0 +1,219 @@
+// Package enrich implements contextual retrieval enrichment: every chunk gets
+// a short preamble situating it within its document, so that the chunk can be
+// understood (and retrieved) in isolation.
+//
+// The preamble is built deterministically from the document title, the
+// chunk's heading path, a document summary and the titles of neighboring
+// sections. Optionally a Completer adds a model-generated blurb, following
+// https://www.anthropic.com/news/contextual-retrieval.
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+// maxSummaryLength is the maximum length of the generated document summary in bytes.
+const maxSummaryLength = 240
+
+// maxPromptDocument is the maximum number of bytes of the document included
+// in completion prompts, keeping prompts within small local context windows.
+const maxPromptDocument = 24000
+
+// promptTemplate is the completion prompt, filled with the document and the chunk.
+const promptTemplate = `<document>
+%s
+</document>
+Here is the chunk we want to situate within the whole document
+<chunk>
+%s
+</chunk>
+Please give a short succinct context to situate this chunk within the overall document for the purposes of improving search retrieval of the chunk. Answer only with the succinct context and nothing else.`
+
+// Enricher generates context preambles for chunks.
+type Enricher struct {
+	completer Completer // Optional; when set, a generated blurb is appended
+}
+
+// NewEnricher creates an Enricher producing deterministic preambles only.
+func NewEnricher() *Enricher {
+	return &Enricher{}
+}
+
+// SetCompleter configures the completion provider used to generate a
+// per-chunk blurb. Passing nil disables generated blurbs.
+func (e *Enricher) SetCompleter(completer Completer) {
+	e.completer = completer
+}
+
+// Enrich sets the Context field of every chunk of doc.
+func (e *Enricher) Enrich(ctx context.Context, doc scanner.Document, chunks []chunk.Chunk) error {
+	sections := doc.Sections
+	if len(sections) == 0 {
+		sections = doc.ExtractSections()
+	}
+	summary := Summary(doc)
+
+	for i := range chunks {
+		preamble, err := e.context(ctx, doc, sections, summary, chunks[i])
+		if err != nil {
+			return fmt.Errorf("failed to enrich %s: %w", chunks[i].ID, err)
+		}
+		chunks[i].Context = preamble
+	}
+	return nil
+}
+
+// context builds the preamble for a single chunk.
+func (e *Enricher) context(ctx context.Context, doc scanner.Document, sections []scanner.Section, summary string, c chunk.Chunk) (string, error) {
+	var lines []string
+
+	if doc.Title != "" {
+		lines = append(lines, "Document: "+doc.Title)
+	}
+
+	idx := sectionIndex(doc, sections, c.StartPos)
+	path := c.HeadingPath
+	if len(path) == 0 {
+		path = headingPath(sections, idx)
+	}
+	if len(path) > 0 {
+		lines = append(lines, "Section: "+strings.Join(path, " > "))
+	}
+
+	if summary != "" {
+		lines = append(lines, "Summary: "+summary)
+	}
+
+	var neighbors []string
+	if idx > 0 {
+		neighbors = append(neighbors, "previous: "+sections[idx-1].Title)
+	}
+	if idx >= 0 && idx < len(sections)-1 {
+		neighbors = append(neighbors, "next: "+sections[idx+1].Title)
+	}
+	if len(neighbors) > 0 {
+		lines = append(lines, "Nearby sections: "+strings.Join(neighbors, "; "))
+	}
+
+	if e.completer != nil {
+		document := cutBytes(string(doc.Content), maxPromptDocument)
+		blurb, err := e.completer.Complete(ctx, fmt.Sprintf(promptTemplate, document, c.Text))
+		if err != nil {
+			return "", err
+		}
+		if blurb != "" {
+			lines = append(lines, blurb)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// Summary returns a short description of doc: the description frontmatter
+// field if present, otherwise the leading sentences of the first paragraph
+// of prose, truncated to a fixed length at a word boundary.
+func Summary(doc scanner.Document) string {
+	if description, ok := doc.Metadata["description"].(string); ok && strings.TrimSpace(description) != "" {
+		return truncate(strings.Join(strings.Fields(description), " "))
+	}
+
+	inFence := false
+	var paragraph []string
+	for _, line := range strings.Split(string(doc.Content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "|") ||
+			strings.HasPrefix(trimmed, "<") || strings.HasPrefix(trimmed, "![") {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+
+	return truncate(strings.Join(paragraph, " "))
+}
+
+// truncate shortens text to maxSummaryLength, preferring a sentence end and
+// falling back to a word boundary.
+func truncate(text string) string {
+	if len(text) <= maxSummaryLength {
+		return text
+	}
+
+	cut := cutBytes(text, maxSummaryLength)
+	if end := strings.LastIndex(cut, ". "); end > maxSummaryLength/2 {
+		return cut[:end+1]
+	}
+	if space := strings.LastIndexByte(cut, ' '); space > 0 {
+		cut = cut[:space]
+	}
+	return cut + "..."
+}
+
+// cutBytes returns the longest prefix of s of at most n bytes that does not
+// split a multi-byte character.
+func cutBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// sectionIndex returns the index of the section containing the byte offset,
+// or -1 if the offset precedes the first heading.
+func sectionIndex(doc scanner.Document, sections []scanner.Section, offset int) int {
+	if offset > len(doc.Content) {
+		offset = len(doc.Content)
+	}
+	line := strings.Count(string(doc.Content[:offset]), "\n")
+
+	idx := -1
+	for i, section := range sections {
+		if section.StartLine > line {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// headingPath returns the titles of the section at idx and its ancestors,
+// outermost first.
+func headingPath(sections []scanner.Section, idx int) []string {
+	if idx < 0 {
+		return nil
+	}
+
+	path := []string{sections[idx].Title}
+	level := sections[idx].Level
+	for i := idx - 1; i >= 0 && level > 1; i-- {
+		if sections[i].Level < level {
+			path = append([]string{sections[i].Title}, path...)
+			level = sections[i].Level
+		}
+	}
+	return path
+}
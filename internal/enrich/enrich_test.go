@@ -0,0 +1,187 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+const testContent = "# Guide\n\nJot builds documentation sites. It also exports chunks.\n\n" +
+	"## Install\n\nRun the installer.\n\n### Options\n\nPass flags.\n\n## Usage\n\nRun jot build."
+
+// TestEnrich tests the deterministic preamble for chunks in different sections.
+func TestEnrich(t *testing.T) {
+	doc := scanner.Document{ID: "doc", Title: "Guide", Content: []byte(testContent)}
+	options := strings.Index(testContent, "Pass flags.")
+
+	chunks := []chunk.Chunk{
+		{ID: "c0", Text: "Jot builds", StartPos: 9},
+		{ID: "c1", Text: "Pass flags.", StartPos: options},
+		{ID: "c2", Text: "Pass flags.", StartPos: options, HeadingPath: []string{"Custom", "Path"}},
+	}
+
+	if err := NewEnricher().Enrich(context.Background(), doc, chunks); err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+
+	tests := []struct {
+		chunk int
+		want  []string
+	}{
+		{0, []string{"Document: Guide", "Section: Guide\n", "Summary: Jot builds documentation sites. It also exports chunks.", "Nearby sections: next: Install"}},
+		{1, []string{"Section: Guide > Install > Options", "Nearby sections: previous: Install; next: Usage"}},
+		{2, []string{"Section: Custom > Path"}},
+	}
+
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(chunks[tt.chunk].Context, want) {
+				t.Errorf("chunk %d context = %q, want it to contain %q", tt.chunk, chunks[tt.chunk].Context, want)
+			}
+		}
+	}
+}
+
+// TestSummary tests summary selection and truncation.
+func TestSummary(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	// A Japanese paragraph without spaces, offset by one byte so that the
+	// length limit falls inside a three-byte character
+	japanese := "a" + strings.Repeat("日本語のドキュメント", 20)
+
+	tests := []struct {
+		name string
+		doc  scanner.Document
+		want string
+	}{
+		{
+			name: "frontmatter description",
+			doc: scanner.Document{
+				Content:  []byte("# Title\n\nBody."),
+				Metadata: map[string]interface{}{"description": "From  frontmatter."},
+			},
+			want: "From frontmatter.",
+		},
+		{
+			name: "skips headings and code",
+			doc:  scanner.Document{Content: []byte("# Title\n\n```\ncode\n```\n\nFirst line\nof prose.\n\nSecond paragraph.")},
+			want: "First line of prose.",
+		},
+		{
+			name: "truncates at word boundary",
+			doc:  scanner.Document{Content: []byte(long)},
+			want: strings.TrimSpace(strings.Repeat("word ", 48)) + "...",
+		},
+		{
+			name: "truncates at character boundary",
+			doc:  scanner.Document{Content: []byte(japanese)},
+			want: string([]rune(japanese)[:80]) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summary(tt.doc); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingCompleter answers every prompt with a fixed blurb and records how
+// often it was called.
+type countingCompleter struct {
+	calls  int
+	prompt string // Last prompt
+}
+
+func (c *countingCompleter) Name() string { return "counting" }
+
+func (c *countingCompleter) Complete(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	c.prompt = prompt
+	return "Generated blurb.", nil
+}
+
+// TestEnrich_Completer tests that generated blurbs are appended and cached.
+func TestEnrich_Completer(t *testing.T) {
+	doc := scanner.Document{ID: "doc", Title: "Guide", Content: []byte(testContent)}
+	inner := &countingCompleter{}
+
+	enricher := NewEnricher()
+	enricher.SetCompleter(NewCachedCompleter(inner, t.TempDir()))
+
+	for i := 0; i < 2; i++ {
+		chunks := []chunk.Chunk{{ID: "c0", Text: "Run the installer."}}
+		if err := enricher.Enrich(context.Background(), doc, chunks); err != nil {
+			t.Fatalf("Enrich() error = %v", err)
+		}
+		if !strings.HasSuffix(chunks[0].Context, "\nGenerated blurb.") {
+			t.Errorf("context = %q, want generated blurb at the end", chunks[0].Context)
+		}
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("completer called %d times, want 1", inner.calls)
+	}
+}
+
+// TestEnrich_CompleterLongDocument tests that documents cut to the prompt
+// limit stay valid UTF-8.
+func TestEnrich_CompleterLongDocument(t *testing.T) {
+	content := "a" + strings.Repeat("日本語のドキュメントです。", maxPromptDocument/30)
+	doc := scanner.Document{ID: "doc", Title: "ガイド", Content: []byte(content)}
+	completer := &countingCompleter{}
+
+	enricher := NewEnricher()
+	enricher.SetCompleter(completer)
+
+	chunks := []chunk.Chunk{{ID: "c0", Text: "日本語のドキュメントです。"}}
+	if err := enricher.Enrich(context.Background(), doc, chunks); err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	if !utf8.ValidString(completer.prompt) {
+		t.Errorf("prompt is not valid UTF-8")
+	}
+	if !utf8.ValidString(chunks[0].Context) {
+		t.Errorf("context %q is not valid UTF-8", chunks[0].Context)
+	}
+}
+
+// TestHTTPCompleter tests the OpenAI-compatible chat completion request.
+func TestHTTPCompleter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "local-model" || len(req.Messages) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  Situated.  "}}]}`))
+	}))
+	defer server.Close()
+
+	completer := NewHTTPCompleter(server.URL+"/", "local-model", "")
+	answer, err := completer.Complete(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if answer != "Situated." {
+		t.Errorf("Complete() = %q, want %q", answer, "Situated.")
+	}
+
+	failing := NewHTTPCompleter(server.URL+"/missing", "local-model", "")
+	if _, err := failing.Complete(context.Background(), "prompt"); err == nil {
+		t.Error("Complete() expected error for non-200 response")
+	}
+}
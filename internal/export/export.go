@@ -8,6 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/embedding"
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 	"github.com/spf13/viper"
@@ -16,7 +19,11 @@ import (
 
 // Exporter handles the conversion of scanned documents into different data formats.
 type Exporter struct {
-	// Configuration can be added here in the future, e.g., for controlling output details.
+	embedder  embedding.Embedder     // Optional; when set, LLM export chunks carry vectors
+	strategy  chunking.ChunkStrategy // Optional; defaults to fixed-size chunking
+	tokenizer tokenizer.Tokenizer    // Optional; defaults to cl100k_base for fixed-size chunking
+	chunkSize int                    // Optional; defaults to llm.chunk_size and llm.overlap
+	overlap   int                    // Used with chunkSize
 }
 
 // NewExporter creates and returns a new Exporter instance.
@@ -24,6 +31,33 @@ func NewExporter() *Exporter {
 	return &Exporter{}
 }
 
+// SetTokenizer configures the tokenizer used by the default chunking and
+// recorded in the export so consumers know how token counts were computed.
+// It should match the tokenizer of any configured strategy.
+func (e *Exporter) SetTokenizer(tok tokenizer.Tokenizer) {
+	e.tokenizer = tok
+}
+
+// SetStrategy configures the chunking strategy used by the LLM export format.
+// Passing nil restores the default fixed-size chunking.
+func (e *Exporter) SetStrategy(strategy chunking.ChunkStrategy) {
+	e.strategy = strategy
+}
+
+// SetChunkSize configures the maximum tokens per chunk and the overlap
+// between chunks of the LLM export format. A chunk size of zero restores the
+// llm.chunk_size and llm.overlap settings.
+func (e *Exporter) SetChunkSize(chunkSize, overlap int) {
+	e.chunkSize = chunkSize
+	e.overlap = overlap
+}
+
+// SetEmbedder configures the embedder used to populate chunk vectors in the
+// LLM export format. Passing nil disables embeddings.
+func (e *Exporter) SetEmbedder(embedder embedding.Embedder) {
+	e.embedder = embedder
+}
+
 // ToJSON exports a slice of documents to a JSON formatted string.
 func (e *Exporter) ToJSON(documents []scanner.Document) (string, error) {
 	export := e.createExportData(documents)
@@ -51,26 +85,30 @@ func (e *Exporter) ToYAML(documents []scanner.Document) (string, error) {
 // ToLLMFormat exports documents to a structure optimized for consumption by Large Language Models.
 // This format includes chunking, sectioning, and metadata extraction.
 func (e *Exporter) ToLLMFormat(documents []scanner.Document) (*LLMExport, error) {
-	// Initialize tokenizer for accurate token-based chunking
-	tok, err := tokenizer.NewTokenizer()
+	tok, err := resolveTokenizer(e.tokenizer, e.strategy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize tokenizer: %w", err)
+		return nil, err
 	}
 
-	// Read chunking configuration from viper with sensible defaults
-	chunkSize := viper.GetInt("llm.chunk_size")
+	// Read chunking configuration from viper with sensible defaults unless
+	// it was set explicitly
+	chunkSize, overlap := e.chunkSize, e.overlap
 	if chunkSize == 0 {
-		chunkSize = 512
-	}
+		chunkSize = viper.GetInt("llm.chunk_size")
+		if chunkSize == 0 {
+			chunkSize = 512
+		}
 
-	overlap := viper.GetInt("llm.overlap")
-	if overlap == 0 {
-		overlap = 128
+		overlap = viper.GetInt("llm.overlap")
+		if overlap == 0 {
+			overlap = 128
+		}
 	}
 
 	export := &LLMExport{
-		Version:   "1.0",
+		Version:   LLMFormatVersion,
 		Generated: time.Now().Format(time.RFC3339),
+		Tokenizer: encodingName(tok),
 		Documents: make([]LLMDocument, 0, len(documents)),
 		Index: &SemanticIndex{
 			Keywords: make(map[string][]string),
@@ -78,13 +116,31 @@ func (e *Exporter) ToLLMFormat(documents []scanner.Document) (*LLMExport, error)
 		},
 	}
 
+	// Rank keywords against the whole export so the index favours terms that
+	// distinguish documents from one another
+	corpus := make([]string, 0, len(documents))
 	for _, doc := range documents {
+		corpus = append(corpus, doc.Title+"\n"+string(doc.Content))
+	}
+	extractor := keywords.NewExtractor(corpus)
+
+	for _, doc := range documents {
+		chunks, err := chunkDocumentWith(e.strategy, doc, chunkSize, overlap, tok)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chunk %s: %w", doc.RelativePath, err)
+		}
+		if e.embedder != nil {
+			if err := embedChunks(e.embedder, chunks); err != nil {
+				return nil, fmt.Errorf("failed to embed %s: %w", doc.RelativePath, err)
+			}
+		}
+
 		llmDoc := LLMDocument{
 			ID:       doc.ID,
 			Title:    doc.Title,
 			Path:     doc.RelativePath,
 			Content:  string(doc.Content),
-			Chunks:   chunkDocument(doc, chunkSize, overlap, tok),
+			Chunks:   chunks,
 			Metadata: doc.Metadata,
 		}
 
@@ -121,89 +177,86 @@ func (e *Exporter) ToLLMFormat(documents []scanner.Document) (*LLMExport, error)
 		export.Documents = append(export.Documents, llmDoc)
 
 		// Build index
-		e.indexDocument(&llmDoc, export.Index)
+		e.indexDocument(&llmDoc, export.Index, extractor)
 	}
 
 	return export, nil
 }
 
-// createExportData transforms a slice of scanner.Document into a generic map structure
-// suitable for JSON or YAML serialization.
-func (e *Exporter) createExportData(documents []scanner.Document) map[string]interface{} {
-	docs := make([]map[string]interface{}, 0, len(documents))
+// createExportData transforms a slice of scanner.Document into the
+// structure serialized by the JSON and YAML formats.
+func (e *Exporter) createExportData(documents []scanner.Document) *DocumentExport {
+	export := &DocumentExport{
+		Version:   JSONFormatVersion,
+		Generated: time.Now().Format(time.RFC3339),
+		Documents: make([]ExportDocument, 0, len(documents)),
+	}
 
 	for _, doc := range documents {
-		docData := map[string]interface{}{
-			"id":       doc.ID,
-			"path":     doc.RelativePath,
-			"title":    doc.Title,
-			"content":  string(doc.Content),
-			"metadata": doc.Metadata,
-			"modified": doc.ModTime.Format(time.RFC3339),
+		docData := ExportDocument{
+			ID:       doc.ID,
+			Path:     doc.RelativePath,
+			Title:    doc.Title,
+			Content:  string(doc.Content),
+			Metadata: doc.Metadata,
+			Modified: doc.ModTime.Format(time.RFC3339),
 		}
 
 		// Add sections
-		if len(doc.Sections) > 0 {
-			sections := make([]map[string]interface{}, 0, len(doc.Sections))
-			for _, section := range doc.Sections {
-				sections = append(sections, map[string]interface{}{
-					"id":         section.ID,
-					"title":      section.Title,
-					"level":      section.Level,
-					"content":    section.Content,
-					"start_line": section.StartLine,
-					"end_line":   section.EndLine,
-				})
-			}
-			docData["sections"] = sections
+		for _, section := range doc.Sections {
+			docData.Sections = append(docData.Sections, LLMSection{
+				ID:        section.ID,
+				Title:     section.Title,
+				Level:     section.Level,
+				Content:   section.Content,
+				StartLine: section.StartLine,
+				EndLine:   section.EndLine,
+			})
 		}
 
 		// Add code blocks
-		if len(doc.CodeBlocks) > 0 {
-			blocks := make([]map[string]interface{}, 0, len(doc.CodeBlocks))
-			for _, block := range doc.CodeBlocks {
-				blocks = append(blocks, map[string]interface{}{
-					"language":   block.Language,
-					"content":    block.Content,
-					"start_line": block.StartLine,
-				})
-			}
-			docData["code_blocks"] = blocks
+		for _, block := range doc.CodeBlocks {
+			docData.CodeBlocks = append(docData.CodeBlocks, LLMCodeBlock{
+				Language:  block.Language,
+				Content:   block.Content,
+				StartLine: block.StartLine,
+			})
 		}
 
 		// Add links
 		if len(doc.Links) > 0 {
-			internal := make([]string, 0)
-			external := make([]string, 0)
+			links := &Links{Internal: []string{}, External: []string{}}
 			for _, link := range doc.Links {
 				if link.IsInternal {
-					internal = append(internal, link.URL)
+					links.Internal = append(links.Internal, link.URL)
 				} else {
-					external = append(external, link.URL)
+					links.External = append(links.External, link.URL)
 				}
 			}
-			docData["links"] = map[string]interface{}{
-				"internal": internal,
-				"external": external,
+			docData.Links = links
+		}
+
+		// Add git history
+		if doc.Git != nil {
+			docData.Git = &GitMetadata{
+				Commit:  doc.Git.Commit,
+				Updated: doc.Git.Updated.Format(time.RFC3339),
+				Authors: doc.Git.Authors,
+				Path:    doc.Git.Path,
 			}
 		}
 
-		docs = append(docs, docData)
+		export.Documents = append(export.Documents, docData)
 	}
 
-	return map[string]interface{}{
-		"version":   "1.0",
-		"generated": time.Now().Format(time.RFC3339),
-		"documents": docs,
-	}
+	return export
 }
 
-// indexDocument builds a simple semantic index for a document by extracting keywords
-// and concepts, and adds them to the provided SemanticIndex.
-func (e *Exporter) indexDocument(doc *LLMDocument, index *SemanticIndex) {
+// indexDocument builds a simple semantic index for a document by extracting ranked
+// keywords and concepts, and adds them to the provided SemanticIndex.
+func (e *Exporter) indexDocument(doc *LLMDocument, index *SemanticIndex, extractor *keywords.Extractor) {
 	// Extract keywords from title and content
-	keywords := extractKeywords(doc.Title + " " + doc.Content)
-	for _, keyword := range keywords {
+	for _, keyword := range extractor.Extract(doc.Title+"\n"+doc.Content, keywords.DefaultLimit) {
 		if _, exists := index.Keywords[keyword]; !exists {
 			index.Keywords[keyword] = make([]string, 0)
 		}
@@ -221,150 +274,42 @@ func (e *Exporter) indexDocument(doc *LLMDocument, index *SemanticIndex) {
 
 // chunkDocument splits a document's content into smaller, potentially overlapping chunks.
 // This is useful for processing large documents with token limits.
-// Uses token-based chunking instead of character-based for accurate LLM context window management.
-func chunkDocument(doc scanner.Document, maxTokens, overlapTokens int, tok tokenizer.Tokenizer) []Chunk {
-	content := string(doc.Content)
-
-	// Check if entire content fits within token limit
-	if tok.Count(content) <= maxTokens {
-		return []Chunk{
-			{
-				ID:         "chunk-0",
-				Text:       content,
-				StartPos:   0,
-				EndPos:     len(content),
-				TokenCount: tok.Count(content),
-			},
-		}
-	}
-
-	chunks := make([]Chunk, 0)
-	chunkID := 0
-	startPos := 0
-
-	for startPos < len(content) {
-		// Find end position where token count reaches maxTokens
-		endPos := len(content)
-		currentText := content[startPos:endPos]
-
-		// If current text exceeds maxTokens, find the right boundary
-		if tok.Count(currentText) > maxTokens {
-			// Binary search for the right character position
-			left, right := startPos, len(content)
-
-			for left < right {
-				mid := (left + right + 1) / 2
-				testText := content[startPos:mid]
-
-				if tok.Count(testText) <= maxTokens {
-					left = mid
-				} else {
-					right = mid - 1
-				}
-			}
-
-			endPos = left
-
-			// Try to break at word boundary to avoid splitting words
-			if endPos < len(content) && endPos > startPos {
-				// Look backwards for space or newline within reasonable range
-				searchStart := maxInt(startPos, endPos-100)
-				for i := endPos; i > searchStart; i-- {
-					if content[i-1] == ' ' || content[i-1] == '\n' {
-						endPos = i
-						break
-					}
-				}
-			}
-		}
-
-		// Extract chunk text
-		chunkText := content[startPos:endPos]
-
-		chunks = append(chunks, Chunk{
-			ID:         fmt.Sprintf("chunk-%d", chunkID),
-			Text:       chunkText,
-			StartPos:   startPos,
-			EndPos:     endPos,
-			TokenCount: tok.Count(chunkText),
-		})
-
-		chunkID++
-
-		// Move to next chunk with overlap
-		if endPos >= len(content) {
-			break
-		}
-
-		// Calculate next start position with token-based overlap
-		// Find position that gives us approximately overlapTokens
-		if overlapTokens > 0 {
-			// Binary search for overlap position
-			targetTokens := tok.Count(chunkText) - overlapTokens
-			if targetTokens <= 0 {
-				// If overlap is larger than chunk, just move forward minimally
-				nextStart := endPos
-				startPos = nextStart
-				continue
-			}
-
-			left, right := startPos, endPos
-			for left < right {
-				mid := (left + right + 1) / 2
-				testText := content[startPos:mid]
-
-				if tok.Count(testText) <= targetTokens {
-					left = mid
-				} else {
-					right = mid - 1
-				}
-			}
-
-			startPos = left
-		} else {
-			startPos = endPos
-		}
-
-		// Ensure we make progress
-		if startPos >= endPos {
-			startPos = endPos
-		}
-	}
-
-	return chunks
+// It is the default used when no chunking strategy is configured and delegates to
+// the token-based fixed-size strategy.
+func chunkDocument(doc scanner.Document, maxTokens, overlapTokens int, tok tokenizer.Tokenizer) ([]Chunk, error) {
+	return chunking.NewFixedSizeStrategy(tok).Chunk(doc, maxTokens, overlapTokens)
 }
 
-// extractKeywords performs a simple keyword extraction from a given text.
-// It removes common stop words and punctuation.
-func extractKeywords(text string) []string {
-	// Simple keyword extraction - in production, use NLP library
-	words := strings.Fields(strings.ToLower(text))
-	keywords := make(map[string]bool)
-
-	stopWords := map[string]bool{
-		"the": true, "a": true, "an": true, "and": true, "or": true,
-		"but": true, "in": true, "on": true, "at": true, "to": true,
-		"for": true, "of": true, "with": true, "by": true, "is": true,
-		"are": true, "was": true, "were": true, "be": true, "been": true,
-		"this": true, "that": true, "these": true, "those": true,
+// resolveTokenizer returns tok, or the default tokenizer when tok is nil and
+// no strategy is configured, since the default fixed-size chunking needs one.
+// It returns nil when a strategy is configured without a tokenizer.
+func resolveTokenizer(tok tokenizer.Tokenizer, strategy chunking.ChunkStrategy) (tokenizer.Tokenizer, error) {
+	if tok != nil || strategy != nil {
+		return tok, nil
 	}
 
-	for _, word := range words {
-		// Clean word
-		word = strings.Trim(word, ".,!?;:\"'()[]{}#")
-
-		// Skip short words and stop words
-		if len(word) > 3 && !stopWords[word] {
-			keywords[word] = true
-		}
+	defaultTok, err := tokenizer.NewTokenizer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tokenizer: %w", err)
 	}
+	return defaultTok, nil
+}
 
-	result := make([]string, 0, len(keywords))
-	for keyword := range keywords {
-		result = append(result, keyword)
+// encodingName returns the encoding of tok, or "" when tok is nil.
+func encodingName(tok tokenizer.Tokenizer) string {
+	if tok == nil {
+		return ""
 	}
+	return tok.Encoding()
+}
 
-	return result
+// chunkDocumentWith splits a document using the given strategy, falling back to
+// chunkDocument when strategy is nil.
+func chunkDocumentWith(strategy chunking.ChunkStrategy, doc scanner.Document, maxTokens, overlapTokens int, tok tokenizer.Tokenizer) ([]Chunk, error) {
+	if strategy == nil {
+		return chunkDocument(doc, maxTokens, overlapTokens, tok)
+	}
+	return strategy.Chunk(doc, maxTokens, overlapTokens)
 }
 
 // contains checks if a string slice contains a specific item.
@@ -376,19 +321,3 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
-
-// maxInt returns the greater of two integers.
-func maxInt(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-// minInt returns the smaller of two integers.
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
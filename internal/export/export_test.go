@@ -149,6 +149,7 @@ func TestExporter_ToLLMFormat(t *testing.T) {
 	}
 
 	exporter := NewExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	llmData, err := exporter.ToLLMFormat(docs)
 	if err != nil {
 		t.Fatalf("ToLLMFormat() error = %v", err)
@@ -178,19 +179,43 @@ func TestExporter_ToLLMFormat(t *testing.T) {
 	}
 }
 
-// TestChunkDocument tests the document chunking logic.
-func TestChunkDocument(t *testing.T) {
-	tok, err := tokenizer.NewTokenizer()
+// TestExporter_ToLLMFormat_ChunkSize tests that the configured chunk size
+// takes precedence over the llm.chunk_size default.
+func TestExporter_ToLLMFormat_ChunkSize(t *testing.T) {
+	docs := []scanner.Document{{ID: "doc", RelativePath: "doc.md", Content: []byte(strings.Repeat("This is a test sentence. ", 100))}}
+
+	exporter := NewExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
+	exporter.SetChunkSize(50, 0)
+	llmData, err := exporter.ToLLMFormat(docs)
 	if err != nil {
-		t.Fatalf("Failed to create tokenizer: %v", err)
+		t.Fatalf("ToLLMFormat() error = %v", err)
+	}
+
+	chunks := llmData.Documents[0].Chunks
+	if len(chunks) < 2 {
+		t.Errorf("ToLLMFormat() produced %d chunks, want the document split into 50-token chunks", len(chunks))
 	}
+	for _, chunk := range chunks {
+		if chunk.TokenCount > 50 {
+			t.Errorf("chunk %s has %d tokens, exceeds chunk size 50", chunk.ID, chunk.TokenCount)
+		}
+	}
+}
+
+// TestChunkDocument tests the document chunking logic.
+func TestChunkDocument(t *testing.T) {
+	tok := tokenizer.NewHeuristicTokenizer()
 
 	content := strings.Repeat("This is a test sentence. ", 100)
 	doc := scanner.Document{
 		Content: []byte(content),
 	}
 
-	chunks := chunkDocument(doc, 100, 20, tok)
+	chunks, err := chunkDocument(doc, 100, 20, tok)
+	if err != nil {
+		t.Fatalf("chunkDocument() error = %v", err)
+	}
 	if len(chunks) == 0 {
 		t.Error("chunkDocument() returned no chunks")
 	}
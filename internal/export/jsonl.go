@@ -3,10 +3,17 @@
 package export
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/dedup"
+	"github.com/onedusk/jot/internal/embedding"
+	"github.com/onedusk/jot/internal/enrich"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 )
@@ -17,14 +24,90 @@ import (
 //
 // Specification: https://jsonlines.org/
 type JSONLExporter struct {
-	// Fields can be added here for configuration if needed in the future
+	embedder  embedding.Embedder     // Optional; when set, every chunk carries a vector
+	strategy  chunking.ChunkStrategy // Optional; defaults to fixed-size chunking
+	tokenizer tokenizer.Tokenizer    // Optional; defaults to cl100k_base for fixed-size chunking
+	enricher  *enrich.Enricher       // Optional; when set, every chunk carries a context preamble
+	target    Target                 // Optional; when set, records use the target's layout
+	manifest  *Manifest              // Optional; when set, every exported chunk is recorded
+	since     map[string]string      // Optional; record hashes of a previous export's chunks, which are not emitted again
+
+	duplicates    *dedup.Detector // Optional; when set, near-duplicate chunks are dropped or merged
+	duplicateMode string          // DuplicatesDrop or DuplicatesMerge
 }
 
+// Ways of handling near-duplicate chunks, see SetDuplicates.
+const (
+	DuplicatesDrop  = "drop"  // Omit near-duplicates of earlier chunks
+	DuplicatesMerge = "merge" // Keep them, with canonical_chunk_id naming the earlier chunk
+)
+
 // NewJSONLExporter creates and returns a new JSONLExporter instance.
 func NewJSONLExporter() *JSONLExporter {
 	return &JSONLExporter{}
 }
 
+// SetTokenizer configures the tokenizer used by the default chunking and
+// recorded in the export so consumers know how token counts were computed.
+// It should match the tokenizer of any configured strategy.
+func (e *JSONLExporter) SetTokenizer(tok tokenizer.Tokenizer) {
+	e.tokenizer = tok
+}
+
+// SetStrategy configures the chunking strategy used to split documents.
+// Passing nil restores the default fixed-size chunking.
+func (e *JSONLExporter) SetStrategy(strategy chunking.ChunkStrategy) {
+	e.strategy = strategy
+}
+
+// SetEnricher configures contextual enrichment of chunks. Passing nil
+// disables it.
+func (e *JSONLExporter) SetEnricher(enricher *enrich.Enricher) {
+	e.enricher = enricher
+}
+
+// SetTarget configures the record layout of each line, such as a vector
+// database's native upsert format. Passing nil emits ChunkMetadata.
+func (e *JSONLExporter) SetTarget(target Target) {
+	e.target = target
+}
+
+// SetManifest configures a manifest that records the documents and chunk IDs
+// of the export. Passing nil disables recording.
+func (e *JSONLExporter) SetManifest(manifest *Manifest) {
+	e.manifest = manifest
+}
+
+// SetSince makes the export incremental: chunks that appear in the manifest
+// of a previous export with the same record (see RecordHash) are still
+// recorded in the manifest set with SetManifest, but they are neither
+// enriched, embedded nor emitted. Chunks whose text is unchanged are emitted
+// again when their neighbours, positions or document metadata changed. Use
+// DiffManifests to find the chunks that were removed. Passing nil exports
+// every chunk.
+func (e *JSONLExporter) SetSince(previous *Manifest) {
+	e.since = nil
+	if previous != nil {
+		e.since = previous.ChunkHashes()
+	}
+}
+
+// SetDuplicates configures near-duplicate detection across the chunks of an
+// export. A chunk whose text is a near-duplicate of an earlier chunk (see
+// dedup.Detector) is omitted with DuplicatesDrop, before it is enriched,
+// embedded or recorded in the manifest; with DuplicatesMerge it is exported
+// with CanonicalChunkID set. Passing a nil detector disables detection.
+func (e *JSONLExporter) SetDuplicates(detector *dedup.Detector, mode string) {
+	e.duplicates = detector
+	e.duplicateMode = mode
+}
+
+// SetEmbedder configures the embedder used to populate chunk vectors.
+// Passing nil disables embeddings.
+func (e *JSONLExporter) SetEmbedder(embedder embedding.Embedder) {
+	e.embedder = embedder
+}
+
 // ToJSONL converts documents to JSONL format with token-based chunking.
 // Each chunk is exported as a single line of compact JSON followed by a newline character.
 //
@@ -37,29 +120,93 @@ func NewJSONLExporter() *JSONLExporter {
 //   - A string containing the JSONL output (newline-delimited JSON objects)
 //   - An error if chunking or JSON marshaling fails
 func (e *JSONLExporter) ToJSONL(documents []scanner.Document, maxTokens, overlapTokens int) (string, error) {
-	// Initialize tokenizer for chunking
-	tok, err := tokenizer.NewTokenizer()
+	var builder strings.Builder
+	if err := e.WriteJSONL(&builder, documents, maxTokens, overlapTokens); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// WriteJSONL writes documents to w in JSONL format, like ToJSONL, but streams
+// the output: each document is chunked and its lines are written before the
+// next document is processed, so memory use does not grow with the corpus.
+func (e *JSONLExporter) WriteJSONL(w io.Writer, documents []scanner.Document, maxTokens, overlapTokens int) error {
+	return e.EachChunk(documents, maxTokens, overlapTokens, func(doc scanner.Document, metadata ChunkMetadata) error {
+		// Convert to the target's record layout if one is configured
+		var record interface{} = metadata
+		if e.target != nil {
+			record = e.target.Record(metadata, doc.Metadata)
+		}
+
+		// Marshal to compact JSON (no indentation)
+		jsonBytes, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal chunk %s to JSON: %w", metadata.ChunkID, err)
+		}
+
+		// Write JSON object followed by newline (JSONL spec)
+		if _, err := w.Write(append(jsonBytes, '\n')); err != nil {
+			return fmt.Errorf("failed to write chunk %s: %w", metadata.ChunkID, err)
+		}
+		return nil
+	})
+}
+
+// EachChunk chunks, enriches and embeds documents one at a time and calls fn
+// for every resulting chunk, in document order. It stops at the first error
+// returned by fn. Chunk IDs are guaranteed to be unique across the export;
+// a duplicate, which means two documents share an ID, is an error.
+func (e *JSONLExporter) EachChunk(documents []scanner.Document, maxTokens, overlapTokens int, fn func(doc scanner.Document, metadata ChunkMetadata) error) error {
+	tok, err := resolveTokenizer(e.tokenizer, e.strategy)
 	if err != nil {
-		return "", fmt.Errorf("failed to initialize tokenizer: %w", err)
+		return err
 	}
 
-	var builder strings.Builder
+	sources := make(map[string]string) // Chunk ID -> source of the chunk
 
 	for _, doc := range documents {
-		// Chunk the document using token-based chunking
-		chunks := chunkDocument(doc, maxTokens, overlapTokens, tok)
+		// Chunk the document using the configured strategy
+		chunks, err := chunkDocumentWith(e.strategy, doc, maxTokens, overlapTokens, tok)
+		if err != nil {
+			return fmt.Errorf("failed to chunk %s: %w", doc.RelativePath, err)
+		}
 
-		// Convert each chunk to ChunkMetadata and write as JSONL
+		// Drop near-duplicates of earlier chunks, or note their canonical chunk
+		canonical := make(map[string]string)
+		if e.duplicates != nil {
+			kept := chunks[:0]
+			for _, chunk := range chunks {
+				if match, ok := e.duplicates.Add(chunk.ID, chunk.Text); ok {
+					if e.duplicateMode == DuplicatesDrop {
+						continue
+					}
+					canonical[chunk.ID] = match.CanonicalID
+				}
+				kept = append(kept, chunk)
+			}
+			chunks = kept
+		}
+
+		// Describe each chunk, including its neighbours and position
+		records := make([]ChunkMetadata, len(chunks))
 		for i, chunk := range chunks {
 			metadata := ChunkMetadata{
-				DocID:      doc.ID,
-				ChunkID:    chunk.ID,
-				Text:       chunk.Text,
-				TokenCount: chunk.TokenCount,
-				Source:     doc.RelativePath,
-				StartPos:   chunk.StartPos,
-				EndPos:     chunk.EndPos,
-				Vector:     chunk.Vector,
+				DocID:       doc.ID,
+				ChunkID:     chunk.ID,
+				Text:        chunk.Text,
+				TokenCount:  chunk.TokenCount,
+				Source:      doc.RelativePath,
+				StartPos:    chunk.StartPos,
+				EndPos:      chunk.EndPos,
+				HeadingPath: chunk.HeadingPath,
+				Tokenizer:   encodingName(tok),
+
+				CanonicalChunkID: canonical[chunk.ID],
+			}
+			if doc.Git != nil {
+				metadata.LastUpdated = doc.Git.Updated.Format(time.RFC3339)
+				metadata.LastCommit = doc.Git.Commit
+				metadata.Authors = doc.Git.Authors
 			}
 
 			// Set previous and next chunk IDs for navigation
@@ -69,18 +216,97 @@ func (e *JSONLExporter) ToJSONL(documents []scanner.Document, maxTokens, overlap
 			if i < len(chunks)-1 {
 				metadata.NextChunkID = chunks[i+1].ID
 			}
+			records[i] = metadata
+		}
+
+		// Only chunks missing from the previous export, or whose record
+		// changed there (such as the neighbours of an inserted chunk), need
+		// to be processed
+		fresh := chunks
+		if e.since != nil {
+			fresh = make([]Chunk, 0, len(chunks))
+			for i, chunk := range chunks {
+				if e.since[chunk.ID] != RecordHash(records[i]) {
+					fresh = append(fresh, chunk)
+				}
+			}
+		}
+
+		// Situate each chunk within its document before embedding, so the
+		// vectors reflect the context as well
+		if e.enricher != nil && len(fresh) > 0 {
+			if err := e.enricher.Enrich(context.Background(), doc, fresh); err != nil {
+				return err
+			}
+		}
+
+		// Compute embeddings for all chunks of the document at once
+		if e.embedder != nil {
+			if err := embedChunks(e.embedder, fresh); err != nil {
+				return fmt.Errorf("failed to embed %s: %w", doc.RelativePath, err)
+			}
+		}
+
+		processed := make(map[string]Chunk, len(fresh))
+		for _, chunk := range fresh {
+			processed[chunk.ID] = chunk
+		}
+
+		for i, metadata := range records {
+			if source, ok := sources[metadata.ChunkID]; ok {
+				return fmt.Errorf("duplicate chunk ID %s in %s and %s", metadata.ChunkID, source, doc.RelativePath)
+			}
+			sources[metadata.ChunkID] = doc.RelativePath
 
-			// Marshal to compact JSON (no indentation)
-			jsonBytes, err := json.Marshal(metadata)
-			if err != nil {
-				return "", fmt.Errorf("failed to marshal chunk %s to JSON: %w", chunk.ID, err)
+			// Chunks of the previous export with the same record are not
+			// processed nor emitted again
+			chunk, ok := processed[metadata.ChunkID]
+			if !ok {
+				if e.manifest != nil {
+					e.manifest.AddChunk(doc, records[i])
+				}
+				continue
 			}
+			metadata.Vector = chunk.Vector
+			metadata.Context = chunk.Context
 
-			// Append JSON object followed by newline (JSONL spec)
-			builder.Write(jsonBytes)
-			builder.WriteString("\n")
+			if e.manifest != nil {
+				e.manifest.AddChunk(doc, metadata)
+			}
+			if err := fn(doc, metadata); err != nil {
+				return err
+			}
 		}
 	}
 
-	return builder.String(), nil
+	return nil
+}
+
+// embedChunks fills in the Vector field of each chunk using the given embedder.
+// Chunks carrying a context preamble are embedded together with it.
+func embedChunks(embedder embedding.Embedder, chunks []Chunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+		if chunk.Context != "" {
+			texts[i] = chunk.Context + "\n\n" + chunk.Text
+		}
+	}
+
+	vectors, err := embedder.Embed(context.Background(), texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(chunks) {
+		return fmt.Errorf("embedder %s returned %d vectors for %d chunks", embedder.Name(), len(vectors), len(chunks))
+	}
+
+	for i := range chunks {
+		chunks[i].Vector = vectors[i]
+	}
+	return nil
 }
@@ -2,12 +2,18 @@ package export
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/onedusk/jot/internal/dedup"
+	"github.com/onedusk/jot/internal/embedding"
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
 )
 
 // TestNewJSONLExporter tests the creation of a new JSONLExporter.
@@ -33,6 +39,7 @@ func TestToJSONL(t *testing.T) {
 
 	// Create exporter and export to JSONL
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -107,6 +114,7 @@ func TestToJSONL_MultipleDocuments(t *testing.T) {
 	}
 
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -157,6 +165,7 @@ func TestToJSONL_ChunkNavigation(t *testing.T) {
 	}
 
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -232,6 +241,7 @@ func TestToJSONL_VectorField(t *testing.T) {
 	}
 
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -260,6 +270,199 @@ func TestToJSONL_VectorField(t *testing.T) {
 	}
 }
 
+// TestToJSONL_WithEmbedder tests that chunk vectors are populated when an embedder is set.
+func TestToJSONL_WithEmbedder(t *testing.T) {
+	docs := []scanner.Document{
+		{
+			ID:           "doc1",
+			Title:        "Test Document",
+			RelativePath: "test.md",
+			Content:      []byte(strings.Repeat("Vector databases store embeddings. ", 20)),
+			ModTime:      time.Now(),
+		},
+	}
+
+	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
+	exporter.SetEmbedder(embedding.NewHashEmbedder(32))
+
+	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
+	if err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonlOutput), "\n")
+	for i, line := range lines {
+		var metadata ChunkMetadata
+		if err := json.Unmarshal([]byte(line), &metadata); err != nil {
+			t.Fatalf("Failed to unmarshal line %d: %v", i, err)
+		}
+		if len(metadata.Vector) != 32 {
+			t.Errorf("Line %d vector length = %d, want 32", i, len(metadata.Vector))
+		}
+	}
+}
+
+// paragraphStrategy is a test chunking strategy that emits one chunk per
+// blank-line separated paragraph, counting words as tokens.
+type paragraphStrategy struct{}
+
+func (paragraphStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]Chunk, error) {
+	var chunks []Chunk
+	content := string(doc.Content)
+	pos := 0
+	for _, para := range strings.Split(content, "\n\n") {
+		if strings.TrimSpace(para) != "" {
+			chunks = append(chunks, Chunk{
+				ID:         fmt.Sprintf("%s-para-%d", doc.ID, len(chunks)),
+				Text:       para,
+				StartPos:   pos,
+				EndPos:     pos + len(para),
+				TokenCount: len(strings.Fields(para)),
+			})
+		}
+		pos += len(para) + 2
+	}
+	return chunks, nil
+}
+
+// TestToJSONL_WithStrategy tests that a configured chunking strategy replaces
+// the default fixed-size chunking.
+func TestToJSONL_WithStrategy(t *testing.T) {
+	docs := []scanner.Document{
+		{
+			ID:           "doc1",
+			Title:        "Test Document",
+			RelativePath: "test.md",
+			Content:      []byte("# Intro\n\nFirst paragraph.\n\nSecond paragraph here."),
+			ModTime:      time.Now(),
+		},
+	}
+
+	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
+	exporter.SetStrategy(paragraphStrategy{})
+
+	jsonlOutput, err := exporter.ToJSONL(docs, 512, 0)
+	if err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonlOutput), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("ToJSONL() produced %d lines, want 3", len(lines))
+	}
+
+	var metadata ChunkMetadata
+	if err := json.Unmarshal([]byte(lines[2]), &metadata); err != nil {
+		t.Fatalf("Failed to unmarshal line 2: %v", err)
+	}
+	if metadata.ChunkID != "doc1-para-2" || metadata.Text != "Second paragraph here." {
+		t.Errorf("line 2 = %q (%s), want paragraph chunk doc1-para-2", metadata.Text, metadata.ChunkID)
+	}
+	if metadata.TokenCount != 3 {
+		t.Errorf("line 2 token_count = %d, want 3", metadata.TokenCount)
+	}
+}
+
+// TestToJSONL_Duplicates tests dropping and merging near-duplicate chunks.
+func TestToJSONL_Duplicates(t *testing.T) {
+	shared := "Support is available by email on weekdays between nine and five. Urgent issues raised by customers on a paid plan are answered within an hour, and everything else is answered by the next business day."
+	docs := []scanner.Document{
+		{ID: "v1", RelativePath: "v1/support.md", Content: []byte("Version one support.\n\n" + shared)},
+		{ID: "v2", RelativePath: "v2/support.md", Content: []byte("Version two support.\n\n" + strings.Replace(shared, "an hour", "one hour", 1))},
+	}
+
+	tests := []struct {
+		mode string
+		want []string // chunk_id:canonical_chunk_id of every line
+	}{
+		{DuplicatesDrop, []string{"v1-para-0:", "v1-para-1:", "v2-para-0:"}},
+		{DuplicatesMerge, []string{"v1-para-0:", "v1-para-1:", "v2-para-0:", "v2-para-1:v1-para-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			exporter := NewJSONLExporter()
+			exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
+			exporter.SetStrategy(paragraphStrategy{})
+			exporter.SetDuplicates(dedup.NewDetector(dedup.DefaultThreshold), tt.mode)
+
+			output, err := exporter.ToJSONL(docs, 512, 0)
+			if err != nil {
+				t.Fatalf("ToJSONL() error = %v", err)
+			}
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+				var metadata ChunkMetadata
+				if err := json.Unmarshal([]byte(line), &metadata); err != nil {
+					t.Fatalf("Failed to unmarshal %q: %v", line, err)
+				}
+				got = append(got, metadata.ChunkID+":"+metadata.CanonicalChunkID)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("ToJSONL() chunks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingStrategy wraps paragraphStrategy and records how many bytes had
+// been written to out each time a document was chunked.
+type recordingStrategy struct {
+	paragraphStrategy
+	out     *bytes.Buffer
+	written []int
+}
+
+func (r *recordingStrategy) Chunk(doc scanner.Document, maxTokens, overlapTokens int) ([]Chunk, error) {
+	r.written = append(r.written, r.out.Len())
+	return r.paragraphStrategy.Chunk(doc, maxTokens, overlapTokens)
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// TestWriteJSONL tests that documents are written one at a time and that
+// write errors are reported.
+func TestWriteJSONL(t *testing.T) {
+	docs := []scanner.Document{
+		{ID: "doc1", RelativePath: "one.md", Content: []byte("First paragraph.\n\nSecond paragraph.")},
+		{ID: "doc2", RelativePath: "two.md", Content: []byte("Third paragraph.")},
+	}
+
+	var out bytes.Buffer
+	strategy := &recordingStrategy{out: &out}
+	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
+	exporter.SetStrategy(strategy)
+
+	if err := exporter.WriteJSONL(&out, docs, 512, 0); err != nil {
+		t.Fatalf("WriteJSONL() error = %v", err)
+	}
+
+	if len(strategy.written) != 2 || strategy.written[0] != 0 || strategy.written[1] == 0 {
+		t.Errorf("bytes written before chunking each document = %v, want first document flushed before the second", strategy.written)
+	}
+
+	want, err := exporter.ToJSONL(docs, 512, 0)
+	if err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+	if out.String() != want {
+		t.Errorf("WriteJSONL() output differs from ToJSONL()\ngot:  %q\nwant: %q", out.String(), want)
+	}
+
+	if err := exporter.WriteJSONL(failingWriter{}, docs, 512, 0); err == nil {
+		t.Error("WriteJSONL() expected error for failing writer")
+	}
+}
+
 // TestJSONLStreaming tests that JSONL output can be read line-by-line using bufio.Scanner
 // without loading the entire file into memory.
 func TestJSONLStreaming(t *testing.T) {
@@ -276,6 +479,7 @@ func TestJSONLStreaming(t *testing.T) {
 	}
 
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL(docs, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -332,6 +536,7 @@ func TestJSONLStreaming(t *testing.T) {
 // TestToJSONL_EmptyDocuments tests JSONL export with empty document list.
 func TestToJSONL_EmptyDocuments(t *testing.T) {
 	exporter := NewJSONLExporter()
+	exporter.SetTokenizer(tokenizer.NewHeuristicTokenizer())
 	jsonlOutput, err := exporter.ToJSONL([]scanner.Document{}, 50, 10)
 	if err != nil {
 		t.Fatalf("ToJSONL() error = %v", err)
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// truncatedMarker ends a document cut short to fit the token budget.
+const truncatedMarker = "[truncated]"
+
+// summaryMarker ends a document replaced by its description to fit the
+// token budget.
+const summaryMarker = "[truncated: summary only]"
+
+// budgetEntry is one document of a token-budgeted llms-full.txt together
+// with the token cost of each way of rendering it.
+type budgetEntry struct {
+	doc     scanner.Document
+	title   string // Separator and H1 heading
+	full    string
+	summary string
+	cost    int // Tokens of the summary
+}
+
+// toBudgetedLLMSFullTxt renders llms-full.txt within e.maxTokens tokens.
+//
+// Documents are ranked by prioritizeDocuments. Every document that fits is
+// first given a summary (its description); the remaining budget then upgrades
+// summaries to full content in priority order, truncating the first document
+// that does not fit entirely; later documents keep their summaries, so the
+// budget is not spent on scraps. Documents without room for a summary are
+// omitted and listed in a trailer. Token counts are summed per entry, so the
+// total may differ from a count of the whole output by a few tokens where
+// entries join.
+func (e *LLMSTxtExporter) toBudgetedLLMSFullTxt(documents []scanner.Document, config ProjectConfig) (string, error) {
+	header := "# " + config.Name + "\n\n> " + config.Description + "\n\n"
+	budget := e.maxTokens - e.tokenizer.Count(header)
+	if budget < 0 {
+		return "", fmt.Errorf("max tokens %d is too small for the llms-full.txt header (%d tokens)", e.maxTokens, e.tokenizer.Count(header))
+	}
+
+	docs := prioritizeDocuments(documents)
+	entries := make([]budgetEntry, len(docs))
+	omittedLines := make([]string, len(docs))
+	omittedCosts := make([]int, len(docs))
+	for i, doc := range docs {
+		separator := ""
+		if i > 0 {
+			separator = "---\n\n"
+		}
+		title := separator + "# " + doc.Title + "\n\n"
+		entries[i] = budgetEntry{
+			doc:     doc,
+			title:   title,
+			full:    title + string(doc.Content) + "\n\n",
+			summary: title + documentDescription(doc) + "\n\n" + summaryMarker + "\n\n",
+		}
+		entries[i].cost = e.tokenizer.Count(entries[i].summary)
+		omittedLines[i] = "- [" + doc.Title + "](" + e.documentURL(doc.RelativePath, config) + ")\n"
+		omittedCosts[i] = e.tokenizer.Count(omittedLines[i])
+	}
+
+	// Keep the longest prefix of documents whose summaries fit next to a
+	// trailer listing the rest
+	trailerHeader := "---\n\n## Omitted documents\n\nThe following documents did not fit in the token budget:\n\n"
+	trailerHeaderCost := e.tokenizer.Count(trailerHeader)
+	summaries, omitted := 0, 0
+	for _, entry := range entries {
+		summaries += entry.cost
+	}
+	kept, used := len(entries), summaries
+	for ; kept > 0 && used > budget; kept-- {
+		summaries -= entries[kept-1].cost
+		omitted += omittedCosts[kept-1]
+		used = summaries + trailerHeaderCost + omitted
+	}
+	if used > budget {
+		return "", fmt.Errorf("max tokens %d is too small to list the omitted documents", e.maxTokens)
+	}
+
+	// Upgrade summaries to full content while the budget lasts
+	slack := budget - used
+	rendered := make([]string, kept)
+	for i := range entries[:kept] {
+		entry := &entries[i]
+		rendered[i] = entry.summary
+		if slack <= 0 {
+			continue
+		}
+
+		fullCost := e.tokenizer.Count(entry.full)
+		if fullCost-entry.cost <= slack {
+			rendered[i] = entry.full
+			slack -= fullCost - entry.cost
+			continue
+		}
+
+		// Cut the content to the tokens left once the title and marker fit
+		available := entry.cost + slack - e.tokenizer.Count(entry.title+"\n\n"+truncatedMarker+"\n\n")
+		prefix := tokenPrefix(e.tokenizer, string(entry.doc.Content), available)
+		truncated := entry.title + prefix + "\n\n" + truncatedMarker + "\n\n"
+		truncatedCost := e.tokenizer.Count(truncated)
+		if prefix != "" && truncatedCost > entry.cost && truncatedCost-entry.cost <= slack {
+			rendered[i] = truncated
+		}
+		slack = 0
+	}
+
+	var builder strings.Builder
+	builder.WriteString(header)
+	for _, text := range rendered {
+		builder.WriteString(text)
+	}
+	if kept < len(entries) {
+		builder.WriteString(trailerHeader)
+		for _, line := range omittedLines[kept:] {
+			builder.WriteString(line)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// prioritizeDocuments orders documents for a token budget: README first,
+// then by frontmatter `priority` (higher first), then shallower paths (TOC
+// depth), then the most recently modified, then alphabetically by path.
+func prioritizeDocuments(documents []scanner.Document) []scanner.Document {
+	sorted := make([]scanner.Document, len(documents))
+	copy(sorted, documents)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+
+		aIsReadme := strings.ToLower(filepath.Base(a.RelativePath)) == "readme.md"
+		bIsReadme := strings.ToLower(filepath.Base(b.RelativePath)) == "readme.md"
+		if aIsReadme != bIsReadme {
+			return aIsReadme
+		}
+
+		if aPriority, bPriority := documentPriority(a), documentPriority(b); aPriority != bPriority {
+			return aPriority > bPriority
+		}
+
+		if aDepth, bDepth := pathDepth(a.RelativePath), pathDepth(b.RelativePath); aDepth != bDepth {
+			return aDepth < bDepth
+		}
+
+		if !a.ModTime.Equal(b.ModTime) {
+			return a.ModTime.After(b.ModTime)
+		}
+
+		return a.RelativePath < b.RelativePath
+	})
+
+	return sorted
+}
+
+// documentPriority returns the numeric frontmatter `priority` of doc, or 0.
+func documentPriority(doc scanner.Document) float64 {
+	switch priority := doc.Metadata["priority"].(type) {
+	case int:
+		return float64(priority)
+	case int64:
+		return float64(priority)
+	case uint64:
+		return float64(priority)
+	case float64:
+		return priority
+	}
+	return 0
+}
+
+// pathDepth returns the number of directories in a relative path, which is
+// the depth of the document in the table of contents.
+func pathDepth(relativePath string) int {
+	return strings.Count(filepath.ToSlash(filepath.Clean(relativePath)), "/")
+}
+
+// tokenPrefix returns a prefix of text within maxTokens tokens, cut at a line
+// break when one falls in its second half, otherwise at the last word break,
+// and never inside a UTF-8 character.
+func tokenPrefix(tok tokenizer.Tokenizer, text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	offsets := tok.Offsets(text)
+	if maxTokens >= len(offsets) {
+		return text
+	}
+
+	end := offsets[maxTokens]
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	prefix := text[:end]
+	if i := strings.LastIndex(prefix, "\n"); i > len(prefix)/2 {
+		return strings.TrimRight(prefix[:i], " \n")
+	}
+	if i := strings.LastIndex(prefix, " "); i > 0 {
+		return prefix[:i]
+	}
+	return prefix
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// TestPrioritizeDocuments tests the ordering used for token budgets.
+func TestPrioritizeDocuments(t *testing.T) {
+	now := time.Now()
+	documents := []scanner.Document{
+		{RelativePath: "guides/old.md", ModTime: now.Add(-time.Hour)},
+		{RelativePath: "guides/new.md", ModTime: now},
+		{RelativePath: "api/deep/ref.md"},
+		{RelativePath: "intro.md"},
+		{RelativePath: "api/key.md", Metadata: map[string]interface{}{"priority": 5}},
+		{RelativePath: "README.md"},
+	}
+
+	sorted := prioritizeDocuments(documents)
+
+	want := []string{"README.md", "api/key.md", "intro.md", "guides/new.md", "guides/old.md", "api/deep/ref.md"}
+	for i, path := range want {
+		if sorted[i].RelativePath != path {
+			t.Errorf("prioritizeDocuments()[%d] = %s, want %s", i, sorted[i].RelativePath, path)
+		}
+	}
+}
+
+// TestToLLMSFullTxt_MaxTokens tests that llms-full.txt fits its token budget
+// by truncating, summarizing and omitting low-priority documents.
+func TestToLLMSFullTxt_MaxTokens(t *testing.T) {
+	body := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit.\n\n", 40)
+	documents := []scanner.Document{
+		{Title: "Readme", RelativePath: "README.md", Content: []byte("Short intro.")},
+		{Title: "Guide", RelativePath: "guide.md", Content: []byte(body)},
+		{Title: "Reference", RelativePath: "api/reference.md", Content: []byte(body)},
+		{Title: "Archive", RelativePath: "archive/old/notes.md", Content: []byte(body),
+			Metadata: map[string]interface{}{"description": "Old notes."}},
+	}
+	config := ProjectConfig{Name: "Test", Description: "Budgeted"}
+	tok := tokenizer.NewHeuristicTokenizer()
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		contains  []string
+		excludes  []string
+	}{
+		{
+			name:      "everything fits",
+			maxTokens: 100000,
+			contains:  []string{"# Readme\n\nShort intro.", "# Archive\n\n" + body},
+			excludes:  []string{truncatedMarker, "Omitted documents"},
+		},
+		{
+			name:      "truncate and summarize",
+			maxTokens: 400,
+			contains:  []string{"# Readme\n\nShort intro.", truncatedMarker, "# Archive\n\nOld notes.\n\n" + summaryMarker},
+			excludes:  []string{"# Guide\n\n" + body},
+		},
+		{
+			name:      "omit",
+			maxTokens: 100,
+			contains:  []string{"# Readme\n\nShort intro.", "## Omitted documents", "- [Archive](archive/old/notes.md)"},
+			excludes:  []string{"# Guide"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := NewLLMSTxtExporter()
+			exporter.SetMaxTokens(tt.maxTokens, tok)
+
+			result, err := exporter.ToLLMSFullTxt(documents, config)
+			if err != nil {
+				t.Fatalf("ToLLMSFullTxt() error = %v", err)
+			}
+			if count := tok.Count(result); count > tt.maxTokens {
+				t.Errorf("ToLLMSFullTxt() = %d tokens, want <= %d", count, tt.maxTokens)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("ToLLMSFullTxt() missing %q in:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("ToLLMSFullTxt() contains %q", unwanted)
+				}
+			}
+		})
+	}
+
+	exporter := NewLLMSTxtExporter()
+	exporter.SetMaxTokens(2, tok)
+	if _, err := exporter.ToLLMSFullTxt(documents, config); err == nil {
+		t.Error("ToLLMSFullTxt() with a budget below the header succeeded, want error")
+	}
+}
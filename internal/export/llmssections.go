@@ -0,0 +1,121 @@
+package export
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
+)
+
+// LLMSSection is a top-level directory of the documentation that gets its
+// own llms.txt and llms-full.txt, e.g. dist/api/llms.txt for api/.
+type LLMSSection struct {
+	Dir       string             // Directory relative to the docs root
+	Title     string             // Title from the TOC, or the directory name
+	Documents []scanner.Document // Documents anywhere below Dir
+}
+
+// SetRoot sets the directory, relative to the documentation root, that the
+// output is written to. Links without a base URL are made relative to it.
+func (e *LLMSTxtExporter) SetRoot(dir string) {
+	e.root = strings.Trim(path.Clean("/"+dir), "/")
+}
+
+// SetSplitSections makes ToLLMSTxt link to one llms.txt per top-level
+// section (see Sections) instead of listing the documents of those sections.
+func (e *LLMSTxtExporter) SetSplitSections(split bool) {
+	e.splitSections = split
+}
+
+// Sections splits documents into those at the top level of the docs root
+// and one LLMSSection per top-level directory, sorted by directory.
+func (e *LLMSTxtExporter) Sections(documents []scanner.Document) ([]scanner.Document, []LLMSSection) {
+	titles := e.topLevelTitles()
+
+	var root []scanner.Document
+	byDir := make(map[string]*LLMSSection)
+	for _, doc := range documents {
+		relativePath := filepath.ToSlash(filepath.Clean(doc.RelativePath))
+		i := strings.Index(relativePath, "/")
+		if i < 0 {
+			root = append(root, doc)
+			continue
+		}
+
+		dir := relativePath[:i]
+		section, ok := byDir[dir]
+		if !ok {
+			title := titles[dir]
+			if title == "" {
+				title = dir
+			}
+			section = &LLMSSection{Dir: dir, Title: title}
+			byDir[dir] = section
+		}
+		section.Documents = append(section.Documents, doc)
+	}
+
+	sections := make([]LLMSSection, 0, len(byDir))
+	for _, section := range byDir {
+		sections = append(sections, *section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].Dir < sections[j].Dir
+	})
+
+	return root, sections
+}
+
+// writeSectionIndex writes a "Sections" list linking to the llms.txt and
+// llms-full.txt of each section.
+func (e *LLMSTxtExporter) writeSectionIndex(builder *strings.Builder, sections []LLMSSection, config ProjectConfig) {
+	builder.WriteString("## Sections\n\n")
+	for _, section := range sections {
+		documents := "documents"
+		if len(section.Documents) == 1 {
+			documents = "document"
+		}
+		fmt.Fprintf(builder, "- [%s](%s): %d %s, full text in [llms-full.txt](%s)\n",
+			section.Title,
+			e.url(section.Dir+"/llms.txt", config),
+			len(section.Documents), documents,
+			e.url(section.Dir+"/llms-full.txt", config))
+	}
+	builder.WriteString("\n")
+}
+
+// topLevelTitles maps the top-level directories of the TOC to their titles.
+func (e *LLMSTxtExporter) topLevelTitles() map[string]string {
+	titles := make(map[string]string)
+	if e.toc == nil || e.toc.Root == nil {
+		return titles
+	}
+
+	for _, child := range e.toc.Root.Children {
+		if child.IsLeaf() {
+			continue
+		}
+		if leaf := firstLeaf(child); leaf != nil {
+			dir := strings.SplitN(filepath.ToSlash(leaf.Path), "/", 2)[0]
+			titles[dir] = child.Title
+		}
+	}
+	return titles
+}
+
+// firstLeaf returns the first document node below node, or nil.
+func firstLeaf(node *toc.TOCNode) *toc.TOCNode {
+	for _, child := range node.Children {
+		if child.IsLeaf() {
+			return child
+		}
+		if leaf := firstLeaf(child); leaf != nil {
+			return leaf
+		}
+	}
+	return nil
+}
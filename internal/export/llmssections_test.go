@@ -0,0 +1,67 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
+)
+
+// TestLLMSTxtSections tests splitting llms.txt into per-section files.
+func TestLLMSTxtSections(t *testing.T) {
+	documents := []scanner.Document{
+		{Title: "Home", RelativePath: "index.md", Content: []byte("Welcome.")},
+		{Title: "Auth", RelativePath: "api/auth.md", Content: []byte("Tokens.")},
+		{Title: "New", RelativePath: "api/v2/new.md", Content: []byte("New API.")},
+		{Title: "Start", RelativePath: "user-guide/start.md", Content: []byte("Begin here.")},
+	}
+	tableOfContents := toc.NewBuilder().Build(documents)
+	config := ProjectConfig{Name: "Jot", Description: "Docs"}
+
+	exporter := NewLLMSTxtExporter()
+	exporter.SetTOC(tableOfContents)
+	exporter.SetSplitSections(true)
+
+	root, sections := exporter.Sections(documents)
+	if len(root) != 1 || root[0].RelativePath != "index.md" {
+		t.Errorf("Sections() root = %v, want index.md", root)
+	}
+	if len(sections) != 2 || sections[0].Dir != "api" || len(sections[0].Documents) != 2 || sections[1].Title != "User Guide" {
+		t.Fatalf("Sections() = %+v, want api (2 documents) and user-guide titled User Guide", sections)
+	}
+
+	result, err := exporter.ToLLMSTxt(documents, config)
+	if err != nil {
+		t.Fatalf("ToLLMSTxt() error = %v", err)
+	}
+	for _, want := range []string{
+		"- [Home](index.md): Welcome.",
+		"## Sections\n\n- [Api](api/llms.txt): 2 documents, full text in [llms-full.txt](api/llms-full.txt)\n",
+		"- [User Guide](user-guide/llms.txt): 1 document,",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("ToLLMSTxt() missing %q in:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "auth.md") {
+		t.Errorf("ToLLMSTxt() lists a document of a split section:\n%s", result)
+	}
+
+	// Section files link relative to their own directory unless a base URL is set
+	sectionExporter := NewLLMSTxtExporter()
+	sectionExporter.SetRoot("api")
+	result, err = sectionExporter.ToLLMSTxt(sections[0].Documents, config)
+	if err != nil {
+		t.Fatalf("ToLLMSTxt() error = %v", err)
+	}
+	if !strings.Contains(result, "- [Auth](auth.md)") || !strings.Contains(result, "- [New](v2/new.md)") {
+		t.Errorf("section ToLLMSTxt() = %q, want links relative to api/", result)
+	}
+
+	config.BaseURL = "https://example.com"
+	result, _ = sectionExporter.ToLLMSTxt(sections[0].Documents, config)
+	if !strings.Contains(result, "- [Auth](https://example.com/api/auth.md)") {
+		t.Errorf("section ToLLMSTxt() = %q, want absolute links", result)
+	}
+}
@@ -6,15 +6,30 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
+	"github.com/onedusk/jot/internal/tokenizer"
 )
 
+// optionalSection is the llms.txt section whose links consumers may skip
+// when they need a shorter context.
+const optionalSection = "Optional"
+
+// maxDescriptionLength is the maximum length, in characters, of a
+// description derived from a document's first paragraph.
+const maxDescriptionLength = 100
+
 // LLMSTxtExporter handles exporting documents to llms.txt format.
 // The llms.txt format creates a simple markdown index of documentation
 // optimized for LLM consumption, as specified at https://llmstxt.org/
 type LLMSTxtExporter struct {
-	// No configuration fields needed currently
+	toc           *toc.TableOfContents // Optional; supplies section titles
+	tokenizer     tokenizer.Tokenizer  // Counts tokens for the llms-full.txt budget
+	maxTokens     int                  // llms-full.txt token budget; 0 means unlimited
+	root          string               // Directory the output is written to, relative to the docs root
+	splitSections bool                 // Link to per-section llms.txt files instead of listing their documents
 }
 
 // NewLLMSTxtExporter creates and returns a new LLMSTxtExporter instance.
@@ -22,12 +37,32 @@ func NewLLMSTxtExporter() *LLMSTxtExporter {
 	return &LLMSTxtExporter{}
 }
 
+// SetTOC sets the table of contents whose directory titles are used as
+// section titles. Without one, sections are titled by directory path.
+func (e *LLMSTxtExporter) SetTOC(t *toc.TableOfContents) {
+	e.toc = t
+}
+
+// SetMaxTokens limits llms-full.txt to maxTokens tokens as counted by tok
+// (the heuristic tokenizer when nil). 0 removes the limit.
+func (e *LLMSTxtExporter) SetMaxTokens(maxTokens int, tok tokenizer.Tokenizer) {
+	if tok == nil {
+		tok = tokenizer.NewHeuristicTokenizer()
+	}
+	e.maxTokens = maxTokens
+	e.tokenizer = tok
+}
+
 // ToLLMSTxt exports documents to llms.txt format per llmstxt.org specification.
 // The output includes:
 // - H1 header with project name
 // - Blockquote with project description
-// - H2 section headers grouped by directory
-// - Markdown list with [Title](path): description format
+// - H2 section headers grouped by directory, titled from the TOC when set
+// - Markdown list with [Title](url): description format
+// - A final "## Optional" section for optional documents (see isOptional)
+//
+// Links are built from config.BaseURL and config.LinkExtension. A document's
+// frontmatter `description` takes priority over its first paragraph.
 func (e *LLMSTxtExporter) ToLLMSTxt(documents []scanner.Document, config ProjectConfig) (string, error) {
 	var builder strings.Builder
 
@@ -41,8 +76,23 @@ func (e *LLMSTxtExporter) ToLLMSTxt(documents []scanner.Document, config Project
 	builder.WriteString(config.Description)
 	builder.WriteString("\n\n")
 
-	// Group documents by section (directory)
-	grouped := groupDocumentsBySection(documents)
+	// Documents of top-level sections are listed in their own llms.txt
+	var split []LLMSSection
+	if e.splitSections {
+		documents, split = e.Sections(documents)
+	}
+
+	// Set optional documents aside, then group the rest by section (directory)
+	optionalFilter := scanner.NewIgnoreFilter(config.Optional)
+	var required, optional []scanner.Document
+	for _, doc := range documents {
+		if isOptional(doc, optionalFilter) {
+			optional = append(optional, doc)
+		} else {
+			required = append(required, doc)
+		}
+	}
+	grouped := groupDocumentsBySection(required)
 
 	// Sort sections for consistent output
 	sections := make([]string, 0, len(grouped))
@@ -51,38 +101,122 @@ func (e *LLMSTxtExporter) ToLLMSTxt(documents []scanner.Document, config Project
 	}
 	sort.Strings(sections)
 
+	titles := e.sectionTitles()
+
 	// Write each section
 	for _, section := range sections {
-		docs := grouped[section]
-
-		// Write H2 section header
-		sectionTitle := section
-		if sectionTitle == "." || sectionTitle == "" {
+		sectionTitle := titles[section]
+		if sectionTitle == "" {
+			sectionTitle = section
+		}
+		if section == "." || section == "" {
 			sectionTitle = "Root"
 		}
-		builder.WriteString("## ")
-		builder.WriteString(sectionTitle)
-		builder.WriteString("\n\n")
+		e.writeSection(&builder, sectionTitle, grouped[section], config)
+	}
 
-		// Write document list
-		for _, doc := range docs {
-			description := extractFirstParagraph(doc.Content)
-
-			// Format: - [Title](path): description
-			builder.WriteString("- [")
-			builder.WriteString(doc.Title)
-			builder.WriteString("](")
-			builder.WriteString(doc.RelativePath)
-			builder.WriteString("): ")
-			builder.WriteString(description)
-			builder.WriteString("\n")
-		}
-		builder.WriteString("\n")
+	if len(split) > 0 {
+		e.writeSectionIndex(&builder, split, config)
+	}
+
+	// The Optional section comes last, per the specification
+	if len(optional) > 0 {
+		e.writeSection(&builder, optionalSection, optional, config)
 	}
 
 	return builder.String(), nil
 }
 
+// writeSection writes an H2 header and a link list for docs.
+func (e *LLMSTxtExporter) writeSection(builder *strings.Builder, title string, docs []scanner.Document, config ProjectConfig) {
+	builder.WriteString("## ")
+	builder.WriteString(title)
+	builder.WriteString("\n\n")
+
+	for _, doc := range docs {
+		// Format: - [Title](url): description
+		builder.WriteString("- [")
+		builder.WriteString(doc.Title)
+		builder.WriteString("](")
+		builder.WriteString(e.documentURL(doc.RelativePath, config))
+		builder.WriteString("): ")
+		builder.WriteString(documentDescription(doc))
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+}
+
+// isOptional reports whether doc belongs in the Optional section, either
+// through `optional: true` in its frontmatter or a matching path pattern.
+func isOptional(doc scanner.Document, filter *scanner.IgnoreFilter) bool {
+	if optional, ok := doc.Metadata["optional"].(bool); ok {
+		return optional
+	}
+	return filter.ShouldIgnore(doc.RelativePath)
+}
+
+// documentURL returns the link target for a document: its relative path with
+// the .md extension replaced by config.LinkExtension, prefixed with
+// config.BaseURL when set, or else made relative to the output directory.
+func (e *LLMSTxtExporter) documentURL(relativePath string, config ProjectConfig) string {
+	link := filepath.ToSlash(relativePath)
+	if config.LinkExtension != "" && strings.HasSuffix(strings.ToLower(link), ".md") {
+		link = link[:len(link)-len(".md")] + config.LinkExtension
+	}
+	return e.url(link, config)
+}
+
+// url returns the link target for a path relative to the docs root.
+func (e *LLMSTxtExporter) url(link string, config ProjectConfig) string {
+	if config.BaseURL != "" {
+		return strings.TrimSuffix(config.BaseURL, "/") + "/" + link
+	}
+	if e.root != "" {
+		return strings.TrimPrefix(link, e.root+"/")
+	}
+	return link
+}
+
+// documentDescription returns the frontmatter description of doc, falling
+// back to its first paragraph.
+func documentDescription(doc scanner.Document) string {
+	if description, ok := doc.Metadata["description"].(string); ok {
+		if description = strings.Join(strings.Fields(description), " "); description != "" {
+			return description
+		}
+	}
+	return extractFirstParagraph(doc.Content)
+}
+
+// sectionTitles maps directory paths to titles taken from the TOC. Nested
+// directories join the titles of their ancestors with " / ".
+func (e *LLMSTxtExporter) sectionTitles() map[string]string {
+	titles := make(map[string]string)
+	if e.toc == nil || e.toc.Root == nil {
+		return titles
+	}
+
+	var walk func(node *toc.TOCNode, title string)
+	walk = func(node *toc.TOCNode, title string) {
+		for _, child := range node.Children {
+			if child.IsLeaf() {
+				if title != "" {
+					titles[filepath.Dir(child.Path)] = title
+				}
+				continue
+			}
+			childTitle := child.Title
+			if title != "" {
+				childTitle = title + " / " + child.Title
+			}
+			walk(child, childTitle)
+		}
+	}
+	walk(e.toc.Root, "")
+
+	return titles
+}
+
 // groupDocumentsBySection groups documents by their directory path.
 func groupDocumentsBySection(documents []scanner.Document) map[string][]scanner.Document {
 	grouped := make(map[string][]scanner.Document)
@@ -96,7 +230,8 @@ func groupDocumentsBySection(documents []scanner.Document) map[string][]scanner.
 }
 
 // extractFirstParagraph extracts the first non-header paragraph from document content.
-// Limits output to 100 characters for concise descriptions.
+// Limits output to 100 characters for concise descriptions, truncating on
+// rune boundaries.
 func extractFirstParagraph(content []byte) string {
 	text := string(content)
 	lines := strings.Split(text, "\n")
@@ -133,15 +268,12 @@ func extractFirstParagraph(content []byte) string {
 		foundContent = true
 
 		// Stop if we've collected enough
-		if paragraph.Len() >= 100 {
+		if utf8.RuneCountInString(paragraph.String()) >= maxDescriptionLength {
 			break
 		}
 	}
 
-	result := paragraph.String()
-	if len(result) > 100 {
-		result = result[:97] + "..."
-	}
+	result := truncateRunes(paragraph.String(), maxDescriptionLength)
 
 	if result == "" {
 		result = "No description available"
@@ -150,6 +282,16 @@ func extractFirstParagraph(content []byte) string {
 	return result
 }
 
+// truncateRunes shortens s to at most max runes, replacing the tail with
+// "..." when it is cut. It never splits a multi-byte character.
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:max-3]), " ") + "..."
+}
+
 // ToLLMSFullTxt exports complete documentation with all content concatenated.
 // Creates llms-full.txt format with:
 // - H1 header with project name
@@ -157,7 +299,14 @@ func extractFirstParagraph(content []byte) string {
 // - All documents concatenated with '---' separators
 // - README.md appears first, then sorted alphabetically by path
 // - Each document prefixed with H1 heading containing document title
+//
+// With a token budget (see SetMaxTokens), documents are prioritized and
+// lower-priority ones are summarized, truncated or omitted instead.
 func (e *LLMSTxtExporter) ToLLMSFullTxt(documents []scanner.Document, config ProjectConfig) (string, error) {
+	if e.maxTokens > 0 {
+		return e.toBudgetedLLMSFullTxt(documents, config)
+	}
+
 	var builder strings.Builder
 
 	// Write H1 header with project name
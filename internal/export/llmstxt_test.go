@@ -3,8 +3,10 @@ package export
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/toc"
 )
 
 // TestToLLMSTxt tests the llms.txt export format generation.
@@ -440,3 +442,70 @@ func TestToLLMSFullTxt_EmptyDocuments(t *testing.T) {
 		t.Error("ToLLMSFullTxt() should not have separators for empty documents")
 	}
 }
+
+// TestToLLMSTxt_Config tests base URLs, frontmatter descriptions, the
+// Optional section and TOC section titles.
+func TestToLLMSTxt_Config(t *testing.T) {
+	documents := []scanner.Document{
+		{
+			Title:        "Install",
+			RelativePath: "getting-started/install.md",
+			Content:      []byte("First paragraph."),
+			Metadata:     map[string]interface{}{"description": "How to  install\nJot."},
+		},
+		{
+			Title:        "Changelog",
+			RelativePath: "changelog.md",
+			Content:      []byte("Release notes."),
+			Metadata:     map[string]interface{}{"optional": true},
+		},
+		{
+			Title:        "Old API",
+			RelativePath: "archive/old-api.md",
+			Content:      []byte("Deprecated."),
+		},
+	}
+	config := ProjectConfig{
+		Name:          "Jot",
+		Description:   "Docs",
+		BaseURL:       "https://example.com/docs/",
+		LinkExtension: ".html",
+		Optional:      []string{"archive/**"},
+	}
+
+	exporter := NewLLMSTxtExporter()
+	exporter.SetTOC(toc.NewBuilder().Build(documents))
+	result, err := exporter.ToLLMSTxt(documents, config)
+	if err != nil {
+		t.Fatalf("ToLLMSTxt() error = %v", err)
+	}
+
+	want := "## Getting Started\n\n" +
+		"- [Install](https://example.com/docs/getting-started/install.html): How to install Jot.\n\n" +
+		"## Optional\n\n" +
+		"- [Old API](https://example.com/docs/archive/old-api.html): Deprecated.\n" +
+		"- [Changelog](https://example.com/docs/changelog.html): Release notes.\n\n"
+	if !strings.HasSuffix(result, want) {
+		t.Errorf("ToLLMSTxt() = %q, want suffix %q", result, want)
+	}
+}
+
+// TestTruncateRunes tests that descriptions are cut on rune boundaries.
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short", "héllo", 5, "héllo"},
+		{"multi-byte", "ééééééé", 6, "ééé..."},
+		{"trailing space", "ab cd ef", 6, "ab..."},
+	}
+	for _, tt := range tests {
+		got := truncateRunes(tt.in, tt.max)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("%s: truncateRunes(%q, %d) = %q, want %q", tt.name, tt.in, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+// ManifestVersion is the version of the manifest format.
+const ManifestVersion = "1"
+
+// Manifest records which documents and chunks an export contained, so that
+// consumers can compare two exports and apply only the differences.
+type Manifest struct {
+	Version   string             `json:"version"`
+	Generated string             `json:"generated"`
+	Tokenizer string             `json:"tokenizer,omitempty"` // Encoding used for chunk token counts
+	Documents []ManifestDocument `json:"documents"`
+
+	index map[string]int // Document ID -> position in Documents
+}
+
+// ManifestDocument lists the chunks exported for one document.
+type ManifestDocument struct {
+	ID     string   `json:"id"`     // Document ID
+	Source string   `json:"source"` // Source file path (relative)
+	Hash   string   `json:"hash"`   // SHA-256 of the document content
+	Chunks []string `json:"chunks"` // Chunk IDs in document order
+	Hashes []string `json:"hashes"` // RecordHash of each chunk, in the order of Chunks
+}
+
+// NewManifest creates an empty manifest.
+func NewManifest() *Manifest {
+	return &Manifest{
+		Version:   ManifestVersion,
+		Generated: time.Now().Format(time.RFC3339),
+		Documents: []ManifestDocument{},
+	}
+}
+
+// AddChunk records a chunk of doc. Documents are listed in the order their
+// first chunk was added.
+func (m *Manifest) AddChunk(doc scanner.Document, chunk ChunkMetadata) {
+	if m.index == nil {
+		m.buildIndex()
+	}
+
+	i, ok := m.index[doc.ID]
+	if !ok {
+		sum := sha256.Sum256(doc.Content)
+		m.Documents = append(m.Documents, ManifestDocument{
+			ID:     doc.ID,
+			Source: doc.RelativePath,
+			Hash:   hex.EncodeToString(sum[:]),
+			Chunks: []string{},
+			Hashes: []string{},
+		})
+		i = len(m.Documents) - 1
+		m.index[doc.ID] = i
+	}
+	m.Documents[i].Chunks = append(m.Documents[i].Chunks, chunk.ChunkID)
+	m.Documents[i].Hashes = append(m.Documents[i].Hashes, RecordHash(chunk))
+	if m.Tokenizer == "" {
+		m.Tokenizer = chunk.Tokenizer
+	}
+}
+
+// Document returns the entry for the document with the given ID.
+func (m *Manifest) Document(id string) (ManifestDocument, bool) {
+	if m.index == nil {
+		m.buildIndex()
+	}
+	i, ok := m.index[id]
+	if !ok {
+		return ManifestDocument{}, false
+	}
+	return m.Documents[i], true
+}
+
+// ChunkIDs returns the set of all chunk IDs in the manifest.
+func (m *Manifest) ChunkIDs() map[string]bool {
+	ids := make(map[string]bool)
+	for _, doc := range m.Documents {
+		for _, id := range doc.Chunks {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// ChunkHashes returns the record hash of every chunk in the manifest by chunk
+// ID. Chunks of manifests written without hashes map to "", which matches no
+// record.
+func (m *Manifest) ChunkHashes() map[string]string {
+	hashes := make(map[string]string)
+	for _, doc := range m.Documents {
+		for i, id := range doc.Chunks {
+			hashes[id] = ""
+			if i < len(doc.Hashes) {
+				hashes[id] = doc.Hashes[i]
+			}
+		}
+	}
+	return hashes
+}
+
+// RecordHash returns the SHA-256 of a chunk's record without its vector and
+// context preamble, which are derived from the rest. Incremental exports emit
+// a chunk again when its record hash changed, for example because a chunk was
+// inserted next to it and its neighbour IDs and positions moved.
+func RecordHash(chunk ChunkMetadata) string {
+	chunk.Vector = nil
+	chunk.Context = ""
+	data, _ := json.Marshal(chunk)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// buildIndex indexes Documents by ID, for manifests that were decoded.
+func (m *Manifest) buildIndex() {
+	m.index = make(map[string]int, len(m.Documents))
+	for i, doc := range m.Documents {
+		m.index[doc.ID] = i
+	}
+}
+
+// Write writes the manifest to w as indented JSON.
+func (m *Manifest) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads a manifest written by Manifest.Write.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %q in %s (expected %s)", m.Version, path, ManifestVersion)
+	}
+	m.buildIndex()
+	return &m, nil
+}
+
+// ManifestDiff describes the changes between two exports.
+type ManifestDiff struct {
+	AddedDocuments   []string `json:"added_documents"`   // IDs of documents only in the new export
+	ChangedDocuments []string `json:"changed_documents"` // IDs of documents whose content hash changed
+	RemovedDocuments []string `json:"removed_documents"` // IDs of documents only in the old export
+	AddedChunks      []string `json:"added_chunks"`      // IDs of chunks only in the new export
+	RemovedChunks    []string `json:"removed_chunks"`    // IDs of chunks only in the old export
+}
+
+// DiffManifests compares the manifest of a previous export with the current
+// one. Because chunk IDs are derived from chunk content, unchanged chunks of
+// edited documents are neither added nor removed. All lists are sorted.
+func DiffManifests(previous, current *Manifest) ManifestDiff {
+	diff := ManifestDiff{
+		AddedDocuments:   []string{},
+		ChangedDocuments: []string{},
+		RemovedDocuments: []string{},
+		AddedChunks:      []string{},
+		RemovedChunks:    []string{},
+	}
+
+	for _, doc := range current.Documents {
+		before, ok := previous.Document(doc.ID)
+		switch {
+		case !ok:
+			diff.AddedDocuments = append(diff.AddedDocuments, doc.ID)
+		case before.Hash != doc.Hash:
+			diff.ChangedDocuments = append(diff.ChangedDocuments, doc.ID)
+		}
+	}
+	for _, doc := range previous.Documents {
+		if _, ok := current.Document(doc.ID); !ok {
+			diff.RemovedDocuments = append(diff.RemovedDocuments, doc.ID)
+		}
+	}
+
+	previousChunks, currentChunks := previous.ChunkIDs(), current.ChunkIDs()
+	for id := range currentChunks {
+		if !previousChunks[id] {
+			diff.AddedChunks = append(diff.AddedChunks, id)
+		}
+	}
+	for id := range previousChunks {
+		if !currentChunks[id] {
+			diff.RemovedChunks = append(diff.RemovedChunks, id)
+		}
+	}
+
+	sort.Strings(diff.AddedDocuments)
+	sort.Strings(diff.ChangedDocuments)
+	sort.Strings(diff.RemovedDocuments)
+	sort.Strings(diff.AddedChunks)
+	sort.Strings(diff.RemovedChunks)
+	return diff
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// exportManifest exports docs with the markdown strategy and returns the manifest.
+func exportManifest(t *testing.T, docs []scanner.Document) *Manifest {
+	t.Helper()
+	manifest := NewManifest()
+	tok := tokenizer.NewHeuristicTokenizer()
+	exporter := NewJSONLExporter()
+	exporter.SetStrategy(chunking.NewMarkdownStrategy(tok))
+	exporter.SetTokenizer(tok)
+	exporter.SetManifest(manifest)
+	if _, err := exporter.ToJSONL(docs, 512, 0); err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+	return manifest
+}
+
+// TestManifest tests manifest recording, round-tripping and diffing.
+func TestManifest(t *testing.T) {
+	guide := scanner.Document{ID: "guide", RelativePath: "guide.md", Content: []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it.")}
+	faq := scanner.Document{ID: "faq", RelativePath: "faq.md", Content: []byte("# FAQ\n\nAsk.")}
+	news := scanner.Document{ID: "news", RelativePath: "news.md", Content: []byte("# News\n\nNothing yet.")}
+
+	previous := exportManifest(t, []scanner.Document{guide, faq})
+	if len(previous.Documents) != 2 || len(previous.Documents[0].Chunks) != 2 || previous.Tokenizer != "heuristic" {
+		t.Fatalf("manifest = %+v, want 2 documents with guide split into 2 chunks", previous)
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	var buf bytes.Buffer
+	if err := previous.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previous, err := ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+
+	// Edit the second section of the guide, drop the FAQ, add news
+	edited := guide
+	edited.Content = []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it twice.")
+	current := exportManifest(t, []scanner.Document{edited, news})
+
+	diff := DiffManifests(previous, current)
+	guideBefore, _ := previous.Document("guide")
+	guideAfter, _ := current.Document("guide")
+	newsChunks, _ := current.Document("news")
+	faqChunks, _ := previous.Document("faq")
+
+	want := ManifestDiff{
+		AddedDocuments:   []string{"news"},
+		ChangedDocuments: []string{"guide"},
+		RemovedDocuments: []string{"faq"},
+		AddedChunks:      sorted(guideAfter.Chunks[1], newsChunks.Chunks[0]),
+		RemovedChunks:    sorted(guideBefore.Chunks[1], faqChunks.Chunks[0]),
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffManifests() = %+v, want %+v", diff, want)
+	}
+	if guideBefore.Chunks[0] != guideAfter.Chunks[0] {
+		t.Errorf("unchanged chunk ID changed from %s to %s", guideBefore.Chunks[0], guideAfter.Chunks[0])
+	}
+}
+
+// TestEachChunk_DuplicateIDs tests that colliding chunk IDs fail the export.
+func TestEachChunk_DuplicateIDs(t *testing.T) {
+	docs := []scanner.Document{
+		{ID: "same", RelativePath: "a/index.md", Content: []byte("Hello.")},
+		{ID: "same", RelativePath: "b/index.md", Content: []byte("Hello.")},
+	}
+
+	exporter := NewJSONLExporter()
+	exporter.SetStrategy(chunking.NewFixedSizeStrategy(tokenizer.NewHeuristicTokenizer()))
+	_, err := exporter.ToJSONL(docs, 512, 0)
+	if err == nil || !strings.Contains(err.Error(), "duplicate chunk ID") {
+		t.Errorf("ToJSONL() error = %v, want duplicate chunk ID error", err)
+	}
+}
+
+// sorted returns its arguments in sorted order.
+func sorted(ids ...string) []string {
+	if ids[0] > ids[1] {
+		ids[0], ids[1] = ids[1], ids[0]
+	}
+	return ids
+}
+
+// countingEmbedder records the texts it was asked to embed.
+type countingEmbedder struct {
+	texts []string
+}
+
+func (c *countingEmbedder) Name() string { return "counting" }
+
+func (c *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	c.texts = append(c.texts, texts...)
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{1}
+	}
+	return vectors, nil
+}
+
+// TestWriteJSONL_Since tests that incremental exports emit and embed only
+// new chunks and the neighbours whose links changed, while recording all
+// chunks in the manifest.
+func TestWriteJSONL_Since(t *testing.T) {
+	guide := scanner.Document{ID: "guide", RelativePath: "guide.md", Content: []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it.\n\n## Usage\n\nUse it.")}
+	previous := exportManifest(t, []scanner.Document{guide})
+
+	edited := guide
+	edited.Content = []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it.\n\n## Usage\n\nUse it twice.")
+
+	tok := tokenizer.NewHeuristicTokenizer()
+	embedder := &countingEmbedder{}
+	current := NewManifest()
+	exporter := NewJSONLExporter()
+	exporter.SetStrategy(chunking.NewMarkdownStrategy(tok))
+	exporter.SetTokenizer(tok)
+	exporter.SetEmbedder(embedder)
+	exporter.SetManifest(current)
+	exporter.SetSince(previous)
+
+	output, err := exporter.ToJSONL([]scanner.Document{edited}, 512, 0)
+	if err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+
+	// Install is unchanged but its next chunk is new; Intro is not emitted
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "Run it.") || !strings.Contains(lines[1], "Use it twice.") {
+		t.Errorf("ToJSONL() = %q, want the changed chunk and its previous neighbour", output)
+	}
+	if len(embedder.texts) != 2 {
+		t.Errorf("embedded %d texts, want 2", len(embedder.texts))
+	}
+	if doc, _ := current.Document("guide"); len(doc.Chunks) != 3 || len(doc.Hashes) != 3 {
+		t.Errorf("manifest chunks = %v, hashes = %v, want all chunks recorded", doc.Chunks, doc.Hashes)
+	}
+
+	diff := DiffManifests(previous, current)
+	if len(diff.RemovedChunks) != 1 || len(diff.AddedChunks) != 1 {
+		t.Errorf("DiffManifests() = %+v, want one added and one removed chunk", diff)
+	}
+}
+
+// TestWriteJSONL_SinceInsertedChunk tests that inserting a chunk between two
+// existing ones emits both neighbours again with links to the new chunk, and
+// the chunks after it with their new positions.
+func TestWriteJSONL_SinceInsertedChunk(t *testing.T) {
+	guide := scanner.Document{ID: "guide", RelativePath: "guide.md", Content: []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it.\n\n## Usage\n\nUse it.")}
+	previous := exportManifest(t, []scanner.Document{guide})
+
+	edited := guide
+	edited.Content = []byte("# Guide\n\nIntro.\n\n## Install\n\nRun it.\n\n## Configure\n\nSet it up.\n\n## Usage\n\nUse it.")
+
+	tok := tokenizer.NewHeuristicTokenizer()
+	exporter := NewJSONLExporter()
+	exporter.SetStrategy(chunking.NewMarkdownStrategy(tok))
+	exporter.SetTokenizer(tok)
+	exporter.SetSince(previous)
+
+	output, err := exporter.ToJSONL([]scanner.Document{edited}, 512, 0)
+	if err != nil {
+		t.Fatalf("ToJSONL() error = %v", err)
+	}
+
+	var records []ChunkMetadata
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var record ChunkMetadata
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ToJSONL() emitted %d chunks, want Install, Configure and Usage:\n%s", len(records), output)
+	}
+
+	install, configure, usage := records[0], records[1], records[2]
+	if !strings.Contains(configure.Text, "Set it up.") {
+		t.Errorf("second chunk = %q, want the inserted chunk", configure.Text)
+	}
+	if install.NextChunkID != configure.ChunkID || usage.PrevChunkID != configure.ChunkID {
+		t.Errorf("neighbour links = %s -> %s <- %s, want both to point at %s", install.ChunkID, install.NextChunkID, usage.PrevChunkID, configure.ChunkID)
+	}
+	if usage.StartPos != strings.Index(string(edited.Content), "## Usage") {
+		t.Errorf("Usage start_pos = %d, want its new position", usage.StartPos)
+	}
+	if ids := previous.ChunkIDs(); !ids[install.ChunkID] || !ids[usage.ChunkID] {
+		t.Errorf("Install and Usage chunk IDs changed, want them stable")
+	}
+}
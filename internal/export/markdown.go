@@ -3,10 +3,18 @@
 package export
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/enrich"
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/tokenizer"
 	"gopkg.in/yaml.v3"
@@ -15,91 +23,306 @@ import (
 // MarkdownExporter handles the conversion of scanned documents into enriched markdown
 // format with YAML frontmatter metadata.
 type MarkdownExporter struct {
-	tokenizer tokenizer.Tokenizer
+	tokenizer     tokenizer.Tokenizer
+	strategy      chunking.ChunkStrategy // Optional; when set, documents are emitted per chunk
+	maxTokens     int
+	overlapTokens int
+	enricher      *enrich.Enricher // Optional; when set, a context preamble precedes each block
 }
 
-// NewMarkdownExporter creates and returns a new MarkdownExporter instance.
+// NewMarkdownExporter creates and returns a new MarkdownExporter instance
+// counting tokens with the default cl100k_base encoding.
 func NewMarkdownExporter() (*MarkdownExporter, error) {
 	tok, err := tokenizer.NewTokenizer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize tokenizer: %w", err)
 	}
 
+	return NewMarkdownExporterWithTokenizer(tok), nil
+}
+
+// NewMarkdownExporterWithTokenizer creates a MarkdownExporter counting tokens
+// with the given tokenizer.
+func NewMarkdownExporterWithTokenizer(tok tokenizer.Tokenizer) *MarkdownExporter {
 	return &MarkdownExporter{
 		tokenizer: tok,
-	}, nil
+	}
+}
+
+// SetStrategy configures the chunking strategy used to split each document into
+// separately annotated chunks. Passing nil emits one block per document.
+func (m *MarkdownExporter) SetStrategy(strategy chunking.ChunkStrategy, maxTokens, overlapTokens int) {
+	m.strategy = strategy
+	m.maxTokens = maxTokens
+	m.overlapTokens = overlapTokens
+}
+
+// SetEnricher configures contextual enrichment. Passing nil disables it.
+func (m *MarkdownExporter) SetEnricher(enricher *enrich.Enricher) {
+	m.enricher = enricher
 }
 
 // MarkdownFrontmatter represents the YAML frontmatter metadata for a markdown document.
 type MarkdownFrontmatter struct {
-	Source     string `yaml:"source"`
-	Section    string `yaml:"section"`
-	ChunkID    string `yaml:"chunk_id"`
-	TokenCount int    `yaml:"token_count"`
-	Modified   string `yaml:"modified"`
+	Source     string `yaml:"source" json:"source"`
+	Section    string `yaml:"section" json:"section"`
+	ChunkID    string `yaml:"chunk_id" json:"chunk_id"`
+	TokenCount int    `yaml:"token_count" json:"token_count"`
+	Modified   string `yaml:"modified" json:"modified"`
+	Tokenizer  string `yaml:"tokenizer,omitempty" json:"tokenizer,omitempty"`
+}
+
+// MarkdownIndexFile is the name of the index written by WriteSeparateFiles.
+const MarkdownIndexFile = "index.json"
+
+// MarkdownIndex lists the files of a split enriched markdown export.
+type MarkdownIndex struct {
+	Version   string               `json:"version"`
+	Generated string               `json:"generated"`
+	Files     []MarkdownIndexEntry `json:"files"`
+}
+
+// MarkdownIndexEntry describes one file of a split export: its path relative
+// to the output directory and the frontmatter written into it.
+type MarkdownIndexEntry struct {
+	Path string `json:"path"`
+	MarkdownFrontmatter
 }
 
 // ToEnrichedMarkdown exports documents to enriched markdown format with YAML frontmatter.
-// If separateFiles is true, it returns a map of filenames to content for separate files.
-// If separateFiles is false, it returns a single concatenated markdown string.
+// It returns a single concatenated markdown string. Separate files need an
+// output directory, so separateFiles=true is an error; use WriteSeparateFiles.
 func (m *MarkdownExporter) ToEnrichedMarkdown(documents []scanner.Document, separateFiles bool) (string, error) {
 	if separateFiles {
-		// TODO: Implement separate files logic in future iteration
-		return "", fmt.Errorf("separate files mode not yet implemented")
+		return "", fmt.Errorf("separate files mode writes to a directory; use WriteSeparateFiles")
 	}
 
 	var result strings.Builder
+	if err := m.WriteEnrichedMarkdown(&result, documents); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// WriteSeparateFiles writes each document, or each chunk when a strategy is
+// set, to its own file under dir, mirroring the source tree: guide/install.md
+// becomes dir/guide/install.md, or dir/guide/install.001.md, install.002.md
+// and so on per chunk. Every file starts with its YAML frontmatter. An index
+// of all files is written to dir/index.json and returned. Two documents that
+// map to the same file, such as guide.md and guide.markdown split into
+// chunks, are an error.
+func (m *MarkdownExporter) WriteSeparateFiles(dir string, documents []scanner.Document) (*MarkdownIndex, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	index := &MarkdownIndex{
+		Version:   MarkdownIndexVersion,
+		Generated: time.Now().Format(time.RFC3339),
+		Files:     []MarkdownIndexEntry{},
+	}
+
+	written := make(map[string]string) // file name -> source document
+	for _, doc := range documents {
+		blocks, err := m.blocks(doc)
+		if err != nil {
+			return nil, err
+		}
 
+		source := filepath.ToSlash(filepath.Clean(doc.RelativePath))
+		if strings.HasPrefix(source, "../") || filepath.IsAbs(doc.RelativePath) {
+			return nil, fmt.Errorf("document path %s is outside the export directory", doc.RelativePath)
+		}
+		base := strings.TrimSuffix(source, filepath.Ext(source))
+
+		for i, block := range blocks {
+			name := source
+			if m.strategy != nil {
+				name = fmt.Sprintf("%s.%03d.md", base, i+1)
+			}
+			if other, ok := written[name]; ok {
+				return nil, fmt.Errorf("%s and %s both export to %s", other, doc.RelativePath, name)
+			}
+			written[name] = doc.RelativePath
+
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, fmt.Errorf("failed to create directory for %s: %w", name, err)
+			}
+			if err := os.WriteFile(path, []byte(block.text+"\n"), 0644); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+
+			index.Files = append(index.Files, MarkdownIndexEntry{Path: name, MarkdownFrontmatter: block.frontmatter})
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal markdown index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MarkdownIndexFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write markdown index: %w", err)
+	}
+
+	return index, nil
+}
+
+// markdownBlock is a document or chunk with its frontmatter, rendered as
+// enriched markdown.
+type markdownBlock struct {
+	frontmatter MarkdownFrontmatter
+	text        string
+}
+
+// WriteEnrichedMarkdown writes documents to w as a single enriched markdown
+// stream, like ToEnrichedMarkdown. Each document is rendered and written
+// before the next one is processed.
+func (m *MarkdownExporter) WriteEnrichedMarkdown(w io.Writer, documents []scanner.Document) error {
 	// Generate table of contents first
 	toc := m.generateTableOfContents(documents)
-	result.WriteString(toc)
-	result.WriteString("\n\n")
+	if _, err := io.WriteString(w, toc+"\n\n"); err != nil {
+		return fmt.Errorf("failed to write table of contents: %w", err)
+	}
 
 	// Process each document
 	for i, doc := range documents {
-		// Generate YAML frontmatter
+		blocks, err := m.blocks(doc)
+		if err != nil {
+			return err
+		}
+
+		var result strings.Builder
+		for j, block := range blocks {
+			if j > 0 {
+				result.WriteString("\n\n")
+			}
+			result.WriteString(block.text)
+		}
+
+		// Add separator between documents (except for last one)
+		if i < len(documents)-1 {
+			result.WriteString("\n\n---\n\n")
+		}
+
+		if _, err := io.WriteString(w, result.String()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", doc.RelativePath, err)
+		}
+	}
+
+	return nil
+}
+
+// blocks renders doc as one block per chunk produced by the configured
+// strategy, or as a single block without one.
+func (m *MarkdownExporter) blocks(doc scanner.Document) ([]markdownBlock, error) {
+	if m.strategy != nil {
+		return m.chunkBlocks(doc)
+	}
+
+	// Generate YAML frontmatter
+	frontmatter := MarkdownFrontmatter{
+		Source:     doc.RelativePath,
+		ChunkID:    doc.ID,
+		TokenCount: m.tokenizer.Count(string(doc.Content)),
+		Modified:   doc.ModTime.Format(time.RFC3339),
+		Tokenizer:  m.tokenizer.Encoding(),
+	}
+
+	// Set section from first section title if available
+	if len(doc.Sections) > 0 {
+		frontmatter.Section = doc.Sections[0].Title
+	} else {
+		frontmatter.Section = doc.Title
+	}
+
+	// Add contextual enrichment for the document as a whole
+	whole := []chunk.Chunk{{ID: doc.ID, Text: string(doc.Content), EndPos: len(doc.Content)}}
+	enrichment, err := m.contextualEnrichment(doc, whole)
+	if err != nil {
+		return nil, err
+	}
+
+	// Preserve original markdown content
+	text, err := renderBlock(frontmatter, enrichment[0]+string(doc.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal frontmatter for %s: %w", doc.RelativePath, err)
+	}
+	return []markdownBlock{{frontmatter: frontmatter, text: text}}, nil
+}
+
+// chunkBlocks renders doc as a sequence of chunks produced by the configured
+// strategy, each preceded by its own YAML frontmatter.
+func (m *MarkdownExporter) chunkBlocks(doc scanner.Document) ([]markdownBlock, error) {
+	chunks, err := m.strategy.Chunk(doc, m.maxTokens, m.overlapTokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to chunk %s: %w", doc.RelativePath, err)
+	}
+
+	enrichment, err := m.contextualEnrichment(doc, chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]markdownBlock, len(chunks))
+	for j, chunk := range chunks {
 		frontmatter := MarkdownFrontmatter{
 			Source:     doc.RelativePath,
-			ChunkID:    doc.ID,
-			TokenCount: m.tokenizer.Count(string(doc.Content)),
+			Section:    sectionAt(doc, chunk.StartPos),
+			ChunkID:    chunk.ID,
+			TokenCount: chunk.TokenCount,
 			Modified:   doc.ModTime.Format(time.RFC3339),
+			Tokenizer:  encodingName(m.tokenizer),
 		}
 
-		// Set section from first section title if available
-		if len(doc.Sections) > 0 {
-			frontmatter.Section = doc.Sections[0].Title
-		} else {
-			frontmatter.Section = doc.Title
+		if len(chunk.HeadingPath) > 0 {
+			frontmatter.Section = strings.Join(chunk.HeadingPath, " > ")
 		}
 
-		// Marshal frontmatter to YAML
-		yamlData, err := yaml.Marshal(frontmatter)
+		text, err := renderBlock(frontmatter, enrichment[j]+strings.TrimSpace(chunk.Text))
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal frontmatter for %s: %w", doc.RelativePath, err)
+			return nil, fmt.Errorf("failed to marshal frontmatter for %s: %w", chunk.ID, err)
 		}
+		blocks[j] = markdownBlock{frontmatter: frontmatter, text: text}
+	}
 
-		// Write frontmatter with delimiters
-		result.WriteString("---\n")
-		result.WriteString(string(yamlData))
-		result.WriteString("---\n\n")
+	return blocks, nil
+}
 
-		// Preserve original markdown content
-		result.WriteString(string(doc.Content))
+// renderBlock returns body preceded by frontmatter between "---" delimiters.
+func renderBlock(frontmatter MarkdownFrontmatter, body string) (string, error) {
+	yamlData, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + string(yamlData) + "---\n\n" + body, nil
+}
 
-		// Add contextual enrichment (stub for future Anthropic-style enrichment)
-		enrichment := m.contextualEnrichment(doc, string(doc.Content))
-		if enrichment != "" {
-			result.WriteString("\n\n")
-			result.WriteString(enrichment)
+// sectionAt returns the title of the last section heading at or before the
+// given byte offset, falling back to the document title. Headings inside
+// fenced code blocks are ignored.
+func sectionAt(doc scanner.Document, offset int) string {
+	section := doc.Title
+	content := string(doc.Content)
+	pos := 0
+	inFence := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if pos > offset {
+			break
 		}
-
-		// Add separator between documents (except for last one)
-		if i < len(documents)-1 {
-			result.WriteString("\n\n---\n\n")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		} else if !inFence && strings.HasPrefix(trimmed, "#") {
+			title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if title != "" {
+				section = title
+			}
 		}
+		pos += len(line)
 	}
-
-	return result.String(), nil
+	return section
 }
 
 // generateTableOfContents creates a markdown table of contents with anchor links.
@@ -143,20 +366,24 @@ func (m *MarkdownExporter) generateTableOfContents(documents []scanner.Document)
 	return toc.String()
 }
 
-// contextualEnrichment is a placeholder method for optional Anthropic-style context injection.
-// This will be implemented in a future iteration to add contextual information as HTML comments
-// or other enrichment metadata to improve LLM understanding.
-//
-// TODO: Implement Anthropic-style contextual retrieval enrichment
-// See: https://www.anthropic.com/news/contextual-retrieval
-// This should inject relevant context from the broader document collection to help
-// LLMs better understand each chunk in isolation.
-func (m *MarkdownExporter) contextualEnrichment(doc scanner.Document, fullContext string) string {
-	// Stub implementation - returns empty string for now
-	// Future implementation will:
-	// 1. Analyze document context and related documents
-	// 2. Generate contextual hints as HTML comments
-	// 3. Inject them at strategic points in the markdown
-	// 4. Follow Anthropic's contextual retrieval patterns
-	return ""
+// contextualEnrichment returns, for each chunk, an HTML comment carrying the
+// chunk's context preamble followed by a blank line, or an empty string when
+// no enricher is configured. HTML comments keep the preamble out of rendered
+// output while leaving it visible to LLMs reading the markdown.
+func (m *MarkdownExporter) contextualEnrichment(doc scanner.Document, chunks []chunk.Chunk) ([]string, error) {
+	enrichment := make([]string, len(chunks))
+	if m.enricher == nil {
+		return enrichment, nil
+	}
+
+	if err := m.enricher.Enrich(context.Background(), doc, chunks); err != nil {
+		return nil, err
+	}
+	for i, c := range chunks {
+		if c.Context != "" {
+			// "--" is not allowed inside HTML comments
+			enrichment[i] = "<!-- Context:\n" + strings.ReplaceAll(c.Context, "--", "- -") + "\n-->\n\n"
+		}
+	}
+	return enrichment, nil
 }
@@ -1,16 +1,30 @@
 package export
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/enrich"
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
 	"gopkg.in/yaml.v3"
 )
 
 // TestNewMarkdownExporter tests the constructor for MarkdownExporter.
 func TestNewMarkdownExporter(t *testing.T) {
+	// The default tokenizer needs the cl100k_base data, which offline runs
+	// may not have; the other tests use the heuristic tokenizer
+	if _, err := tokenizer.NewTokenizer(); err != nil {
+		t.Skipf("default tokenizer unavailable: %v", err)
+	}
+
 	exporter, err := NewMarkdownExporter()
 	if err != nil {
 		t.Fatalf("NewMarkdownExporter() failed: %v", err)
@@ -27,10 +41,7 @@ func TestNewMarkdownExporter(t *testing.T) {
 
 // TestToEnrichedMarkdown_SingleDocument tests enriched markdown export with a single document.
 func TestToEnrichedMarkdown_SingleDocument(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	// Create test document
 	modTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
@@ -92,10 +103,7 @@ func TestToEnrichedMarkdown_SingleDocument(t *testing.T) {
 
 // TestToEnrichedMarkdown_FrontmatterParsing tests that YAML frontmatter is valid and parseable.
 func TestToEnrichedMarkdown_FrontmatterParsing(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	modTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 	doc := scanner.Document{
@@ -175,10 +183,7 @@ func TestToEnrichedMarkdown_FrontmatterParsing(t *testing.T) {
 
 // TestToEnrichedMarkdown_MultipleDocuments tests exporting multiple documents.
 func TestToEnrichedMarkdown_MultipleDocuments(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	modTime := time.Now()
 	docs := []scanner.Document{
@@ -249,10 +254,7 @@ func TestToEnrichedMarkdown_MultipleDocuments(t *testing.T) {
 
 // TestToEnrichedMarkdown_CodeBlockPreservation tests that code blocks are preserved.
 func TestToEnrichedMarkdown_CodeBlockPreservation(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	content := `# Code Example
 
@@ -306,10 +308,7 @@ That's the example.`
 
 // TestToEnrichedMarkdown_LinksPreservation tests that links are preserved.
 func TestToEnrichedMarkdown_LinksPreservation(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	content := `# Links Example
 
@@ -347,10 +346,7 @@ Check out [this guide](./guide.md) and visit [our website](https://example.com).
 
 // TestGenerateTableOfContents tests TOC generation in isolation.
 func TestGenerateTableOfContents(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	docs := []scanner.Document{
 		{
@@ -398,12 +394,10 @@ func TestGenerateTableOfContents(t *testing.T) {
 	}
 }
 
-// TestToEnrichedMarkdown_SeparateFilesNotImplemented tests that separate files mode returns error.
-func TestToEnrichedMarkdown_SeparateFilesNotImplemented(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+// TestToEnrichedMarkdown_SeparateFiles tests that separate files mode, which
+// needs an output directory, is rejected by ToEnrichedMarkdown.
+func TestToEnrichedMarkdown_SeparateFiles(t *testing.T) {
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	doc := scanner.Document{
 		ID:           "test-doc",
@@ -414,42 +408,132 @@ func TestToEnrichedMarkdown_SeparateFilesNotImplemented(t *testing.T) {
 		Sections:     []scanner.Section{{Title: "Test", Level: 1}},
 	}
 
-	_, err = exporter.ToEnrichedMarkdown([]scanner.Document{doc}, true)
-	if err == nil {
-		t.Error("Expected error for separate files mode, got nil")
+	_, err := exporter.ToEnrichedMarkdown([]scanner.Document{doc}, true)
+	if err == nil || !strings.Contains(err.Error(), "WriteSeparateFiles") {
+		t.Errorf("ToEnrichedMarkdown() error = %v, want error pointing at WriteSeparateFiles", err)
 	}
+}
 
-	if !strings.Contains(err.Error(), "not yet implemented") {
-		t.Errorf("Expected 'not yet implemented' error, got: %v", err)
+// TestWriteSeparateFiles tests writing one file per document or chunk with an index.
+func TestWriteSeparateFiles(t *testing.T) {
+	tok := tokenizer.NewHeuristicTokenizer()
+	docs := []scanner.Document{
+		{ID: "intro", RelativePath: "intro.md", Title: "Intro", Content: []byte("# Intro\n\nHello."), ModTime: time.Now()},
+		{ID: "install", RelativePath: "guide/install.md", Title: "Install", Content: []byte("# Install\n\nStep one.\n\n## Verify\n\nStep two."), ModTime: time.Now()},
+	}
+
+	tests := []struct {
+		name     string
+		strategy chunking.ChunkStrategy
+		want     []string
+	}{
+		{"per document", nil, []string{"intro.md", "guide/install.md"}},
+		{"per chunk", chunking.NewMarkdownHeaderStrategy(tok), []string{"intro.001.md", "guide/install.001.md", "guide/install.002.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			exporter := NewMarkdownExporterWithTokenizer(tok)
+			exporter.SetStrategy(tt.strategy, 512, 0)
+
+			index, err := exporter.WriteSeparateFiles(dir, docs)
+			if err != nil {
+				t.Fatalf("WriteSeparateFiles() error = %v", err)
+			}
+
+			var paths []string
+			for _, entry := range index.Files {
+				paths = append(paths, entry.Path)
+				data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(entry.Path)))
+				if err != nil {
+					t.Fatalf("file %s not written: %v", entry.Path, err)
+				}
+				if !strings.HasPrefix(string(data), "---\nsource: "+entry.Source+"\n") || !strings.Contains(string(data), "chunk_id: "+entry.ChunkID) {
+					t.Errorf("%s frontmatter does not match index entry %+v:\n%s", entry.Path, entry, data)
+				}
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("WriteSeparateFiles() files = %v, want %v", paths, tt.want)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, MarkdownIndexFile))
+			if err != nil {
+				t.Fatalf("index not written: %v", err)
+			}
+			var written MarkdownIndex
+			if err := json.Unmarshal(data, &written); err != nil || len(written.Files) != len(tt.want) || written.Files[0].TokenCount == 0 || written.Version != MarkdownIndexVersion {
+				t.Errorf("index = %s (error %v), want version %s and %d files with token counts", data, err, MarkdownIndexVersion, len(tt.want))
+			}
+		})
+	}
+}
+
+// TestWriteSeparateFiles_Collision tests that documents mapping to the same
+// file are an error instead of overwriting each other.
+func TestWriteSeparateFiles_Collision(t *testing.T) {
+	tok := tokenizer.NewHeuristicTokenizer()
+
+	tests := []struct {
+		name     string
+		strategy chunking.ChunkStrategy
+		paths    []string
+		want     string
+	}{
+		{"same path from two inputs", nil, []string{"guide.md", "guide.md"}, "guide.md and guide.md both export to guide.md"},
+		{"same base name per chunk", chunking.NewMarkdownHeaderStrategy(tok), []string{"guide.md", "guide.markdown"}, "guide.md and guide.markdown both export to guide.001.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var docs []scanner.Document
+			for i, path := range tt.paths {
+				docs = append(docs, scanner.Document{ID: fmt.Sprintf("doc%d", i), RelativePath: path, Content: []byte("# Guide\n\nHello."), ModTime: time.Now()})
+			}
+
+			exporter := NewMarkdownExporterWithTokenizer(tok)
+			exporter.SetStrategy(tt.strategy, 512, 0)
+			_, err := exporter.WriteSeparateFiles(t.TempDir(), docs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("WriteSeparateFiles() error = %v, want %q", err, tt.want)
+			}
+		})
 	}
 }
 
 // TestContextualEnrichment tests the placeholder enrichment method.
 func TestContextualEnrichment(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := &MarkdownExporter{}
 
 	doc := scanner.Document{
 		ID:      "test-doc",
 		Title:   "Test",
-		Content: []byte("Test content"),
+		Content: []byte("# Test\n\nTest content.\n\n## Usage\n\nRun it."),
+	}
+	chunks := []Chunk{{ID: "test-doc-chunk-0", Text: "Run it.", StartPos: 30, EndPos: 37}}
+
+	// Without an enricher no preamble is produced
+	result, err := exporter.contextualEnrichment(doc, chunks)
+	if err != nil {
+		t.Fatalf("contextualEnrichment() error = %v", err)
+	}
+	if result[0] != "" {
+		t.Errorf("Expected empty enrichment without enricher, got: %s", result[0])
 	}
 
-	// Should return empty string as it's a stub
-	result := exporter.contextualEnrichment(doc, "full context")
-	if result != "" {
-		t.Errorf("Expected empty string from stub, got: %s", result)
+	exporter.SetEnricher(enrich.NewEnricher())
+	result, err = exporter.contextualEnrichment(doc, chunks)
+	if err != nil {
+		t.Fatalf("contextualEnrichment() error = %v", err)
+	}
+	if !strings.HasPrefix(result[0], "<!-- Context:\n") || !strings.Contains(result[0], "Section: Test > Usage") {
+		t.Errorf("Unexpected enrichment: %q", result[0])
 	}
 }
 
 // TestToEnrichedMarkdown_EmptyDocuments tests handling of empty document list.
 func TestToEnrichedMarkdown_EmptyDocuments(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	result, err := exporter.ToEnrichedMarkdown([]scanner.Document{}, false)
 	if err != nil {
@@ -464,10 +548,7 @@ func TestToEnrichedMarkdown_EmptyDocuments(t *testing.T) {
 
 // TestToEnrichedMarkdown_NoSections tests handling of documents without sections.
 func TestToEnrichedMarkdown_NoSections(t *testing.T) {
-	exporter, err := NewMarkdownExporter()
-	if err != nil {
-		t.Fatalf("NewMarkdownExporter() failed: %v", err)
-	}
+	exporter := NewMarkdownExporterWithTokenizer(tokenizer.NewHeuristicTokenizer())
 
 	doc := scanner.Document{
 		ID:           "no-sections",
@@ -508,3 +589,55 @@ func TestToEnrichedMarkdown_NoSections(t *testing.T) {
 		t.Errorf("Expected section to be 'Simple Doc', got '%s'", fm.Section)
 	}
 }
+
+// TestToEnrichedMarkdown_WithStrategy tests that a configured strategy emits
+// one frontmatter block per chunk with the enclosing section.
+func TestToEnrichedMarkdown_WithStrategy(t *testing.T) {
+	doc := scanner.Document{
+		ID:           "doc1",
+		Title:        "Guide",
+		RelativePath: "guide.md",
+		Content:      []byte("# Guide\n\nIntro text.\n\n## Install\n\n```sh\n# not a heading\n```\n\nRun the installer."),
+		ModTime:      time.Now(),
+	}
+
+	exporter := &MarkdownExporter{}
+	exporter.SetStrategy(paragraphStrategy{}, 512, 0)
+
+	result, err := exporter.ToEnrichedMarkdown([]scanner.Document{doc}, false)
+	if err != nil {
+		t.Fatalf("ToEnrichedMarkdown() failed: %v", err)
+	}
+
+	var frontmatters []MarkdownFrontmatter
+	for _, part := range strings.Split(result, "---\n") {
+		if !strings.Contains(part, "source:") {
+			continue
+		}
+		var fm MarkdownFrontmatter
+		if err := yaml.Unmarshal([]byte(part), &fm); err != nil {
+			t.Fatalf("Failed to parse frontmatter: %v", err)
+		}
+		frontmatters = append(frontmatters, fm)
+	}
+
+	if len(frontmatters) != 5 {
+		t.Fatalf("Expected 5 chunk frontmatters, got %d", len(frontmatters))
+	}
+
+	tests := []struct {
+		index   int
+		chunkID string
+		section string
+	}{
+		{1, "doc1-para-1", "Guide"},
+		{3, "doc1-para-3", "Install"},
+		{4, "doc1-para-4", "Install"},
+	}
+	for _, tt := range tests {
+		fm := frontmatters[tt.index]
+		if fm.ChunkID != tt.chunkID || fm.Section != tt.section {
+			t.Errorf("chunk %d = (%s, %s), want (%s, %s)", tt.index, fm.ChunkID, fm.Section, tt.chunkID, tt.section)
+		}
+	}
+}
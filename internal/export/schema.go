@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/onedusk/jot/internal/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Versions of the export formats that have a published schema. A version is
+// part of the $id of its schema in schemas/ and must be bumped whenever the
+// schema changes; TestSchemaVersions fails until it is.
+const (
+	JSONFormatVersion  = "1.1" // json and yaml formats (DocumentExport)
+	JSONLFormatVersion = "1.2" // jsonl format with the jot target (ChunkMetadata)
+	LLMFormatVersion   = "1.0" // llm format (LLMExport)
+)
+
+// MarkdownIndexVersion is the version of the index.json written by split
+// markdown exports (MarkdownIndex). The index has no published schema; bump
+// the version whenever its layout changes.
+const MarkdownIndexVersion = "1.0"
+
+// schemaFiles holds the published JSON Schemas, one per format.
+//
+//go:embed schemas/*.schema.json
+var schemaFiles embed.FS
+
+// SchemaFormats lists the formats that have a published schema, in the
+// order they are documented.
+func SchemaFormats() []string {
+	return []string{"json", "jsonl", "llm"}
+}
+
+// FormatVersion returns the version of the given export format.
+func FormatVersion(format string) (string, error) {
+	switch format {
+	case "json", "yaml":
+		return JSONFormatVersion, nil
+	case "jsonl":
+		return JSONLFormatVersion, nil
+	case "llm":
+		return LLMFormatVersion, nil
+	default:
+		return "", fmt.Errorf("no schema for format: %s (supported: %s)", format, strings.Join(SchemaFormats(), ", "))
+	}
+}
+
+// Schema returns the JSON Schema of an export format. The yaml format shares
+// the schema of json; the jsonl schema describes a single line.
+func Schema(format string) ([]byte, error) {
+	if _, err := FormatVersion(format); err != nil {
+		return nil, err
+	}
+	if format == "yaml" {
+		format = "json"
+	}
+
+	data, err := schemaFiles.ReadFile("schemas/" + format + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s schema: %w", format, err)
+	}
+	return data, nil
+}
+
+// DetectFormat guesses the format of an export from its file name and
+// content: .jsonl for JSONL, .yaml or .yml for YAML, and otherwise JSON,
+// which is the llm format when it has a semantic index. A trailing .gz is
+// ignored; data must already be decompressed.
+func DetectFormat(name string, data []byte) string {
+	switch filepath.Ext(strings.TrimSuffix(name, ".gz")) {
+	case ".jsonl":
+		return "jsonl"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err == nil {
+		if _, ok := top["index"]; ok {
+			return "llm"
+		}
+	}
+	return "json"
+}
+
+// ValidateExport checks export data against the schema of its format. JSONL
+// is validated line by line. Violations are reported as a
+// *jsonschema.ValidationError.
+func ValidateExport(format string, data []byte) error {
+	schemaData, err := Schema(format)
+	if err != nil {
+		return err
+	}
+	schema, err := jsonschema.Parse(schemaData)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "jsonl":
+		return validateJSONL(schema, data)
+	case "yaml":
+		value, err := decodeYAML(data)
+		if err != nil {
+			return err
+		}
+		return schema.Validate(value)
+	default:
+		return schema.ValidateJSON(data)
+	}
+}
+
+// validateJSONL validates every non-empty line of data, prefixing violations
+// with their line number.
+func validateJSONL(schema *jsonschema.Schema, data []byte) error {
+	var violations []string
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lines.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for number := 1; lines.Scan(); number++ {
+		line := bytes.TrimSpace(lines.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		err := schema.ValidateJSON(line)
+		if validationErr, ok := err.(*jsonschema.ValidationError); ok {
+			for _, violation := range validationErr.Violations {
+				violations = append(violations, fmt.Sprintf("line %d: %s", number, violation))
+			}
+		} else if err != nil {
+			violations = append(violations, fmt.Sprintf("line %d: %v", number, err))
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return fmt.Errorf("failed to read JSONL: %w", err)
+	}
+
+	if len(violations) > 0 {
+		return &jsonschema.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// decodeYAML decodes a YAML document into the values json.Unmarshal would
+// produce, so that it can be validated against a JSON Schema.
+func decodeYAML(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	converted, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(converted, &result); err != nil {
+		return nil, fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	return result, nil
+}
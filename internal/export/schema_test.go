@@ -0,0 +1,251 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/jsonschema"
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// update rewrites the golden files in testdata/golden: go test ./internal/export -update
+var update = flag.Bool("update", false, "update golden files")
+
+// generatedPattern matches the generation timestamp of JSON and YAML exports.
+var generatedPattern = regexp.MustCompile(`("generated": "|generated: "?)[^"\n]*`)
+
+// goldenDocuments returns fixed documents covering every optional field of
+// the export formats.
+func goldenDocuments() []scanner.Document {
+	modified := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	content := "# Install\n\nDownload the binary.\n\n## Verify\n\nRun the check:\n\n```sh\njot --version\n```\n\nSee [usage](usage.md) or [the site](https://example.com)."
+	return []scanner.Document{
+		{
+			ID:           "install",
+			RelativePath: "guide/install.md",
+			Title:        "Install",
+			Content:      []byte(content),
+			ModTime:      modified,
+			Metadata:     map[string]interface{}{"description": "Installing jot", "tags": []interface{}{"setup"}},
+			Git:          &scanner.GitInfo{Commit: "9fceb02d0ae598e95dc970b74767f19372d61af8", Updated: modified, Authors: []string{"Ada", "Grace"}, Path: "docs/guide/install.md"},
+			Sections: []scanner.Section{
+				{ID: "install", Title: "Install", Level: 1, Content: "Download the binary.", StartLine: 1, EndLine: 4},
+				{ID: "verify", Title: "Verify", Level: 2, Content: "Run the check:", StartLine: 5, EndLine: 13},
+			},
+			CodeBlocks: []scanner.CodeBlock{{Language: "sh", Content: "jot --version", StartLine: 9}},
+			Links: []scanner.Link{
+				{Text: "usage", URL: "usage.md", IsInternal: true},
+				{Text: "the site", URL: "https://example.com"},
+			},
+		},
+		{
+			ID:           "usage",
+			RelativePath: "guide/usage.md",
+			Title:        "Usage",
+			Content:      []byte("# Usage\n\nRun jot build."),
+			ModTime:      modified,
+		},
+	}
+}
+
+// goldenExport renders the golden documents in format with the generation
+// timestamp removed.
+func goldenExport(t *testing.T, format string) []byte {
+	t.Helper()
+	tok := tokenizer.NewHeuristicTokenizer()
+	docs := goldenDocuments()
+
+	var output string
+	var err error
+	switch format {
+	case "json", "yaml":
+		exporter := NewExporter()
+		if format == "json" {
+			output, err = exporter.ToJSON(docs)
+		} else {
+			output, err = exporter.ToYAML(docs)
+		}
+	case "jsonl":
+		exporter := NewJSONLExporter()
+		exporter.SetTokenizer(tok)
+		exporter.SetStrategy(chunking.NewMarkdownStrategy(tok))
+		output, err = exporter.ToJSONL(docs, 512, 0)
+	case "llm":
+		exporter := NewExporter()
+		exporter.SetTokenizer(tok)
+		exporter.SetStrategy(chunking.NewMarkdownStrategy(tok))
+		llmExport, llmErr := exporter.ToLLMFormat(docs)
+		if llmErr != nil {
+			t.Fatalf("ToLLMFormat() error = %v", llmErr)
+		}
+		data, marshalErr := json.MarshalIndent(llmExport, "", "  ")
+		output, err = string(data), marshalErr
+	}
+	if err != nil {
+		t.Fatalf("%s export error = %v", format, err)
+	}
+
+	return []byte(generatedPattern.ReplaceAllString(strings.TrimRight(output, "\n"), "${1}GENERATED") + "\n")
+}
+
+// TestGoldenExports tests that exports match the golden files in
+// testdata/golden and validate against their published schemas. A golden
+// mismatch means the output contract changed: update the schema, bump the
+// format version and rerun with -update.
+func TestGoldenExports(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "jsonl", "llm"} {
+		t.Run(format, func(t *testing.T) {
+			got := goldenExport(t, format)
+
+			if err := ValidateExport(format, got); err != nil {
+				t.Errorf("%s export does not match its schema: %v", format, err)
+			}
+
+			path := filepath.Join("testdata", "golden", format+".golden")
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s export differs from %s; if the change is intended, update the schema, bump the format version and run go test -update\ngot:\n%s", format, path, got)
+			}
+		})
+	}
+}
+
+// TestSchemaVersions tests that every schema carries its format version and
+// that a schema never changes without a version bump. testdata/golden/schemas.golden
+// records the version and hash of each schema.
+func TestSchemaVersions(t *testing.T) {
+	var golden strings.Builder
+	for _, format := range SchemaFormats() {
+		data, err := Schema(format)
+		if err != nil {
+			t.Fatalf("Schema(%q) error = %v", format, err)
+		}
+		version, err := FormatVersion(format)
+		if err != nil {
+			t.Fatalf("FormatVersion(%q) error = %v", format, err)
+		}
+
+		schema, err := jsonschema.Parse(data)
+		if err != nil {
+			t.Fatalf("%s schema does not parse: %v", format, err)
+		}
+		if !strings.HasSuffix(schema.ID, "/"+format+"/"+version) {
+			t.Errorf("%s schema $id = %q, want it to end in /%s/%s", format, schema.ID, format, version)
+		}
+		if versionProperty := schema.Properties["version"]; versionProperty != nil && versionProperty.Const != version {
+			t.Errorf("%s schema requires version %v, want %s", format, versionProperty.Const, version)
+		}
+
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&golden, "%s %s %s\n", format, version, hex.EncodeToString(sum[:]))
+	}
+
+	path := filepath.Join("testdata", "golden", "schemas.golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(golden.String()), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	wantLines := strings.Split(strings.TrimSpace(string(want)), "\n")
+	for i, line := range strings.Split(strings.TrimSpace(golden.String()), "\n") {
+		if i >= len(wantLines) {
+			t.Errorf("%s missing from %s; run go test -update", line, path)
+			continue
+		}
+		got, recorded := strings.Fields(line), strings.Fields(wantLines[i])
+		switch {
+		case len(recorded) != 3 || got[0] != recorded[0]:
+			t.Errorf("%s line %d = %q, want a line for %s; run go test -update", path, i+1, wantLines[i], got[0])
+		case got[1] == recorded[1] && got[2] != recorded[2]:
+			t.Errorf("%s schema changed without a version bump (still %s); bump its format version", got[0], got[1])
+		case got[1] != recorded[1] || got[2] != recorded[2]:
+			t.Errorf("%s schema is version %s, %s records %s; run go test -update", got[0], got[1], path, recorded[1])
+		}
+	}
+}
+
+// TestValidateExport tests validation of hand-written exports.
+func TestValidateExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   string // substring of the error, empty for valid exports
+	}{
+		{"json", "json", `{"version": "1.1", "generated": "x", "documents": []}`, ""},
+		{"json wrong version", "json", `{"version": "0.9", "generated": "x", "documents": []}`, "$.version: expected 1.1"},
+		{"json unknown field", "json", `{"version": "1.1", "generated": "x", "documents": [], "extra": 1}`, "$.extra: unexpected property"},
+		{"yaml", "yaml", "version: \"1.1\"\ngenerated: x\ndocuments: []\n", ""},
+		{"yaml missing documents", "yaml", "version: \"1.1\"\ngenerated: x\n", `missing required property "documents"`},
+		{"jsonl", "jsonl", `{"doc_id":"d","chunk_id":"c","text":"t","token_count":1,"source":"a.md","start_pos":0,"end_pos":1}` + "\n\n", ""},
+		{"jsonl bad line", "jsonl", `{"doc_id":"d","chunk_id":"c","text":"t","token_count":1,"source":"a.md","start_pos":0,"end_pos":1}` + "\n" + `{"doc_id":"d"}`, `line 2: $: missing required property "chunk_id"`},
+		{"jsonl invalid json", "jsonl", `{`, "line 1: invalid JSON"},
+		{"llm missing index", "llm", `{"version": "1.0", "generated": "x", "documents": []}`, `missing required property "index"`},
+		{"unknown format", "markdown", `# Doc`, "no schema for format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExport(tt.format, []byte(tt.data))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateExport() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateExport() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestDetectFormat tests format detection from file names and content.
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want string
+	}{
+		{"jsonl", "docs.jsonl", `{}`, "jsonl"},
+		{"gzipped jsonl", "docs.jsonl.gz", `{}`, "jsonl"},
+		{"yaml", "docs.yml", `version: "1.0"`, "yaml"},
+		{"llm", "docs.json", `{"version": "1.0", "index": {}}`, "llm"},
+		{"json", "docs.json", `{"version": "1.0", "documents": []}`, "json"},
+		{"unknown extension", "export.txt", `{"documents": []}`, "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.file, []byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat(%q) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
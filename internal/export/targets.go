@@ -0,0 +1,402 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Target converts exported chunks into the native record layout of a vector
+// database or retrieval framework, so JSONL output can be loaded without a
+// conversion script.
+type Target interface {
+	// Name returns the target name accepted by NewTarget.
+	Name() string
+
+	// Record returns the JSON-serializable record for a chunk. frontmatter is
+	// the YAML frontmatter of the chunk's document and may be nil.
+	Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{}
+}
+
+// DefaultWeaviateClass is the class of objects produced by the weaviate target.
+const DefaultWeaviateClass = "JotChunk"
+
+// chunkNamespace is the UUID namespace for chunk IDs, for stores that only
+// accept UUIDs as point identifiers.
+var chunkNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/onedusk/jot/chunk"))
+
+// ChunkUUID returns a stable UUID derived from a chunk ID. Qdrant and Weaviate
+// require UUID identifiers; the original ID is kept in the payload.
+func ChunkUUID(chunkID string) string {
+	return uuid.NewSHA1(chunkNamespace, []byte(chunkID)).String()
+}
+
+// NewTarget creates a Target based on the given name.
+// Supported targets:
+//   - "jot": Jot's own ChunkMetadata layout (the default)
+//   - "pinecone": Pinecone upsert vectors (id, values, metadata)
+//   - "qdrant": Qdrant points (id, vector, payload)
+//   - "weaviate": Weaviate batch objects (class, id, properties, vector)
+//   - "chroma": Chroma records (id, document, embedding, metadata)
+//   - "langchain": LangChain documents (page_content, metadata)
+//   - "llamaindex": LlamaIndex text nodes (id_, text, metadata, relationships)
+//
+// Returns an error if the target name is not recognized.
+func NewTarget(name string) (Target, error) {
+	switch name {
+	case "", "jot":
+		return jotTarget{}, nil
+	case "pinecone":
+		return pineconeTarget{}, nil
+	case "qdrant":
+		return qdrantTarget{}, nil
+	case "weaviate":
+		return NewWeaviateTarget(DefaultWeaviateClass), nil
+	case "chroma":
+		return chromaTarget{}, nil
+	case "langchain":
+		return langchainTarget{}, nil
+	case "llamaindex":
+		return llamaindexTarget{}, nil
+	default:
+		return nil, fmt.Errorf("unknown export target: %s (supported: %s)", name, strings.Join(AvailableTargets(), ", "))
+	}
+}
+
+// AvailableTargets returns a list of all available export target names.
+func AvailableTargets() []string {
+	return []string{"jot", "pinecone", "qdrant", "weaviate", "chroma", "langchain", "llamaindex"}
+}
+
+// DefaultTarget returns the default export target name.
+func DefaultTarget() string {
+	return "jot"
+}
+
+// jotTarget emits ChunkMetadata unchanged.
+type jotTarget struct{}
+
+func (jotTarget) Name() string { return "jot" }
+
+func (jotTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	return chunk
+}
+
+// PineconeRecord is a vector in the layout of Pinecone's upsert API.
+type PineconeRecord struct {
+	ID       string                 `json:"id"`
+	Values   []float32              `json:"values"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// pineconeTarget emits PineconeRecords. Pinecone metadata only holds strings,
+// numbers, booleans and lists of strings, so frontmatter is converted
+// accordingly; the chunk text is stored under "text".
+type pineconeTarget struct{}
+
+func (pineconeTarget) Name() string { return "pinecone" }
+
+func (pineconeTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	values := chunk.Vector
+	if values == nil {
+		values = []float32{}
+	}
+	return PineconeRecord{
+		ID:       chunk.ChunkID,
+		Values:   values,
+		Metadata: chunkPayload(chunk, frontmatter, true, false),
+	}
+}
+
+// QdrantPoint is a point in the layout of Qdrant's upsert points API.
+type QdrantPoint struct {
+	ID      string                 `json:"id"`
+	Vector  []float32              `json:"vector,omitempty"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// qdrantTarget emits QdrantPoints identified by ChunkUUID.
+type qdrantTarget struct{}
+
+func (qdrantTarget) Name() string { return "qdrant" }
+
+func (qdrantTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	return QdrantPoint{
+		ID:      ChunkUUID(chunk.ChunkID),
+		Vector:  chunk.Vector,
+		Payload: chunkPayload(chunk, frontmatter, true, false),
+	}
+}
+
+// WeaviateObject is an object in the layout of Weaviate's batch objects API.
+type WeaviateObject struct {
+	Class      string                 `json:"class"`
+	ID         string                 `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	Vector     []float32              `json:"vector,omitempty"`
+}
+
+// weaviateTarget emits WeaviateObjects identified by ChunkUUID. Property
+// names are restricted to GraphQL names, so frontmatter keys are sanitized.
+type weaviateTarget struct {
+	class string
+}
+
+// NewWeaviateTarget creates a weaviate target producing objects of the given class.
+func NewWeaviateTarget(class string) Target {
+	return weaviateTarget{class: class}
+}
+
+func (weaviateTarget) Name() string { return "weaviate" }
+
+func (w weaviateTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	properties := chunkPayload(chunk, frontmatter, true, false)
+	for _, key := range sortedKeys(properties) {
+		if name := graphQLName(key); name != key {
+			value := properties[key]
+			delete(properties, key)
+			if _, exists := properties[name]; !exists {
+				properties[name] = value
+			}
+		}
+	}
+	return WeaviateObject{
+		Class:      w.class,
+		ID:         ChunkUUID(chunk.ChunkID),
+		Properties: properties,
+		Vector:     chunk.Vector,
+	}
+}
+
+// ChromaRecord is a record in the layout of Chroma's add and upsert APIs,
+// with one element of each parallel array per line.
+type ChromaRecord struct {
+	ID        string                 `json:"id"`
+	Document  string                 `json:"document"`
+	Embedding []float32              `json:"embedding,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// chromaTarget emits ChromaRecords. Chroma metadata values must be scalars,
+// so lists such as the heading path are joined into strings.
+type chromaTarget struct{}
+
+func (chromaTarget) Name() string { return "chroma" }
+
+func (chromaTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	return ChromaRecord{
+		ID:        chunk.ChunkID,
+		Document:  chunk.Text,
+		Embedding: chunk.Vector,
+		Metadata:  chunkPayload(chunk, frontmatter, false, true),
+	}
+}
+
+// LangChainDocument is a document in the layout of LangChain's Document.
+type LangChainDocument struct {
+	ID          string                 `json:"id"`
+	PageContent string                 `json:"page_content"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	Type        string                 `json:"type"`
+}
+
+// langchainTarget emits LangChainDocuments.
+type langchainTarget struct{}
+
+func (langchainTarget) Name() string { return "langchain" }
+
+func (langchainTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	return LangChainDocument{
+		ID:          chunk.ChunkID,
+		PageContent: chunk.Text,
+		Metadata:    chunkPayload(chunk, frontmatter, false, false),
+		Type:        "Document",
+	}
+}
+
+// LlamaIndexNode is a node in the layout of LlamaIndex's serialized TextNode.
+type LlamaIndexNode struct {
+	ID            string                        `json:"id_"`
+	Text          string                        `json:"text"`
+	Embedding     []float32                     `json:"embedding"`
+	Metadata      map[string]interface{}        `json:"metadata"`
+	Relationships map[string]LlamaIndexRelation `json:"relationships"`
+	StartCharIdx  int                           `json:"start_char_idx"`
+	EndCharIdx    int                           `json:"end_char_idx"`
+	ClassName     string                        `json:"class_name"`
+}
+
+// LlamaIndexRelation references a related node, like LlamaIndex's RelatedNodeInfo.
+type LlamaIndexRelation struct {
+	NodeID    string `json:"node_id"`
+	NodeType  string `json:"node_type"`
+	ClassName string `json:"class_name"`
+}
+
+// LlamaIndex NodeRelationship and ObjectType values.
+const (
+	llamaSource   = "1"
+	llamaPrevious = "2"
+	llamaNext     = "3"
+
+	llamaTextNode = "1"
+	llamaDocument = "4"
+)
+
+// llamaindexTarget emits LlamaIndexNodes linked to their document and to the
+// neighboring chunks.
+type llamaindexTarget struct{}
+
+func (llamaindexTarget) Name() string { return "llamaindex" }
+
+func (llamaindexTarget) Record(chunk ChunkMetadata, frontmatter map[string]interface{}) interface{} {
+	relation := func(id, nodeType string) LlamaIndexRelation {
+		return LlamaIndexRelation{NodeID: id, NodeType: nodeType, ClassName: "RelatedNodeInfo"}
+	}
+
+	relationships := map[string]LlamaIndexRelation{
+		llamaSource: relation(chunk.DocID, llamaDocument),
+	}
+	if chunk.PrevChunkID != "" {
+		relationships[llamaPrevious] = relation(chunk.PrevChunkID, llamaTextNode)
+	}
+	if chunk.NextChunkID != "" {
+		relationships[llamaNext] = relation(chunk.NextChunkID, llamaTextNode)
+	}
+
+	return LlamaIndexNode{
+		ID:            chunk.ChunkID,
+		Text:          chunk.Text,
+		Embedding:     chunk.Vector,
+		Metadata:      chunkPayload(chunk, frontmatter, false, false),
+		Relationships: relationships,
+		StartCharIdx:  chunk.StartPos,
+		EndCharIdx:    chunk.EndPos,
+		ClassName:     "TextNode",
+	}
+}
+
+// chunkPayload merges a document's frontmatter with the chunk's fields into a
+// metadata map. Chunk fields take precedence over frontmatter keys of the same
+// name. withText includes the chunk text under "text"; flat joins lists into
+// strings for stores that only accept scalar values.
+func chunkPayload(chunk ChunkMetadata, frontmatter map[string]interface{}, withText, flat bool) map[string]interface{} {
+	payload := make(map[string]interface{}, len(frontmatter)+12)
+	for key, value := range frontmatter {
+		if converted, ok := metadataValue(value, flat); ok {
+			payload[key] = converted
+		}
+	}
+
+	payload["doc_id"] = chunk.DocID
+	payload["chunk_id"] = chunk.ChunkID
+	payload["source"] = chunk.Source
+	payload["token_count"] = chunk.TokenCount
+	payload["start_pos"] = chunk.StartPos
+	payload["end_pos"] = chunk.EndPos
+	if withText {
+		payload["text"] = chunk.Text
+	}
+	if chunk.PrevChunkID != "" {
+		payload["prev_chunk_id"] = chunk.PrevChunkID
+	}
+	if chunk.NextChunkID != "" {
+		payload["next_chunk_id"] = chunk.NextChunkID
+	}
+	if len(chunk.HeadingPath) > 0 {
+		if flat {
+			payload["heading_path"] = strings.Join(chunk.HeadingPath, " > ")
+		} else {
+			payload["heading_path"] = chunk.HeadingPath
+		}
+	}
+	if chunk.Context != "" {
+		payload["context"] = chunk.Context
+	}
+	if chunk.Tokenizer != "" {
+		payload["tokenizer"] = chunk.Tokenizer
+	}
+	if chunk.CanonicalChunkID != "" {
+		payload["canonical_chunk_id"] = chunk.CanonicalChunkID
+	}
+	if chunk.LastCommit != "" {
+		payload["last_updated"] = chunk.LastUpdated
+		payload["last_commit"] = chunk.LastCommit
+		if flat {
+			payload["authors"] = strings.Join(chunk.Authors, ", ")
+		} else {
+			payload["authors"] = chunk.Authors
+		}
+	}
+
+	return payload
+}
+
+// metadataValue converts a frontmatter value to a metadata value that vector
+// stores accept: strings, numbers, booleans and lists of strings (or, when
+// flat, a comma-separated string). Other values are JSON-encoded. It returns
+// false for values that should be dropped.
+func metadataValue(value interface{}, flat bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string, bool, int, int64, float64:
+		return v, true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := metadataValue(item, true); ok {
+				items = append(items, fmt.Sprint(s))
+			}
+		}
+		if flat {
+			return strings.Join(items, ", "), true
+		}
+		return items, true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v), true
+		}
+		return string(data), true
+	}
+}
+
+// graphQLName converts key into a valid GraphQL name (/[_A-Za-z][_0-9A-Za-z]*/)
+// by replacing other characters with underscores.
+func graphQLName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onedusk/jot/internal/chunking"
+	"github.com/onedusk/jot/internal/embedding"
+	"github.com/onedusk/jot/internal/jsonschema"
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/tokenizer"
+)
+
+// TestTargets tests that every target emits records matching its system's
+// native layout, described by a JSON Schema in testdata/targets (or the
+// published jsonl schema for jot).
+func TestTargets(t *testing.T) {
+	docs := []scanner.Document{
+		{
+			ID:           "doc1",
+			Title:        "Guide",
+			RelativePath: "guide.md",
+			Content:      []byte("# Guide\n\nIntro paragraph.\n\n## Install\n\nRun the installer.\n\n## Usage\n\nRun jot build."),
+			ModTime:      time.Now(),
+			Metadata: map[string]interface{}{
+				"tags":          []interface{}{"setup", "cli"},
+				"last-reviewed": time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+				"draft":         false,
+				"weight":        3,
+				"author":        map[string]interface{}{"name": "Jo"},
+			},
+		},
+	}
+
+	for _, name := range AvailableTargets() {
+		t.Run(name, func(t *testing.T) {
+			// The jot layout is the published jsonl schema
+			data, err := Schema("jsonl")
+			if name != "jot" {
+				data, err = os.ReadFile(filepath.Join("testdata", "targets", name+".schema.json"))
+			}
+			if err != nil {
+				t.Fatalf("failed to read schema: %v", err)
+			}
+			schema, err := jsonschema.Parse(data)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			target, err := NewTarget(name)
+			if err != nil {
+				t.Fatalf("NewTarget(%q) error = %v", name, err)
+			}
+
+			exporter := NewJSONLExporter()
+			exporter.SetStrategy(chunking.NewMarkdownStrategy(tokenizer.NewHeuristicTokenizer()))
+			exporter.SetEmbedder(embedding.NewHashEmbedder(8))
+			exporter.SetTarget(target)
+
+			output, err := exporter.ToJSONL(docs, 512, 0)
+			if err != nil {
+				t.Fatalf("ToJSONL() error = %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("ToJSONL() produced %d lines, want 3", len(lines))
+			}
+			for i, line := range lines {
+				if err := schema.ValidateJSON([]byte(line)); err != nil {
+					t.Errorf("line %d does not match the %s schema: %v\n%s", i, name, err, line)
+				}
+			}
+		})
+	}
+}
+
+// TestTargetRecords tests target-specific conversions of chunk fields.
+func TestTargetRecords(t *testing.T) {
+	chunk := ChunkMetadata{
+		DocID:       "doc1",
+		ChunkID:     "doc1-chunk-1",
+		Text:        "Run the installer.",
+		Source:      "guide.md",
+		PrevChunkID: "doc1-chunk-0",
+		HeadingPath: []string{"Guide", "Install"},
+	}
+	frontmatter := map[string]interface{}{
+		"tags":     []interface{}{"setup", "cli"},
+		"2nd-key":  "value",
+		"chunk_id": "overridden",
+	}
+
+	record := func(name string) map[string]interface{} {
+		target, err := NewTarget(name)
+		if err != nil {
+			t.Fatalf("NewTarget(%q) error = %v", name, err)
+		}
+		data, err := json.Marshal(target.Record(chunk, frontmatter))
+		if err != nil {
+			t.Fatalf("failed to marshal %s record: %v", name, err)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("failed to unmarshal %s record: %v", name, err)
+		}
+		return decoded
+	}
+
+	qdrant := record("qdrant")
+	if qdrant["id"] != ChunkUUID(chunk.ChunkID) || qdrant["id"] == ChunkUUID("doc1-chunk-0") {
+		t.Errorf("qdrant id = %v, want stable UUID of the chunk ID", qdrant["id"])
+	}
+	if payload := qdrant["payload"].(map[string]interface{}); payload["chunk_id"] != chunk.ChunkID {
+		t.Errorf("qdrant payload chunk_id = %v, want chunk field to override frontmatter", payload["chunk_id"])
+	}
+
+	chroma := record("chroma")["metadata"].(map[string]interface{})
+	if chroma["heading_path"] != "Guide > Install" || chroma["tags"] != "setup, cli" {
+		t.Errorf("chroma metadata = %v, want lists joined into strings", chroma)
+	}
+
+	weaviate := record("weaviate")["properties"].(map[string]interface{})
+	if weaviate["_2nd_key"] != "value" {
+		t.Errorf("weaviate properties = %v, want key sanitized to _2nd_key", weaviate)
+	}
+
+	llama := record("llamaindex")["relationships"].(map[string]interface{})
+	if _, ok := llama["3"]; ok {
+		t.Errorf("llamaindex relationships = %v, want no next relation for the last chunk", llama)
+	}
+	if prev := llama["2"].(map[string]interface{}); prev["node_id"] != "doc1-chunk-0" {
+		t.Errorf("llamaindex previous relation = %v, want doc1-chunk-0", prev)
+	}
+
+	if _, err := NewTarget("unknown"); err == nil {
+		t.Error("NewTarget() expected error for unknown target")
+	}
+}
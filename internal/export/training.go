@@ -0,0 +1,371 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/onedusk/jot/internal/chunk"
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+// DefaultValidationSplit is the default fraction of documents whose training
+// pairs go into the validation set.
+const DefaultValidationSplit = 0.1
+
+// minAnswerWords is the number of words below which a synthesized answer is
+// considered too thin to train on.
+const minAnswerWords = 3
+
+// Kinds of training pairs, by the document structure they are derived from.
+const (
+	PairSection    = "section"    // Heading as question, section body as answer
+	PairCode       = "code"       // Explanation as question, following code block as answer
+	PairDefinition = "definition" // Definition list term as question, definition as answer
+)
+
+// trainingHeadingRegex matches ATX headings, capturing the title.
+var trainingHeadingRegex = regexp.MustCompile(`^#{1,6}\s+(.+?)(?:\s+#+)?\s*$`)
+
+// trainingFenceRegex matches the opening line of a fenced code block,
+// capturing the fence and the language.
+var trainingFenceRegex = regexp.MustCompile("^(```+|~~~+)\\s*([^\\s`]*)")
+
+// TrainingPair is a question and answer synthesized from document structure.
+type TrainingPair struct {
+	Kind     string // PairSection, PairCode or PairDefinition
+	Question string
+	Answer   string
+}
+
+// TrainingMessage is one message of a chat-format training record.
+type TrainingMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// TrainingRecord is one line of a chat-format fine-tuning dataset, the layout
+// accepted by OpenAI-compatible fine-tuning APIs.
+type TrainingRecord struct {
+	Messages []TrainingMessage `json:"messages"`
+}
+
+// TrainingStats summarizes a training export.
+type TrainingStats struct {
+	TrainDocuments      int // Documents whose pairs went into the training set
+	ValidationDocuments int // Documents whose pairs went into the validation set
+	TrainRecords        int
+	ValidationRecords   int
+	Duplicates          int // Pairs dropped because the same answer was already written
+}
+
+// TrainingExporter turns documents into instruction-style fine-tuning records
+// derived from their structure, split into training and validation sets by
+// document so that no document contributes to both.
+type TrainingExporter struct {
+	systemPrompt    string  // Optional; when set, every record starts with a system message
+	validationSplit float64 // Fraction of documents assigned to the validation set
+}
+
+// NewTrainingExporter creates a TrainingExporter with the default
+// validation split and no system prompt.
+func NewTrainingExporter() *TrainingExporter {
+	return &TrainingExporter{validationSplit: DefaultValidationSplit}
+}
+
+// SetSystemPrompt configures the system message that starts every record.
+// Passing "" omits the system message.
+func (e *TrainingExporter) SetSystemPrompt(prompt string) {
+	e.systemPrompt = prompt
+}
+
+// SetValidationSplit configures the fraction of documents (0 to 1) assigned
+// to the validation set.
+func (e *TrainingExporter) SetValidationSplit(fraction float64) {
+	e.validationSplit = fraction
+}
+
+// IsValidation reports whether doc belongs to the validation set. The
+// assignment depends only on the document's path, so a document stays in
+// the same set when other documents are added or removed.
+func (e *TrainingExporter) IsValidation(doc scanner.Document) bool {
+	if e.validationSplit <= 0 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(filepath.ToSlash(doc.RelativePath)))
+	position := float64(binary.BigEndian.Uint64(sum[:8])) / (1 << 64)
+	return position < e.validationSplit
+}
+
+// WriteTraining writes the training pairs of documents as chat-format JSONL:
+// pairs of validation documents (see IsValidation) go to validation and all
+// others to train. When validation is nil every pair goes to train.
+//
+// Pairs are deduplicated across both sets by their answer, after normalizing
+// whitespace, since boilerplate repeated across documents yields the same
+// answer to differently phrased questions. Training documents are written
+// first, so an answer shared with a validation document is kept in the
+// training set and dropped from validation.
+func (e *TrainingExporter) WriteTraining(train, validation io.Writer, documents []scanner.Document) (TrainingStats, error) {
+	var stats TrainingStats
+	var validationDocs []scanner.Document
+	seen := make(map[string]bool)
+
+	for _, doc := range documents {
+		if validation != nil && e.IsValidation(doc) {
+			validationDocs = append(validationDocs, doc)
+			continue
+		}
+		written, err := e.writePairs(train, doc, seen, &stats)
+		if err != nil {
+			return stats, err
+		}
+		stats.TrainDocuments++
+		stats.TrainRecords += written
+	}
+
+	for _, doc := range validationDocs {
+		written, err := e.writePairs(validation, doc, seen, &stats)
+		if err != nil {
+			return stats, err
+		}
+		stats.ValidationDocuments++
+		stats.ValidationRecords += written
+	}
+
+	return stats, nil
+}
+
+// writePairs writes the pairs of doc that were not seen before and returns
+// how many were written.
+func (e *TrainingExporter) writePairs(w io.Writer, doc scanner.Document, seen map[string]bool, stats *TrainingStats) (int, error) {
+	written := 0
+	for _, pair := range ExtractTrainingPairs(doc) {
+		key := chunk.Normalize(pair.Answer)
+		if seen[key] {
+			stats.Duplicates++
+			continue
+		}
+		seen[key] = true
+
+		jsonBytes, err := json.Marshal(e.record(pair))
+		if err != nil {
+			return written, fmt.Errorf("failed to marshal training record for %s: %w", doc.RelativePath, err)
+		}
+		if _, err := w.Write(append(jsonBytes, '\n')); err != nil {
+			return written, fmt.Errorf("failed to write training record for %s: %w", doc.RelativePath, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// record converts a pair into a chat-format record.
+func (e *TrainingExporter) record(pair TrainingPair) TrainingRecord {
+	messages := make([]TrainingMessage, 0, 3)
+	if e.systemPrompt != "" {
+		messages = append(messages, TrainingMessage{Role: "system", Content: e.systemPrompt})
+	}
+	messages = append(messages,
+		TrainingMessage{Role: "user", Content: pair.Question},
+		TrainingMessage{Role: "assistant", Content: pair.Answer},
+	)
+	return TrainingRecord{Messages: messages}
+}
+
+// trainingBlock is a heading, paragraph or fenced code block of a document.
+type trainingBlock struct {
+	kind string // "heading", "paragraph" or "code"
+	text string // Heading title, paragraph text, or the whole fenced block
+	lang string // Code block language
+}
+
+// ExtractTrainingPairs synthesizes question and answer pairs from the
+// structure of doc, in document order:
+//   - every heading with a body becomes a question answered by the text up to the next heading
+//   - every code block preceded by a paragraph pairs that explanation with the code
+//   - every definition list entry ("Term" followed by ": definition") pairs the term with its definition
+//
+// Answers with fewer than three words are skipped.
+func ExtractTrainingPairs(doc scanner.Document) []TrainingPair {
+	blocks := splitTrainingBlocks(string(doc.Content))
+	var pairs []TrainingPair
+	add := func(kind, question, answer string) {
+		if len(strings.Fields(answer)) >= minAnswerWords {
+			pairs = append(pairs, TrainingPair{Kind: kind, Question: question, Answer: answer})
+		}
+	}
+
+	for i, block := range blocks {
+		switch block.kind {
+		case "heading":
+			var body []string
+			for _, next := range blocks[i+1:] {
+				if next.kind == "heading" {
+					break
+				}
+				body = append(body, next.text)
+			}
+			if len(body) > 0 {
+				add(PairSection, sectionQuestion(doc.Title, block.text), strings.Join(body, "\n\n"))
+			}
+
+		case "code":
+			if i > 0 && blocks[i-1].kind == "paragraph" && !isDefinitionList(blocks[i-1].text) {
+				add(PairCode, codeQuestion(doc.Title, blocks[i-1].text, block.lang), block.text)
+			}
+
+		case "paragraph":
+			previous := ""
+			if i > 0 && blocks[i-1].kind == "paragraph" {
+				previous = blocks[i-1].text
+			}
+			for _, definition := range parseDefinitions(previous, block.text) {
+				add(PairDefinition, fmt.Sprintf("What does \"%s\" mean in the %s documentation?", definition[0], doc.Title), definition[1])
+			}
+		}
+	}
+
+	return pairs
+}
+
+// splitTrainingBlocks splits markdown content into headings, paragraphs and
+// fenced code blocks. Lines inside code fences are never headings.
+func splitTrainingBlocks(content string) []trainingBlock {
+	var blocks []trainingBlock
+	var paragraph, code []string
+	fence, lang := "", ""
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, trainingBlock{kind: "paragraph", text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			code = append(code, line)
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				blocks = append(blocks, trainingBlock{kind: "code", text: strings.Join(code, "\n"), lang: lang})
+				code, fence = nil, ""
+			}
+			continue
+		}
+
+		if matches := trainingFenceRegex.FindStringSubmatch(trimmed); matches != nil {
+			flush()
+			fence, lang = matches[1], matches[2]
+			code = []string{line}
+			continue
+		}
+
+		if matches := trainingHeadingRegex.FindStringSubmatch(trimmed); matches != nil {
+			flush()
+			blocks = append(blocks, trainingBlock{kind: "heading", text: matches[1]})
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+
+	// An unterminated fence runs to the end of the document
+	if fence != "" {
+		blocks = append(blocks, trainingBlock{kind: "code", text: strings.Join(code, "\n"), lang: lang})
+	}
+	flush()
+
+	return blocks
+}
+
+// sectionQuestion phrases a heading as a question about the document.
+func sectionQuestion(docTitle, heading string) string {
+	if strings.HasSuffix(heading, "?") {
+		return heading
+	}
+
+	switch strings.ToLower(strings.Fields(heading)[0]) {
+	case "how", "what", "why", "when", "where", "which", "who", "can", "should", "is", "are", "do", "does":
+		return heading + "?"
+	}
+
+	if strings.EqualFold(heading, docTitle) {
+		return fmt.Sprintf("Give an overview of %s.", heading)
+	}
+	return fmt.Sprintf("What does the %s documentation say about %s?", docTitle, heading)
+}
+
+// codeQuestion phrases the explanation preceding a code block as a request
+// for that code.
+func codeQuestion(docTitle, explanation, lang string) string {
+	explanation = strings.TrimRight(explanation, ": ")
+	if !strings.HasSuffix(explanation, ".") && !strings.HasSuffix(explanation, "?") && !strings.HasSuffix(explanation, "!") {
+		explanation += "."
+	}
+
+	code := "code"
+	if lang != "" {
+		code = lang + " code"
+	}
+	return fmt.Sprintf("%s\n\nShow the %s for this from the %s documentation.", explanation, code, docTitle)
+}
+
+// isDefinitionList reports whether a paragraph contains definition list
+// entries.
+func isDefinitionList(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, ": ") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDefinitions returns the [term, definition] entries of a definition
+// list paragraph: term lines each followed by one or more ": definition"
+// lines. When the paragraph starts with a definition, the term is the
+// previous paragraph if that is a single line, as in
+// "Term\n\n: definition".
+func parseDefinitions(previous, text string) [][2]string {
+	lines := strings.Split(text, "\n")
+	if !isDefinitionList(text) {
+		return nil
+	}
+
+	term := ""
+	if strings.HasPrefix(lines[0], ": ") && previous != "" && !strings.Contains(previous, "\n") {
+		term = previous
+	}
+
+	var entries [][2]string
+	var definitions []string
+	emit := func() {
+		if term != "" && len(definitions) > 0 {
+			entries = append(entries, [2]string{term, strings.Join(definitions, "\n")})
+		}
+		definitions = nil
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, ": ") {
+			definitions = append(definitions, strings.TrimSpace(strings.TrimPrefix(line, ": ")))
+			continue
+		}
+		emit()
+		term = line
+	}
+	emit()
+
+	return entries
+}
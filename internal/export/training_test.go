@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+// TestExtractTrainingPairs tests pairs synthesized from headings, code blocks
+// and definition lists.
+func TestExtractTrainingPairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []TrainingPair
+	}{
+		{
+			name:    "sections",
+			content: "# Install\n\nDownload the latest release.\n\n## How do I upgrade\n\nRun the installer again.\n\n## Verify\n\nOk.",
+			want: []TrainingPair{
+				{PairSection, "Give an overview of Install.", "Download the latest release."},
+				{PairSection, "How do I upgrade?", "Run the installer again."},
+			},
+		},
+		{
+			name:    "code with explanation",
+			content: "# Install\n\nCheck the installed version:\n\n```sh\n# prints the version\njot --version\n```",
+			want: []TrainingPair{
+				{PairSection, "Give an overview of Install.", "Check the installed version:\n\n```sh\n# prints the version\njot --version\n```"},
+				{PairCode, "Check the installed version.\n\nShow the sh code for this from the Install documentation.", "```sh\n# prints the version\njot --version\n```"},
+			},
+		},
+		{
+			name:    "definition lists",
+			content: "Chunk\n: A piece of a document sized for embedding.\n\nToken budget\n\n: The maximum number of tokens allowed.",
+			want: []TrainingPair{
+				{PairDefinition, `What does "Chunk" mean in the Install documentation?`, "A piece of a document sized for embedding."},
+				{PairDefinition, `What does "Token budget" mean in the Install documentation?`, "The maximum number of tokens allowed."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := scanner.Document{Title: "Install", RelativePath: "install.md", Content: []byte(tt.content)}
+			if got := ExtractTrainingPairs(doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTrainingPairs() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriteTraining tests the document split, chat-message records and
+// deduplication across sets.
+func TestWriteTraining(t *testing.T) {
+	exporter := NewTrainingExporter()
+	exporter.SetValidationSplit(0.5)
+	exporter.SetSystemPrompt("You answer questions about Jot.")
+
+	// Find one document path in each set
+	var trainPath, validationPath string
+	for i := 0; trainPath == "" || validationPath == ""; i++ {
+		path := fmt.Sprintf("doc%d.md", i)
+		if exporter.IsValidation(scanner.Document{RelativePath: path}) {
+			validationPath = path
+		} else {
+			trainPath = path
+		}
+	}
+
+	shared := "## Support\n\nAsk in the community forum."
+	docs := []scanner.Document{
+		{Title: "Validation", RelativePath: validationPath, Content: []byte("# Validation\n\nOnly in the validation set.\n\n" + shared)},
+		{Title: "Train", RelativePath: trainPath, Content: []byte("# Train\n\nOnly in the training set.\n\n" + shared)},
+	}
+
+	var train, validation strings.Builder
+	stats, err := exporter.WriteTraining(&train, &validation, docs)
+	if err != nil {
+		t.Fatalf("WriteTraining() error = %v", err)
+	}
+
+	want := TrainingStats{TrainDocuments: 1, ValidationDocuments: 1, TrainRecords: 2, ValidationRecords: 1, Duplicates: 1}
+	if stats != want {
+		t.Errorf("WriteTraining() stats = %+v, want %+v", stats, want)
+	}
+
+	var records []TrainingRecord
+	for _, line := range strings.Split(strings.TrimSpace(train.String()), "\n") {
+		var record TrainingRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid training record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 || len(records[0].Messages) != 3 || records[0].Messages[0].Role != "system" ||
+		records[0].Messages[1].Role != "user" || records[0].Messages[2].Content != "Only in the training set." {
+		t.Errorf("training records = %+v", records)
+	}
+	if !strings.Contains(validation.String(), "Only in the validation set.") || strings.Contains(validation.String(), "community forum") {
+		t.Errorf("validation set = %s, want its own pair without the shared one", validation.String())
+	}
+
+	// Without a validation writer everything is training data
+	train.Reset()
+	stats, err = exporter.WriteTraining(&train, nil, docs)
+	if err != nil {
+		t.Fatalf("WriteTraining() error = %v", err)
+	}
+	if stats.TrainDocuments != 2 || stats.TrainRecords != 3 || stats.ValidationRecords != 0 {
+		t.Errorf("WriteTraining() without validation stats = %+v", stats)
+	}
+}
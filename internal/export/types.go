@@ -2,10 +2,53 @@
 // and a special format optimized for Large Language Models (LLMs).
 package export
 
+import "github.com/onedusk/jot/internal/chunk"
+
 // ProjectConfig contains metadata for llms.txt header generation.
 type ProjectConfig struct {
 	Name        string `yaml:"name" json:"name"`
 	Description string `yaml:"description" json:"description"`
+
+	// BaseURL, when set, makes llms.txt links absolute (e.g. the published
+	// site, or a mirror of the markdown sources).
+	BaseURL string `yaml:"base_url" json:"base_url,omitempty"`
+	// LinkExtension replaces the .md extension of linked paths, e.g. ".html"
+	// for rendered pages. Empty keeps the source path.
+	LinkExtension string `yaml:"link_extension" json:"link_extension,omitempty"`
+	// Optional lists path patterns (same syntax as input.ignore) of documents that
+	// go into the llms.txt "Optional" section.
+	Optional []string `yaml:"optional" json:"optional,omitempty"`
+}
+
+// DocumentExport is the structure of the json and yaml export formats,
+// described by the "json" schema (see Schema).
+type DocumentExport struct {
+	Version   string           `json:"version" yaml:"version"`
+	Generated string           `json:"generated" yaml:"generated"`
+	Documents []ExportDocument `json:"documents" yaml:"documents"`
+}
+
+// ExportDocument is a single document of a DocumentExport. Sections, code
+// blocks and links are omitted when the document has none.
+type ExportDocument struct {
+	ID         string                 `json:"id" yaml:"id"`
+	Path       string                 `json:"path" yaml:"path"`
+	Title      string                 `json:"title" yaml:"title"`
+	Content    string                 `json:"content" yaml:"content"`
+	Metadata   map[string]interface{} `json:"metadata" yaml:"metadata"`
+	Modified   string                 `json:"modified" yaml:"modified"`
+	Sections   []LLMSection           `json:"sections,omitempty" yaml:"sections,omitempty"`
+	CodeBlocks []LLMCodeBlock         `json:"code_blocks,omitempty" yaml:"code_blocks,omitempty"`
+	Links      *Links                 `json:"links,omitempty" yaml:"links,omitempty"`
+	Git        *GitMetadata           `json:"git,omitempty" yaml:"git,omitempty"` // Set when git history is read
+}
+
+// GitMetadata is the git history of an exported document's file.
+type GitMetadata struct {
+	Commit  string   `json:"commit" yaml:"commit"`   // Last commit that changed the file
+	Updated string   `json:"updated" yaml:"updated"` // Committer date of that commit (RFC 3339)
+	Authors []string `json:"authors" yaml:"authors"` // Most commits first
+	Path    string   `json:"path" yaml:"path"`       // Path of the file in the repository
 }
 
 // LLMExport represents the complete data structure for an export optimized
@@ -13,6 +56,7 @@ type ProjectConfig struct {
 type LLMExport struct {
 	Version   string         `json:"version" yaml:"version"`
 	Generated string         `json:"generated" yaml:"generated"`
+	Tokenizer string         `json:"tokenizer,omitempty" yaml:"tokenizer,omitempty"` // Encoding used for chunk token counts
 	Documents []LLMDocument  `json:"documents" yaml:"documents"`
 	Index     *SemanticIndex `json:"index" yaml:"index"`
 }
@@ -33,16 +77,10 @@ type LLMDocument struct {
 	Metadata   map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
-// Chunk represents a segment of text from a document, typically sized for
-// tasks like vector embedding or processing within a model's context window.
-type Chunk struct {
-	ID         string    `json:"id" yaml:"id"`
-	Text       string    `json:"text" yaml:"text"`
-	StartPos   int       `json:"start_pos" yaml:"start_pos"`
-	EndPos     int       `json:"end_pos" yaml:"end_pos"`
-	TokenCount int       `json:"token_count" yaml:"token_count"`
-	Vector     []float32 `json:"vector,omitempty" yaml:"vector,omitempty"`
-}
+// Chunk represents a segment of text from a document. It is an alias of
+// chunk.Chunk so that chunks produced by the chunking strategies can be
+// exported directly.
+type Chunk = chunk.Chunk
 
 // LLMSection represents a distinct section within a document, such as a
 // chapter or a part introduced by a header.
@@ -80,14 +118,26 @@ type SemanticIndex struct {
 // This structure is used for JSONL export and includes navigation fields for document relationships.
 // Compatible with vector databases like Pinecone, Weaviate, and Qdrant.
 type ChunkMetadata struct {
-	DocID       string    `json:"doc_id"`                // Unique identifier of the parent document
-	ChunkID     string    `json:"chunk_id"`              // Unique identifier for this chunk
-	Text        string    `json:"text"`                  // The actual text content of the chunk
-	TokenCount  int       `json:"token_count"`           // Number of tokens in this chunk
-	Source      string    `json:"source"`                // Source file path (relative)
-	StartPos    int       `json:"start_pos"`             // Starting position in the document
-	EndPos      int       `json:"end_pos"`               // Ending position in the document
+	DocID       string    `json:"doc_id"`                  // Unique identifier of the parent document
+	ChunkID     string    `json:"chunk_id"`                // Unique identifier for this chunk
+	Text        string    `json:"text"`                    // The actual text content of the chunk
+	TokenCount  int       `json:"token_count"`             // Number of tokens in this chunk
+	Source      string    `json:"source"`                  // Source file path (relative)
+	StartPos    int       `json:"start_pos"`               // Starting position in the document
+	EndPos      int       `json:"end_pos"`                 // Ending position in the document
 	PrevChunkID string    `json:"prev_chunk_id,omitempty"` // ID of the previous chunk for navigation
 	NextChunkID string    `json:"next_chunk_id,omitempty"` // ID of the next chunk for navigation
-	Vector      []float32 `json:"vector,omitempty"`      // Optional embedding vector for similarity search
+	Vector      []float32 `json:"vector,omitempty"`        // Optional embedding vector for similarity search
+	HeadingPath []string  `json:"heading_path,omitempty"`  // Enclosing headings, outermost first, when known
+	Context     string    `json:"context,omitempty"`       // Contextual preamble situating the chunk in its document
+	Tokenizer   string    `json:"tokenizer,omitempty"`     // Encoding used to compute token_count
+
+	// CanonicalChunkID is set on near-duplicates kept by DuplicatesMerge and
+	// names the earlier chunk they duplicate.
+	CanonicalChunkID string `json:"canonical_chunk_id,omitempty"`
+
+	// Git history of the source file, when read by the scanner
+	LastUpdated string   `json:"last_updated,omitempty"` // Date of the last commit (RFC 3339)
+	LastCommit  string   `json:"last_commit,omitempty"`  // Hash of the last commit
+	Authors     []string `json:"authors,omitempty"`      // Authors of the file's commits, most commits first
 }
@@ -0,0 +1,200 @@
+// Package i18n splits documentation into locales, such as /en/ and /ja/,
+// either from per-locale input paths or from filename suffixes (page.ja.md
+// next to page.md), and fills untranslated pages from the default locale.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/site"
+)
+
+// LanguagePlaceholder is replaced by the label of the page's locale in
+// untranslated notices.
+const LanguagePlaceholder = "{language}"
+
+// DefaultUntranslated is the notice shown on pages that fall back to the
+// default locale.
+const DefaultUntranslated = "This page has not been translated into " + LanguagePlaceholder + " yet and is shown in the original language."
+
+// codeRegex matches locale codes such as en, ja, pt-BR or zh-Hans.
+var codeRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Locale is one language of the documentation.
+type Locale struct {
+	Code         string   `mapstructure:"code"`         // BCP 47 language tag, used as output directory and <html lang>
+	Label        string   `mapstructure:"label"`        // Shown in the language switcher; defaults to Code
+	Paths        []string `mapstructure:"paths"`        // Input paths of the locale; empty to use page.<code>.md suffixes in the shared input paths
+	Untranslated string   `mapstructure:"untranslated"` // Notice on fallback pages, with {language} for Label; defaults to DefaultUntranslated
+}
+
+// DisplayName returns the label of the locale, or its code when it has none.
+func (l Locale) DisplayName() string {
+	if l.Label != "" {
+		return l.Label
+	}
+	return l.Code
+}
+
+// UntranslatedNotice returns the notice shown on pages of the locale that
+// fall back to the default locale, with LanguagePlaceholder replaced by the
+// label of the locale. The notice is not a format string, so other text such
+// as "50%" is kept as written.
+func (l Locale) UntranslatedNotice() string {
+	notice := l.Untranslated
+	if notice == "" {
+		notice = DefaultUntranslated
+	}
+	return strings.ReplaceAll(notice, LanguagePlaceholder, l.DisplayName())
+}
+
+// Validate checks that locales have unique, well-formed codes and that the
+// default locale is one of them.
+func Validate(locales []Locale, defaultCode string) error {
+	seen := make(map[string]bool)
+	for _, locale := range locales {
+		if !codeRegex.MatchString(locale.Code) {
+			return fmt.Errorf("invalid locale code: %q", locale.Code)
+		}
+		if seen[locale.Code] {
+			return fmt.Errorf("duplicate locale: %s", locale.Code)
+		}
+		seen[locale.Code] = true
+	}
+	if !seen[defaultCode] {
+		return fmt.Errorf("default locale %s is not a configured locale", defaultCode)
+	}
+	return nil
+}
+
+// Split returns the locale and the locale-neutral path of a document path:
+// "guide/page.ja.md" is ("ja", "guide/page.md") when ja is one of codes.
+// Paths without a known locale suffix belong to defaultCode.
+func Split(relativePath string, codes []string, defaultCode string) (string, string) {
+	base := strings.TrimSuffix(relativePath, filepath.Ext(relativePath))
+	for _, code := range codes {
+		if suffix := "." + code; strings.HasSuffix(strings.ToLower(base), strings.ToLower(suffix)) {
+			return code, base[:len(base)-len(suffix)] + filepath.Ext(relativePath)
+		}
+	}
+	return defaultCode, relativePath
+}
+
+// SplitDocuments groups documents scanned from shared input paths by their
+// filename suffix (see Split) into the given locales, with relative paths
+// stripped of the suffix so that translations share their path. Two files of
+// one locale with the same stripped path, such as index.md and index.en.md
+// when en is the default, are an error.
+func SplitDocuments(documents []scanner.Document, codes []string, defaultCode string) (map[string][]scanner.Document, error) {
+	byLocale := make(map[string][]scanner.Document)
+	sources := make(map[string]string) // Locale and stripped path -> original path
+	for _, doc := range documents {
+		code, relativePath := Split(doc.RelativePath, codes, defaultCode)
+		key := code + "\x00" + filepath.ToSlash(relativePath)
+		if source, ok := sources[key]; ok {
+			return nil, fmt.Errorf("%s and %s are both the %s page %s", source, doc.RelativePath, code, relativePath)
+		}
+		sources[key] = doc.RelativePath
+
+		doc.RelativePath = relativePath
+		byLocale[code] = append(byLocale[code], doc)
+	}
+	return byLocale, nil
+}
+
+// WithFallbacks returns the documents of a locale completed with the default
+// locale's documents it has no translation of, and the relative paths of
+// those fallbacks.
+func WithFallbacks(documents, defaults []scanner.Document) ([]scanner.Document, map[string]bool) {
+	translated := make(map[string]bool, len(documents))
+	for _, doc := range documents {
+		translated[doc.RelativePath] = true
+	}
+
+	fallbacks := make(map[string]bool)
+	result := append([]scanner.Document(nil), documents...)
+	for _, doc := range defaults {
+		if !translated[doc.RelativePath] {
+			result = append(result, doc)
+			fallbacks[doc.RelativePath] = true
+		}
+	}
+	return result, fallbacks
+}
+
+// siteIndex is a locales.json entry.
+type siteIndex struct {
+	Code    string `json:"code"`
+	Label   string `json:"label"`
+	Path    string `json:"path"` // Directory of the locale relative to the site root, with a trailing slash
+	Default bool   `json:"default,omitempty"`
+}
+
+// redirectPage is the site root index.html of a multi-language site. It
+// sends visitors to the index page of the first of their preferred languages
+// the site has, and to the default locale otherwise or without JavaScript.
+const redirectPage = `<!DOCTYPE html>
+<html lang="%[1]s">
+<head>
+    <meta charset="UTF-8">
+    <title>Documentation</title>
+    <script>
+        (function() {
+            var locales = %[2]s;
+            var preferred = navigator.languages || [navigator.language || ""];
+            for (var i = 0; i < preferred.length; i++) {
+                var language = preferred[i].toLowerCase();
+                for (var j = 0; j < locales.length; j++) {
+                    var code = locales[j].toLowerCase();
+                    if (language === code || language.split("-")[0] === code) {
+                        window.location.replace(locales[j] + "/` + site.IndexPage + `");
+                        return;
+                    }
+                }
+            }
+            window.location.replace("%[1]s/` + site.IndexPage + `");
+        })();
+    </script>
+    <noscript><meta http-equiv="refresh" content="0; url=%[1]s/` + site.IndexPage + `"></noscript>
+</head>
+<body>
+    <p><a href="%[1]s/` + site.IndexPage + `">Go to the documentation</a></p>
+</body>
+</html>
+`
+
+// WriteIndex writes the root of a multi-language site to outputPath: an
+// index.html redirecting to the visitor's language and a locales.json
+// listing every locale and its directory.
+func WriteIndex(outputPath string, locales []Locale, defaultCode string) error {
+	codes := make([]string, 0, len(locales))
+	entries := make([]siteIndex, 0, len(locales))
+	for _, locale := range locales {
+		codes = append(codes, locale.Code)
+		entries = append(entries, siteIndex{Code: locale.Code, Label: locale.DisplayName(), Path: locale.Code + "/", Default: locale.Code == defaultCode})
+	}
+
+	codesJSON, err := json.Marshal(codes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal locale codes: %w", err)
+	}
+	page := fmt.Sprintf(redirectPage, defaultCode, codesJSON)
+	if err := os.WriteFile(filepath.Join(outputPath, "index.html"), []byte(page), 0644); err != nil {
+		return fmt.Errorf("failed to write index.html: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal locales.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputPath, "locales.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write locales.json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onedusk/jot/internal/scanner"
+)
+
+// TestSplit tests splitting locale suffixes off document paths.
+func TestSplit(t *testing.T) {
+	codes := []string{"en", "ja", "pt-BR"}
+
+	tests := []struct {
+		path     string
+		wantCode string
+		wantPath string
+	}{
+		{"index.md", "en", "index.md"},
+		{"index.ja.md", "ja", "index.md"},
+		{"guide/setup.pt-BR.md", "pt-BR", "guide/setup.md"},
+		{"guide/setup.PT-br.md", "pt-BR", "guide/setup.md"},
+		{"guide/setup.de.md", "en", "guide/setup.de.md"},
+		{"japan.md", "en", "japan.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			code, path := Split(tt.path, codes, "en")
+			if code != tt.wantCode || path != tt.wantPath {
+				t.Errorf("Split(%q) = (%q, %q), want (%q, %q)", tt.path, code, path, tt.wantCode, tt.wantPath)
+			}
+		})
+	}
+}
+
+// TestSplitDocuments_WithFallbacks tests grouping documents by locale and
+// completing a locale with the default locale's untranslated pages.
+func TestSplitDocuments_WithFallbacks(t *testing.T) {
+	docs := []scanner.Document{
+		{RelativePath: "index.md"},
+		{RelativePath: "index.ja.md"},
+		{RelativePath: "guide/setup.md"},
+	}
+
+	byLocale, err := SplitDocuments(docs, []string{"en", "ja"}, "en")
+	if err != nil {
+		t.Fatalf("SplitDocuments() error = %v", err)
+	}
+	paths := func(docs []scanner.Document) []string {
+		var paths []string
+		for _, doc := range docs {
+			paths = append(paths, doc.RelativePath)
+		}
+		return paths
+	}
+	if got, want := paths(byLocale["en"]), []string{"index.md", "guide/setup.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitDocuments() en = %v, want %v", got, want)
+	}
+	if got, want := paths(byLocale["ja"]), []string{"index.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitDocuments() ja = %v, want %v", got, want)
+	}
+
+	ja, fallbacks := WithFallbacks(byLocale["ja"], byLocale["en"])
+	if got, want := paths(ja), []string{"index.md", "guide/setup.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WithFallbacks() = %v, want %v", got, want)
+	}
+	if want := map[string]bool{"guide/setup.md": true}; !reflect.DeepEqual(fallbacks, want) {
+		t.Errorf("WithFallbacks() fallbacks = %v, want %v", fallbacks, want)
+	}
+}
+
+// TestSplitDocuments_Collision tests that two files of one locale with the
+// same path after removing the suffix are an error.
+func TestSplitDocuments_Collision(t *testing.T) {
+	docs := []scanner.Document{
+		{RelativePath: "index.md"},
+		{RelativePath: "index.en.md"},
+	}
+
+	_, err := SplitDocuments(docs, []string{"en", "ja"}, "en")
+	if err == nil || !strings.Contains(err.Error(), "index.md and index.en.md") {
+		t.Errorf("SplitDocuments() error = %v, want a collision of index.md and index.en.md", err)
+	}
+}
+
+// TestValidate tests validation of the configured locales.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		locales     []Locale
+		defaultCode string
+		want        string // substring of the error, empty for valid locales
+	}{
+		{"valid", []Locale{{Code: "en"}, {Code: "zh-Hans"}}, "en", ""},
+		{"invalid code", []Locale{{Code: "en"}, {Code: "../ja"}}, "en", "invalid locale code"},
+		{"duplicate", []Locale{{Code: "en"}, {Code: "en"}}, "en", "duplicate locale: en"},
+		{"unknown default", []Locale{{Code: "en"}}, "fr", "default locale fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.locales, tt.defaultCode)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLocale_UntranslatedNotice tests the default and configured notices of
+// fallback pages.
+func TestLocale_UntranslatedNotice(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale Locale
+		want   string
+	}{
+		{"default", Locale{Code: "ja", Label: "日本語"}, "This page has not been translated into 日本語 yet and is shown in the original language."},
+		{"configured", Locale{Code: "ja", Untranslated: "このページは未翻訳です ({language})"}, "このページは未翻訳です (ja)"},
+		{"percent sign", Locale{Code: "de", Label: "Deutsch", Untranslated: "50% translated, see {language} soon"}, "50% translated, see Deutsch soon"},
+		{"without label", Locale{Code: "de", Untranslated: "Noch nicht übersetzt."}, "Noch nicht übersetzt."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.locale.UntranslatedNotice(); got != tt.want {
+				t.Errorf("UntranslatedNotice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriteIndex tests the root index.html and locales.json of a
+// multi-language site.
+func TestWriteIndex(t *testing.T) {
+	outputPath := t.TempDir()
+	locales := []Locale{{Code: "en", Label: "English"}, {Code: "ja"}}
+
+	if err := WriteIndex(outputPath, locales, "en"); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(outputPath, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`var locales = ["en","ja"];`, `content="0; url=en/index.html"`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("index.html missing %s", want)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputPath, "locales.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []siteIndex
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("locales.json is not valid JSON: %v", err)
+	}
+	want := []siteIndex{
+		{Code: "en", Label: "English", Path: "en/", Default: true},
+		{Code: "ja", Label: "ja", Path: "ja/"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("locales.json = %+v, want %+v", entries, want)
+	}
+}
@@ -0,0 +1,244 @@
+// Package jsonschema implements validation against the subset of JSON Schema
+// used by Jot's export contracts: types, required and additional properties,
+// array items, enums, constants and numeric minimums.
+//
+// It is intentionally small; schemas using other keywords are accepted but
+// those keywords are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is a parsed JSON Schema document.
+type Schema struct {
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 TypeList           `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// TypeList holds the allowed types of a value. In JSON it is either a single
+// type name or an array of names.
+type TypeList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = list
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t TypeList) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Additional is the value of additionalProperties: either a boolean or a
+// schema that extra properties must satisfy.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Additional) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+// Parse parses a JSON Schema document.
+func Parse(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// ValidationError lists every violation found in a value.
+type ValidationError struct {
+	Violations []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Violations, "; ")
+}
+
+// Validate checks a decoded JSON value (as produced by json.Unmarshal into an
+// interface{}) against the schema. It returns a *ValidationError describing
+// every violation, or nil if the value is valid.
+func (s *Schema) Validate(value interface{}) error {
+	var violations []string
+	s.validate("$", value, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// ValidateJSON decodes data and validates it against the schema.
+func (s *Schema) ValidateJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.Validate(value)
+}
+
+// validate appends the violations of value at path to violations.
+func (s *Schema) validate(path string, value interface{}, violations *[]string) {
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), typeOf(value)))
+		return
+	}
+
+	if s.Const != nil && !equal(s.Const, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %v", path, s.Const))
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if equal(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is not one of %v", path, value, s.Enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(path, v, violations)
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			*violations = append(*violations, fmt.Sprintf("%s: expected at least %d items, got %d", path, *s.MinItems, len(v)))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is less than %v", path, v, *s.Minimum))
+		}
+	}
+}
+
+// validateObject checks required, declared and additional properties.
+func (s *Schema) validateObject(path string, object map[string]interface{}, violations *[]string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*violations = append(*violations, fmt.Sprintf("%s: missing required property %q", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := path + "." + name
+		if property, ok := s.Properties[name]; ok {
+			property.validate(child, object[name], violations)
+			continue
+		}
+		if s.AdditionalProperties == nil {
+			continue
+		}
+		if !s.AdditionalProperties.Allowed {
+			*violations = append(*violations, fmt.Sprintf("%s: unexpected property", child))
+		} else if s.AdditionalProperties.Schema != nil {
+			s.AdditionalProperties.Schema.validate(child, object[name], violations)
+		}
+	}
+}
+
+// matches reports whether value has one of the listed types.
+func (t TypeList) matches(value interface{}) bool {
+	actual := typeOf(value)
+	for _, name := range t {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of a decoded JSON value.
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal compares two decoded JSON values.
+func equal(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
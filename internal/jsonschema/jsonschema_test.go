@@ -0,0 +1,60 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["id", "values"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string"},
+    "count": {"type": "integer", "minimum": 0},
+    "kind": {"enum": ["a", "b"]},
+    "values": {"type": "array", "minItems": 1, "items": {"type": "number"}},
+    "metadata": {"type": "object", "additionalProperties": {"type": ["string", "number"]}}
+  }
+}`
+
+// TestValidate tests validation of valid and invalid documents.
+func TestValidate(t *testing.T) {
+	schema, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want string // substring of the error, empty for valid documents
+	}{
+		{"valid", `{"id": "x", "values": [1, 0.5], "count": 2, "kind": "a", "metadata": {"k": "v", "n": 1}}`, ""},
+		{"missing required", `{"id": "x"}`, `missing required property "values"`},
+		{"wrong type", `{"id": 1, "values": [1]}`, "$.id: expected string, got integer"},
+		{"not an integer", `{"id": "x", "values": [1], "count": 1.5}`, "$.count: expected integer, got number"},
+		{"below minimum", `{"id": "x", "values": [1], "count": -1}`, "$.count: -1 is less than 0"},
+		{"enum", `{"id": "x", "values": [1], "kind": "c"}`, "$.kind: c is not one of"},
+		{"min items", `{"id": "x", "values": []}`, "expected at least 1 items"},
+		{"item type", `{"id": "x", "values": ["1"]}`, "$.values[0]: expected number"},
+		{"additional property", `{"id": "x", "values": [1], "extra": true}`, "$.extra: unexpected property"},
+		{"additional schema", `{"id": "x", "values": [1], "metadata": {"k": true}}`, "$.metadata.k: expected string or number"},
+		{"invalid json", `{`, "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateJSON([]byte(tt.doc))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateJSON() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateJSON() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package keywords provides corpus-aware keyword and keyphrase extraction.
+// Candidate terms are generated RAKE-style (runs of content words between stop
+// words and punctuation) and ranked by TF-IDF across the scanned documents, so
+// terms that are frequent in one document but rare in the corpus rank highest.
+package keywords
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLimit is the number of keywords returned per document by callers that
+// do not need a custom limit.
+const DefaultLimit = 10
+
+// maxPhraseWords is the longest keyphrase, in words, that will be considered.
+const maxPhraseWords = 3
+
+// minWordLength is the minimum rune length of a single-word keyword.
+const minWordLength = 3
+
+var (
+	codeBlockRegex  = regexp.MustCompile("(?s)```.*?```")
+	inlineCodeRegex = regexp.MustCompile("`[^`]+`")
+	imageRegex      = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	linkRegex       = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+	htmlTagRegex    = regexp.MustCompile(`<[^>]+>`)
+)
+
+// Keyword is a ranked keyword or keyphrase with its TF-IDF score.
+type Keyword struct {
+	Term  string
+	Score float64
+}
+
+// Extractor ranks the keywords of individual documents against the document
+// frequencies of a corpus. An Extractor built from an empty corpus ranks by
+// term frequency alone.
+type Extractor struct {
+	docFreq map[string]int
+	numDocs int
+}
+
+// NewExtractor creates an Extractor whose inverse document frequencies are
+// computed from the given corpus of raw markdown texts.
+func NewExtractor(corpus []string) *Extractor {
+	e := &Extractor{
+		docFreq: make(map[string]int),
+		numDocs: len(corpus),
+	}
+
+	for _, text := range corpus {
+		for term := range countCandidates(text) {
+			e.docFreq[term]++
+		}
+	}
+
+	return e
+}
+
+// Extract returns up to limit keywords for text, ordered from most to least
+// relevant. The order is deterministic for a given corpus and text.
+func (e *Extractor) Extract(text string, limit int) []string {
+	scored := e.ExtractScored(text, limit)
+
+	result := make([]string, 0, len(scored))
+	for _, kw := range scored {
+		result = append(result, kw.Term)
+	}
+	return result
+}
+
+// ExtractScored returns up to limit keywords for text along with their scores.
+// Ties are broken alphabetically so that output is stable between builds.
+func (e *Extractor) ExtractScored(text string, limit int) []Keyword {
+	counts := countCandidates(text)
+	if len(counts) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	scored := make([]Keyword, 0, len(counts))
+	for term, count := range counts {
+		words := strings.Count(term, " ") + 1
+
+		// A phrase seen only once is usually an accident of wording rather
+		// than a concept the document is about.
+		if words > 1 && count < 2 {
+			continue
+		}
+
+		tf := float64(count) / float64(total)
+		// Multi-word phrases are boosted by their length, as in RAKE where a
+		// phrase's score is the sum of its member word scores.
+		score := tf * e.idf(term) * float64(words)
+		scored = append(scored, Keyword{Term: term, Score: score})
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Term < scored[j].Term
+	})
+
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	return scored
+}
+
+// idf returns the smoothed inverse document frequency of a term. Smoothing
+// keeps the weight positive for terms found in every document, so small or
+// single-document corpora still produce keywords.
+func (e *Extractor) idf(term string) float64 {
+	if e.numDocs == 0 {
+		return 1
+	}
+	return math.Log(float64(1+e.numDocs)/float64(1+e.docFreq[term])) + 1
+}
+
+// countCandidates splits text into candidate keywords and keyphrases and
+// returns how often each one occurs.
+func countCandidates(text string) map[string]int {
+	counts := make(map[string]int)
+
+	for _, run := range candidateRuns(text) {
+		for i := range run {
+			if utf8.RuneCountInString(run[i]) >= minWordLength && !isNumeric(run[i]) {
+				counts[run[i]]++
+			}
+			for n := 2; n <= maxPhraseWords && i+n <= len(run); n++ {
+				counts[strings.Join(run[i:i+n], " ")]++
+			}
+		}
+	}
+
+	return counts
+}
+
+// candidateRuns strips markdown syntax from text and splits it into runs of
+// consecutive content words. Runs are delimited by punctuation and stop words,
+// which is how RAKE identifies candidate phrases.
+func candidateRuns(text string) [][]string {
+	text = codeBlockRegex.ReplaceAllString(text, " . ")
+	text = inlineCodeRegex.ReplaceAllString(text, " . ")
+	text = imageRegex.ReplaceAllString(text, " . ")
+	text = linkRegex.ReplaceAllString(text, "$1")
+	text = htmlTagRegex.ReplaceAllString(text, " . ")
+	text = strings.ToLower(text)
+
+	var runs [][]string
+	var current []string
+	var word strings.Builder
+
+	flushWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.Trim(word.String(), "-'")
+		word.Reset()
+		if w == "" || IsStopWord(w) {
+			flushRun(&runs, &current)
+			return
+		}
+		current = append(current, w)
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '\'':
+			word.WriteRune(r)
+		case r == ' ' || r == '\t':
+			flushWord()
+		default:
+			// Any other character (punctuation, newlines, markdown syntax)
+			// ends both the word and the phrase.
+			flushWord()
+			flushRun(&runs, &current)
+		}
+	}
+	flushWord()
+	flushRun(&runs, &current)
+
+	return runs
+}
+
+// flushRun appends the current run of words to runs and resets it.
+func flushRun(runs *[][]string, current *[]string) {
+	if len(*current) > 0 {
+		*runs = append(*runs, *current)
+	}
+	*current = nil
+}
+
+// isNumeric reports whether word consists solely of digits and separators.
+func isNumeric(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) && r != '-' && r != '\'' {
+			return false
+		}
+	}
+	return true
+}
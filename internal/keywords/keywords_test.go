@@ -0,0 +1,101 @@
+package keywords
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExtract_RanksDistinctiveTermsFirst verifies that terms common to every
+// document in the corpus rank below terms specific to one document.
+func TestExtract_RanksDistinctiveTermsFirst(t *testing.T) {
+	corpus := []string{
+		"Documentation guide. The documentation covers webhooks and webhooks retries.",
+		"Documentation guide. The documentation covers billing and invoices.",
+		"Documentation guide. The documentation covers authentication tokens.",
+	}
+	extractor := NewExtractor(corpus)
+
+	keywords := extractor.Extract(corpus[0], DefaultLimit)
+	if len(keywords) == 0 {
+		t.Fatal("Extract() returned no keywords")
+	}
+	if keywords[0] != "webhooks" {
+		t.Errorf("Extract()[0] = %q, want %q (got %v)", keywords[0], "webhooks", keywords)
+	}
+
+	position := func(term string) int {
+		for i, kw := range keywords {
+			if kw == term {
+				return i
+			}
+		}
+		return len(keywords)
+	}
+	if position("documentation") < position("webhooks") {
+		t.Errorf("corpus-wide term ranked above distinctive term: %v", keywords)
+	}
+}
+
+// TestExtract_Keyphrases verifies that repeated multi-word phrases are extracted.
+func TestExtract_Keyphrases(t *testing.T) {
+	text := "# Vector Database\n\nA vector database stores embeddings. " +
+		"Choose a vector database that supports filtering."
+
+	keywords := NewExtractor(nil).Extract(text, DefaultLimit)
+
+	found := false
+	for _, kw := range keywords {
+		if kw == "vector database" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Extract() should include keyphrase %q, got %v", "vector database", keywords)
+	}
+}
+
+// TestExtract_Deterministic verifies that repeated extraction yields identical output.
+func TestExtract_Deterministic(t *testing.T) {
+	corpus := []string{
+		"alpha beta gamma delta epsilon zeta eta theta iota kappa lambda",
+		"alpha omicron sigma",
+	}
+	extractor := NewExtractor(corpus)
+
+	first := extractor.Extract(corpus[0], 5)
+	for i := 0; i < 20; i++ {
+		if got := extractor.Extract(corpus[0], 5); !reflect.DeepEqual(got, first) {
+			t.Fatalf("Extract() not deterministic: %v vs %v", got, first)
+		}
+	}
+
+	// Ties are broken alphabetically
+	want := []string{"beta", "delta", "epsilon", "eta", "gamma"}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Extract() = %v, want %v", first, want)
+	}
+}
+
+// TestExtract_IgnoresStopWordsAndCode verifies that stop words, numbers and
+// code are never returned as keywords.
+func TestExtract_IgnoresStopWordsAndCode(t *testing.T) {
+	text := "This is the guide. It should be used with care in 2024.\n\n" +
+		"```go\nfunc secretFunction() {}\n```\n\nUse `inlineIdentifier` here."
+
+	for _, kw := range NewExtractor(nil).Extract(text, 0) {
+		if IsStopWord(kw) {
+			t.Errorf("Extract() returned stop word %q", kw)
+		}
+		switch kw {
+		case "2024", "secretfunction", "inlineidentifier":
+			t.Errorf("Extract() returned %q", kw)
+		}
+	}
+}
+
+// TestExtract_Empty verifies that empty input yields no keywords.
+func TestExtract_Empty(t *testing.T) {
+	if got := NewExtractor(nil).Extract("", DefaultLimit); len(got) != 0 {
+		t.Errorf("Extract(\"\") = %v, want empty", got)
+	}
+}
@@ -0,0 +1,42 @@
+package keywords
+
+// stopWords lists common English words that carry little meaning on their own.
+// They are never returned as keywords and act as phrase delimiters.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "above": true, "after": true, "again": true,
+	"against": true, "all": true, "also": true, "am": true, "an": true,
+	"and": true, "any": true, "are": true, "as": true, "at": true,
+	"be": true, "because": true, "been": true, "before": true, "being": true,
+	"below": true, "between": true, "both": true, "but": true, "by": true,
+	"can": true, "could": true, "did": true, "do": true, "does": true,
+	"doing": true, "down": true, "during": true, "each": true, "either": true,
+	"else": true, "etc": true, "even": true, "every": true, "few": true,
+	"for": true, "from": true, "further": true, "get": true, "gets": true,
+	"had": true, "has": true, "have": true, "having": true, "he": true,
+	"her": true, "here": true, "hers": true, "him": true, "his": true,
+	"how": true, "however": true, "i": true, "if": true, "in": true,
+	"into": true, "is": true, "it": true, "it's": true, "its": true,
+	"itself": true, "just": true, "let": true, "like": true, "may": true,
+	"me": true, "might": true, "more": true, "most": true, "much": true,
+	"must": true, "my": true, "no": true, "nor": true, "not": true,
+	"now": true, "of": true, "off": true, "often": true, "on": true,
+	"once": true, "one": true, "only": true, "or": true, "other": true,
+	"our": true, "ours": true, "out": true, "over": true, "own": true,
+	"per": true, "same": true, "see": true, "shall": true, "she": true,
+	"should": true, "since": true, "so": true, "some": true, "such": true,
+	"than": true, "that": true, "the": true, "their": true, "theirs": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "those": true, "through": true, "thus": true, "to": true,
+	"too": true, "under": true, "until": true, "up": true, "upon": true,
+	"us": true, "use": true, "used": true, "uses": true, "using": true,
+	"very": true, "via": true, "was": true, "we": true, "well": true,
+	"were": true, "what": true, "when": true, "where": true, "whether": true,
+	"which": true, "while": true, "who": true, "whom": true, "why": true,
+	"will": true, "with": true, "within": true, "without": true, "would": true,
+	"yet": true, "you": true, "your": true, "yours": true,
+}
+
+// IsStopWord reports whether word (in lower case) is a common English stop word.
+func IsStopWord(word string) bool {
+	return stopWords[word]
+}
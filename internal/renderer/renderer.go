@@ -13,18 +13,98 @@ import (
 
 	"github.com/russross/blackfriday/v2"
 	"github.com/onedusk/jot/internal/scanner"
+	"github.com/onedusk/jot/internal/site"
 	"github.com/onedusk/jot/internal/toc"
 )
 
 // HTMLRenderer is responsible for converting markdown documents into final HTML pages.
 // It manages templates, markdown-to-HTML conversion, and generation of navigation elements.
 type HTMLRenderer struct {
-	templates *template.Template
+	templates         *template.Template
+	markdownAlternate bool         // Link each page to its markdown twin
+	versions          *VersionInfo // Set when the page belongs to a versioned site
+	editURL           string       // Template of "Edit this page" links, with {path} for the file's repository path
+	language          string       // Language of the pages, for <html lang>
+	locales           *LocaleInfo  // Set when the page belongs to a multi-language site
+}
+
+// LocaleInfo places rendered pages in one locale directory of a site that
+// holds the documentation in several languages.
+type LocaleInfo struct {
+	Dir       string          // Output directory of the pages under the site root, e.g. "ja"
+	Current   string          // Code of the locale being rendered
+	Default   string          // Code of the default locale, the language of fallback pages
+	Locales   []SiteLocale    // Locales offered by the language switcher, in display order
+	Fallbacks map[string]bool // Relative paths of pages shown in the default locale for lack of a translation
+	Notice    string          // Notice shown on fallback pages
+}
+
+// SiteLocale is one entry of the language switcher.
+type SiteLocale struct {
+	Code  string
+	Label string
+	Dir   string          // Output directory under the site root
+	Pages map[string]bool // Relative paths of the locale's pages, including fallbacks
+}
+
+// VersionInfo places rendered pages in one version directory of a site that
+// holds several versions of the documentation side by side.
+type VersionInfo struct {
+	Dir      string        // Output directory of the pages under the site root, e.g. "v1" or "latest"
+	Current  string        // Name of the version being rendered
+	Latest   string        // Name of the latest version
+	Versions []SiteVersion // Versions offered by the version switcher, in display order
+}
+
+// SiteVersion is one entry of the version switcher.
+type SiteVersion struct {
+	Name  string
+	Label string
+	Dir   string          // Output directory under the site root
+	Pages map[string]bool // Relative paths of the version's documents
 }
 
 // NewHTMLRenderer creates and returns a new HTMLRenderer instance.
 func NewHTMLRenderer() *HTMLRenderer {
-	return &HTMLRenderer{}
+	return &HTMLRenderer{language: "en"}
+}
+
+// SetLanguage sets the language of rendered pages (<html lang>), a BCP 47
+// tag such as "en" or "ja". The default is "en".
+func (r *HTMLRenderer) SetLanguage(language string) {
+	r.language = language
+}
+
+// SetLocales renders pages as part of a multi-language site: pages take the
+// locale's language, the header gets a language switcher and the head
+// hreflang alternates linking to the same page in every locale (or the
+// locale's index when the page does not exist there), and fallback pages
+// are flagged with a notice. Passing nil renders a single-language site.
+func (r *HTMLRenderer) SetLocales(info *LocaleInfo) {
+	r.locales = info
+}
+
+// SetMarkdownAlternate makes rendered pages advertise their markdown twin
+// (page.md next to page.html) with a <link rel="alternate"> in the head.
+func (r *HTMLRenderer) SetMarkdownAlternate(enabled bool) {
+	r.markdownAlternate = enabled
+}
+
+// SetVersions renders pages as part of a versioned site: the header gets a
+// version switcher linking to the same page in every version (or the
+// version's index when the page does not exist there), pages of versions
+// other than the latest get an outdated banner, and breadcrumbs point into
+// the version directory. Passing nil renders an unversioned site.
+func (r *HTMLRenderer) SetVersions(info *VersionInfo) {
+	r.versions = info
+}
+
+// SetEditURL adds an "Edit this page" link to pages whose documents carry git
+// history, built from urlTemplate by replacing {path} with the file's path in
+// the repository, e.g. "https://github.com/org/repo/edit/main/{path}".
+// Passing "" omits the link.
+func (r *HTMLRenderer) SetEditURL(urlTemplate string) {
+	r.editURL = urlTemplate
 }
 
 // getRelativePrefix calculates the relative path prefix (e.g., "../") needed to
@@ -156,12 +236,89 @@ func (r *HTMLRenderer) RenderPage(doc scanner.Document, tableOfContents *toc.Tab
 		Navigation:     template.HTML(nav),
 		Breadcrumb:     breadcrumb,
 		RelativePrefix: relativePrefix,
+		Lang:           r.language,
+	}
+	if r.markdownAlternate {
+		data.MarkdownURL = filepath.Base(filepath.ToSlash(doc.RelativePath))
+	}
+	if r.versions != nil {
+		r.addVersionData(&data, filepath.ToSlash(doc.RelativePath), relativePrefix)
+	}
+	if r.locales != nil {
+		r.addLocaleData(&data, filepath.ToSlash(doc.RelativePath), relativePrefix)
+	}
+	if doc.Git != nil {
+		data.LastUpdated = doc.Git.Updated.Format("January 2, 2006")
+		data.Contributors = strings.Join(doc.Git.Authors, ", ")
+		if r.editURL != "" {
+			data.EditURL = strings.ReplaceAll(r.editURL, "{path}", doc.Git.Path)
+		}
 	}
 
 	// Render using template
 	return r.renderTemplate(data)
 }
 
+// addVersionData fills in the version switcher and outdated banner of a page
+// and moves its breadcrumbs into the version directory.
+func (r *HTMLRenderer) addVersionData(data *PageData, relativePath, relativePrefix string) {
+	siteRoot := relativePrefix + "../"
+	pageURL := func(version SiteVersion) string {
+		if version.Pages[relativePath] {
+			return siteRoot + version.Dir + "/" + strings.Replace(relativePath, ".md", ".html", 1)
+		}
+		return siteRoot + version.Dir + "/" + site.IndexPage
+	}
+
+	for _, version := range r.versions.Versions {
+		data.Versions = append(data.Versions, VersionLink{
+			Label:   version.Label,
+			URL:     pageURL(version),
+			Current: version.Name == r.versions.Current,
+		})
+		if version.Name == r.versions.Current {
+			data.VersionLabel = version.Label
+		}
+		if version.Name == r.versions.Latest && r.versions.Current != r.versions.Latest {
+			data.LatestLabel = version.Label
+			data.LatestURL = pageURL(version)
+		}
+	}
+
+	for i := range data.Breadcrumb {
+		data.Breadcrumb[i].Path = "/" + r.versions.Dir + data.Breadcrumb[i].Path
+	}
+}
+
+// addLocaleData fills in the language, language switcher and fallback notice
+// of a page and moves its breadcrumbs into the locale directory.
+func (r *HTMLRenderer) addLocaleData(data *PageData, relativePath, relativePrefix string) {
+	siteRoot := relativePrefix + "../"
+	data.Lang = r.locales.Current
+
+	for _, locale := range r.locales.Locales {
+		url := siteRoot + locale.Dir + "/" + site.IndexPage
+		if locale.Pages[relativePath] {
+			url = siteRoot + locale.Dir + "/" + strings.Replace(relativePath, ".md", ".html", 1)
+		}
+		data.Locales = append(data.Locales, LocaleLink{
+			Code:    locale.Code,
+			Label:   locale.Label,
+			URL:     url,
+			Current: locale.Code == r.locales.Current,
+		})
+	}
+
+	if r.locales.Fallbacks[relativePath] {
+		data.ContentLang = r.locales.Default
+		data.Untranslated = r.locales.Notice
+	}
+
+	for i := range data.Breadcrumb {
+		data.Breadcrumb[i].Path = "/" + r.locales.Dir + data.Breadcrumb[i].Path
+	}
+}
+
 // ResolveInternalLinks converts relative links to markdown files (.md) into
 // links to the corresponding HTML files (.html) within the generated HTML.
 func (r *HTMLRenderer) ResolveInternalLinks(html string) string {
@@ -297,6 +454,35 @@ type PageData struct {
 	Navigation     template.HTML
 	Breadcrumb     []BreadcrumbItem
 	RelativePrefix string
+	MarkdownURL    string // Markdown twin of the page, relative to it; empty if none
+	Versions       []VersionLink
+	VersionLabel   string // Label of the page's version on a versioned site
+	LatestLabel    string // Label of the latest version, when the page is outdated
+	LatestURL      string // The page, or the index, in the latest version, when the page is outdated
+	LastUpdated    string // Date of the last commit to the page's file, when git history is read
+	Contributors   string // Authors of the commits to the page's file, comma-separated
+	EditURL        string // Link to edit the page's file in its repository
+	Lang           string // Language of the page
+	Locales        []LocaleLink
+	ContentLang    string // Language of the content when it differs from Lang, as on fallback pages
+	Untranslated   string // Notice shown on pages that fall back to the default locale
+}
+
+// LocaleLink is an entry of the language switcher, linking to the current
+// page in another locale.
+type LocaleLink struct {
+	Code    string
+	Label   string
+	URL     string
+	Current bool
+}
+
+// VersionLink is an entry of the version switcher, linking to the current
+// page in another version.
+type VersionLink struct {
+	Label   string
+	URL     string
+	Current bool
 }
 
 // BreadcrumbItem represents a single item in a breadcrumb navigation trail.
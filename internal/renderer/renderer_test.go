@@ -4,6 +4,7 @@ package renderer
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/onedusk/jot/internal/scanner"
 	"github.com/onedusk/jot/internal/toc"
@@ -149,6 +150,184 @@ func TestHTMLRenderer_RenderPage(t *testing.T) {
 			t.Errorf("RenderPage() missing expected element: %s", elem)
 		}
 	}
+
+	if strings.Contains(page, `rel="alternate"`) {
+		t.Error("RenderPage() links a markdown twin without SetMarkdownAlternate")
+	}
+	renderer.SetMarkdownAlternate(true)
+	page, err = renderer.RenderPage(doc, &toc.TableOfContents{Root: tocRoot})
+	if err != nil {
+		t.Fatalf("RenderPage() error = %v", err)
+	}
+	if !strings.Contains(page, `<link rel="alternate" type="text/markdown" href="test.md">`) {
+		t.Error("RenderPage() missing markdown alternate link")
+	}
+}
+
+// TestHTMLRenderer_RenderPage_Versions tests the version switcher, outdated
+// banner and breadcrumbs of pages on a versioned site.
+func TestHTMLRenderer_RenderPage_Versions(t *testing.T) {
+	doc := scanner.Document{
+		Title:        "Install",
+		RelativePath: "guide/install.md",
+		Content:      []byte("# Install"),
+	}
+	tableOfContents := &toc.TableOfContents{Root: &toc.TOCNode{ID: "root"}}
+	versions := []SiteVersion{
+		{Name: "v2", Label: "2.x", Dir: "latest", Pages: map[string]bool{"index.md": true}},
+		{Name: "v1", Label: "1.x", Dir: "v1", Pages: map[string]bool{"guide/install.md": true}},
+	}
+
+	tests := []struct {
+		name    string
+		info    VersionInfo
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "outdated version",
+			info: VersionInfo{Dir: "v1", Current: "v1", Latest: "v2", Versions: versions},
+			want: []string{
+				`<option value="../../latest/index.html">2.x</option>`,
+				`<option value="../../v1/guide/install.html" selected>1.x</option>`,
+				`You are viewing the documentation for 1.x, which is not the latest version.`,
+				`<a href="../../latest/index.html">See this page in 2.x</a>`,
+				`<a href="/v1/guide/" class="breadcrumb-link">Guide</a>`,
+			},
+		},
+		{
+			name:    "latest version",
+			info:    VersionInfo{Dir: "latest", Current: "v2", Latest: "v2", Versions: versions},
+			want:    []string{`<option value="../../latest/index.html" selected>2.x</option>`, `<a href="/latest/" class="breadcrumb-link">Home</a>`},
+			notWant: []string{`version-banner`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewHTMLRenderer()
+			renderer.SetVersions(&tt.info)
+			page, err := renderer.RenderPage(doc, tableOfContents)
+			if err != nil {
+				t.Fatalf("RenderPage() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(page, want) {
+					t.Errorf("RenderPage() missing %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(page, notWant) {
+					t.Errorf("RenderPage() contains %s", notWant)
+				}
+			}
+		})
+	}
+}
+
+// TestHTMLRenderer_RenderPage_Locales tests the language attribute, language
+// switcher, alternate links and untranslated notice of a multi-language site.
+func TestHTMLRenderer_RenderPage_Locales(t *testing.T) {
+	doc := scanner.Document{
+		Title:        "Install",
+		RelativePath: "guide/install.md",
+		Content:      []byte("# Install"),
+	}
+	tableOfContents := &toc.TableOfContents{Root: &toc.TOCNode{ID: "root"}}
+	locales := []SiteLocale{
+		{Code: "en", Label: "English", Dir: "en", Pages: map[string]bool{"guide/install.md": true}},
+		{Code: "ja", Label: "日本語", Dir: "ja", Pages: map[string]bool{"guide/install.md": true}},
+		{Code: "de", Label: "Deutsch", Dir: "de", Pages: map[string]bool{"index.md": true}},
+	}
+
+	tests := []struct {
+		name    string
+		info    LocaleInfo
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "translated page",
+			info: LocaleInfo{Dir: "ja", Current: "ja", Default: "en", Locales: locales, Notice: "Not translated"},
+			want: []string{
+				`<html lang="ja">`,
+				`<link rel="alternate" hreflang="en" href="../../en/guide/install.html">`,
+				`<option value="../../ja/guide/install.html" lang="ja" selected>日本語</option>`,
+				`<option value="../../de/index.html" lang="de">Deutsch</option>`,
+				`<a href="/ja/guide/" class="breadcrumb-link">Guide</a>`,
+			},
+			notWant: []string{`untranslated-banner`, `<article lang=`, `hreflang="ja"`},
+		},
+		{
+			name: "fallback page",
+			info: LocaleInfo{Dir: "ja", Current: "ja", Default: "en", Locales: locales, Fallbacks: map[string]bool{"guide/install.md": true}, Notice: "Not translated"},
+			want: []string{
+				`<div class="untranslated-banner">Not translated</div>`,
+				`<article lang="en">`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewHTMLRenderer()
+			renderer.SetLocales(&tt.info)
+			page, err := renderer.RenderPage(doc, tableOfContents)
+			if err != nil {
+				t.Fatalf("RenderPage() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(page, want) {
+					t.Errorf("RenderPage() missing %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(page, notWant) {
+					t.Errorf("RenderPage() contains %s", notWant)
+				}
+			}
+		})
+	}
+}
+
+// TestHTMLRenderer_RenderPage_Git tests the last updated line and edit link
+// of pages whose documents carry git history.
+func TestHTMLRenderer_RenderPage_Git(t *testing.T) {
+	doc := scanner.Document{
+		Title:        "Install",
+		RelativePath: "guide/install.md",
+		Content:      []byte("# Install"),
+	}
+	tableOfContents := &toc.TableOfContents{Root: &toc.TOCNode{ID: "root"}}
+
+	renderer := NewHTMLRenderer()
+	renderer.SetEditURL("https://github.com/org/repo/edit/main/{path}")
+	page, err := renderer.RenderPage(doc, tableOfContents)
+	if err != nil {
+		t.Fatalf("RenderPage() error = %v", err)
+	}
+	if strings.Contains(page, "page-meta") {
+		t.Error("RenderPage() shows page metadata for a document without git history")
+	}
+
+	doc.Git = &scanner.GitInfo{
+		Commit:  "9fceb02d0ae598e95dc970b74767f19372d61af8",
+		Updated: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Authors: []string{"Grace", "Ada"},
+		Path:    "docs/guide/install.md",
+	}
+	page, err = renderer.RenderPage(doc, tableOfContents)
+	if err != nil {
+		t.Fatalf("RenderPage() error = %v", err)
+	}
+	for _, want := range []string{
+		"Last updated March 1, 2025 by Grace, Ada",
+		`<a href="https://github.com/org/repo/edit/main/docs/guide/install.md" class="edit-link">Edit this page</a>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("RenderPage() missing %s", want)
+		}
+	}
 }
 
 // TestHTMLRenderer_ResolveInternalLinks tests the resolution of internal markdown links.
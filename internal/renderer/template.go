@@ -4,12 +4,14 @@
 package renderer
 
 const htmlTemplate = `<!DOCTYPE html>
-<html lang="en">
+<html lang="{{.Lang}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>{{.Title}} | Jot Documentation</title>
-
+{{range .Locales}}{{if not .Current}}    <link rel="alternate" hreflang="{{.Code}}" href="{{.URL}}">
+{{end}}{{end}}{{if .MarkdownURL}}    <link rel="alternate" type="text/markdown" href="{{.MarkdownURL}}">
+{{end}}
     <!-- Modern Font Stack -->
     <link rel="preconnect" href="https://fonts.googleapis.com">
     <link rel="preconnect" href="https://fonts.gstatic.com" crossorigin>
@@ -33,7 +35,13 @@ const htmlTemplate = `<!DOCTYPE html>
                     <div class="logo">Documentation</div>
                 </div>
                 <nav class="header-nav">
-                    <a href="#" class="header-link">Docs</a>
+{{if .Locales}}                    <select class="locale-switcher" aria-label="Language" onchange="window.location.href = this.value">
+{{range .Locales}}                        <option value="{{.URL}}" lang="{{.Code}}"{{if .Current}} selected{{end}}>{{.Label}}</option>
+{{end}}                    </select>
+{{end}}{{if .Versions}}                    <select class="version-switcher" aria-label="Documentation version" onchange="window.location.href = this.value">
+{{range .Versions}}                        <option value="{{.URL}}"{{if .Current}} selected{{end}}>{{.Label}}</option>
+{{end}}                    </select>
+{{end}}                    <a href="#" class="header-link">Docs</a>
                     <a href="#" class="header-link">API</a>
                     <a href="#" class="header-link">GitHub</a>
                 </nav>
@@ -67,11 +75,26 @@ const htmlTemplate = `<!DOCTYPE html>
                     {{end}}
                 </nav>
 
-                <!-- Article Content -->
-                <article>
+{{if .LatestURL}}                <!-- Outdated Version Banner -->
+                <div class="version-banner">
+                    You are viewing the documentation for {{.VersionLabel}}, which is not the latest version.
+                    <a href="{{.LatestURL}}">See this page in {{.LatestLabel}}</a>.
+                </div>
+
+{{end}}{{if .Untranslated}}                <!-- Untranslated Page Notice -->
+                <div class="untranslated-banner">{{.Untranslated}}</div>
+
+{{end}}                <!-- Article Content -->
+                <article{{if .ContentLang}} lang="{{.ContentLang}}"{{end}}>
                     {{.Content}}
                 </article>
-            </div>
+{{if or .LastUpdated .EditURL}}
+                <!-- Page Metadata -->
+                <footer class="page-meta">
+{{if .LastUpdated}}                    <span class="page-updated">Last updated {{.LastUpdated}}{{if .Contributors}} by {{.Contributors}}{{end}}</span>
+{{end}}{{if .EditURL}}                    <a href="{{.EditURL}}" class="edit-link">Edit this page</a>
+{{end}}                </footer>
+{{end}}            </div>
         </main>
     </div>
 
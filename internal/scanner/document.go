@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Document represents a single parsed markdown file, including its content,
@@ -19,7 +21,8 @@ type Document struct {
 	Content      []byte                 // The raw markdown content of the file, with frontmatter removed.
 	HTML         string                 // Rendered HTML content (populated by the renderer).
 	Metadata     map[string]interface{} // Key-value data parsed from YAML frontmatter.
-	ModTime      time.Time              // The last modification time of the file.
+	ModTime      time.Time              // The last modification time of the file, or of its last commit when git history is read.
+	Git          *GitInfo               // Git history of the file; nil unless the scanner reads git history.
 	Sections     []Section              // A slice of sections extracted from the document.
 	Links        []Link                 // A slice of links found in the document.
 	CodeBlocks   []CodeBlock            // A slice of code blocks found in the document.
@@ -201,10 +204,14 @@ func ExtractFrontmatter(content []byte) (map[string]interface{}, []byte) {
 		return nil, content
 	}
 
-	// For now, return empty map and content without frontmatter
-	// TODO: Implement proper YAML parsing
-	frontmatterEnd := endIndex + 4 + 4 // account for both --- markers
-	return make(map[string]interface{}), content[frontmatterEnd:]
+	// Malformed YAML yields empty metadata; the block is still removed
+	metadata := make(map[string]interface{})
+	if err := yaml.Unmarshal(content[4:4+endIndex], &metadata); err != nil || metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+
+	frontmatterEnd := 4 + endIndex + 5 // opening "---\n" and closing "\n---\n" markers
+	return metadata, content[frontmatterEnd:]
 }
 
 // generateSectionID creates a URL-friendly slug from a section title.
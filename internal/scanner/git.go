@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitInfo holds the git history of a document's file.
+type GitInfo struct {
+	Commit  string    // Hash of the last commit that changed the file
+	Updated time.Time // Committer date of that commit
+	Authors []string  // Authors of the commits that changed the file, most commits first, then most recent
+	Path    string    // Path of the file relative to the repository root
+}
+
+// gitFile accumulates the history of one file while reading the log.
+type gitFile struct {
+	info    GitInfo
+	commits map[string]int // Author -> number of commits
+}
+
+// readGitHistory fills in the Git field of documents from the history of the
+// repository containing the scanned root, using the git binary, and replaces
+// their ModTime with the date of their last commit. Files without commits,
+// such as untracked files, keep their filesystem time. Shallow clones are an
+// error, since their history attributes every file to the newest commits.
+func (s *Scanner) readGitHistory(documents []Document) error {
+	// Run git in the scanned directory (the parent of a scanned file), where
+	// the relative paths of the documents start
+	dir := s.rootPath
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	prefix, err := runGit(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	shallow, err := runGit(dir, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(shallow) == "true" {
+		return fmt.Errorf("%s is a shallow clone, so every file would appear last changed by its newest commit; fetch the full history (git fetch --unshallow, or fetch-depth: 0 for actions/checkout) or disable git.history", dir)
+	}
+
+	// Newest commits come first, each followed by the files it changed,
+	// relative to dir
+	log, err := runGit(dir, "log", "--relative", "--no-renames",
+		"--format=%x00%H%x1f%cI%x1f%aN", "--name-only", "--", ".")
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]*gitFile)
+	for _, record := range strings.Split(log, "\x00") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+		updated, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return fmt.Errorf("failed to parse commit date %q: %w", fields[1], err)
+		}
+		author := fields[2]
+
+		for _, name := range lines[1:] {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			file, ok := files[name]
+			if !ok {
+				file = &gitFile{
+					info:    GitInfo{Commit: fields[0], Updated: updated, Path: prefix + name},
+					commits: make(map[string]int),
+				}
+				files[name] = file
+			}
+			if file.commits[author] == 0 {
+				file.info.Authors = append(file.info.Authors, author)
+			}
+			file.commits[author]++
+		}
+	}
+
+	for i := range documents {
+		file, ok := files[filepath.ToSlash(documents[i].RelativePath)]
+		if !ok {
+			continue
+		}
+		info := file.info
+		sort.SliceStable(info.Authors, func(a, b int) bool {
+			return file.commits[info.Authors[a]] > file.commits[info.Authors[b]]
+		})
+		documents[i].Git = &info
+		documents[i].ModTime = info.Updated
+	}
+
+	return nil
+}
+
+// runGit runs a git command in dir and returns its standard output. Paths
+// are printed unquoted.
+func runGit(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s: %w", args[0], message, err)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
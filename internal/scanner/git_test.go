@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestScanner_GitHistory tests reading the last commit, date and authors of
+// documents from git.
+func TestScanner_GitHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(author, date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=" + author + "@example.com"}, args...)...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("Ada", "2025-01-01T10:00:00Z", "init", "-q")
+	write("docs/guide/install.md", "# Install")
+	write("docs/usage.md", "# Usage")
+	git("Ada", "2025-01-01T10:00:00Z", "add", ".")
+	git("Ada", "2025-01-01T10:00:00Z", "commit", "-q", "-m", "Add docs")
+	write("docs/guide/install.md", "# Install\n\nMore.")
+	git("Grace", "2025-02-01T10:00:00Z", "commit", "-q", "-am", "Expand install")
+	write("docs/guide/install.md", "# Install\n\nEven more.")
+	git("Grace", "2025-03-01T10:00:00Z", "commit", "-q", "-am", "Expand install again")
+	write("docs/draft.md", "# Draft")
+
+	s, err := NewScanner(filepath.Join(repo, "docs"), nil)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	s.SetGitHistory(true)
+	docs, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	byPath := make(map[string]Document)
+	for _, doc := range docs {
+		byPath[doc.RelativePath] = doc
+	}
+
+	install := byPath["guide/install.md"]
+	if install.Git == nil {
+		t.Fatal("Scan() did not read the git history of guide/install.md")
+	}
+	if len(install.Git.Commit) != 40 {
+		t.Errorf("Git.Commit = %q, want a commit hash", install.Git.Commit)
+	}
+	wantUpdated := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	if !install.Git.Updated.Equal(wantUpdated) || !install.ModTime.Equal(wantUpdated) {
+		t.Errorf("Git.Updated = %v, ModTime = %v, want %v", install.Git.Updated, install.ModTime, wantUpdated)
+	}
+	if want := []string{"Grace", "Ada"}; !reflect.DeepEqual(install.Git.Authors, want) {
+		t.Errorf("Git.Authors = %v, want %v", install.Git.Authors, want)
+	}
+	if install.Git.Path != "docs/guide/install.md" {
+		t.Errorf("Git.Path = %q, want docs/guide/install.md", install.Git.Path)
+	}
+
+	usage := byPath["usage.md"]
+	if usage.Git == nil || !usage.Git.Updated.Equal(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)) || !reflect.DeepEqual(usage.Git.Authors, []string{"Ada"}) {
+		t.Errorf("usage.md Git = %+v, want Ada's first commit", usage.Git)
+	}
+	if draft := byPath["draft.md"]; draft.Git != nil {
+		t.Errorf("untracked draft.md Git = %+v, want nil", draft.Git)
+	}
+
+	// Without git history documents keep their filesystem times
+	s.SetGitHistory(false)
+	docs, err = s.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	for _, doc := range docs {
+		if doc.Git != nil {
+			t.Errorf("Scan() without git history set Git on %s", doc.RelativePath)
+		}
+	}
+
+	// Shallow clones, such as default CI checkouts, have no usable history
+	clone := filepath.Join(t.TempDir(), "clone")
+	git("Ada", "2025-03-01T10:00:00Z", "clone", "-q", "--depth", "1", "file://"+filepath.ToSlash(repo), clone)
+	s, err = NewScanner(filepath.Join(clone, "docs"), nil)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	s.SetGitHistory(true)
+	if _, err := s.Scan(); err == nil || !strings.Contains(err.Error(), "shallow clone") {
+		t.Errorf("Scan() of a shallow clone error = %v, want a shallow clone error", err)
+	}
+}
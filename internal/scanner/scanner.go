@@ -6,6 +6,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -15,8 +16,9 @@ import (
 // Scanner is used to discover and read markdown files from a specified root directory,
 // applying ignore patterns and parsing files into Document structs.
 type Scanner struct {
-	rootPath string
-	filter   *IgnoreFilter
+	rootPath   string
+	filter     *IgnoreFilter
+	gitHistory bool // Read last commit, date and authors of each document from git
 }
 
 // NewScanner creates a new Scanner for the given root path and ignore patterns.
@@ -43,6 +45,14 @@ func NewScanner(rootPath string, ignorePatterns []string) (*Scanner, error) {
 	}, nil
 }
 
+// SetGitHistory makes Scan read the git history of the scanned files, so
+// that documents carry their last commit, its date (also as ModTime) and
+// their authors. It requires the git binary and a repository containing the
+// root path.
+func (s *Scanner) SetGitHistory(enabled bool) {
+	s.gitHistory = enabled
+}
+
 // Scan walks the configured root path, discovers all markdown files that are not
 // ignored, and returns them as a slice of parsed Document structs.
 func (s *Scanner) Scan() ([]Document, error) {
@@ -89,6 +99,12 @@ func (s *Scanner) Scan() ([]Document, error) {
 		return nil, err
 	}
 
+	if s.gitHistory {
+		if err := s.readGitHistory(documents); err != nil {
+			return nil, fmt.Errorf("failed to read git history: %w", err)
+		}
+	}
+
 	return documents, nil
 }
 
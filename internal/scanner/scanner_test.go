@@ -4,6 +4,7 @@ package scanner
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -225,3 +226,49 @@ func TestIgnoreFilter(t *testing.T) {
 		})
 	}
 }
+
+// TestExtractFrontmatter tests YAML frontmatter parsing.
+func TestExtractFrontmatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantMeta    map[string]interface{}
+		wantContent string
+	}{
+		{
+			name:        "fields",
+			content:     "---\ntitle: Guide\ndescription: How it works\noptional: true\npriority: 2\n---\n# Guide",
+			wantMeta:    map[string]interface{}{"title": "Guide", "description": "How it works", "optional": true, "priority": 2},
+			wantContent: "# Guide",
+		},
+		{
+			name:        "malformed yaml",
+			content:     "---\ntitle: [unclosed\n---\nBody",
+			wantMeta:    map[string]interface{}{},
+			wantContent: "Body",
+		},
+		{
+			name:        "empty block",
+			content:     "---\n\n---\nBody",
+			wantMeta:    map[string]interface{}{},
+			wantContent: "Body",
+		},
+		{
+			name:        "no frontmatter",
+			content:     "# Title",
+			wantContent: "# Title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, content := ExtractFrontmatter([]byte(tt.content))
+			if !reflect.DeepEqual(meta, tt.wantMeta) {
+				t.Errorf("ExtractFrontmatter() metadata = %#v, want %#v", meta, tt.wantMeta)
+			}
+			if string(content) != tt.wantContent {
+				t.Errorf("ExtractFrontmatter() content = %q, want %q", content, tt.wantContent)
+			}
+		})
+	}
+}
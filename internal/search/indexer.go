@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 )
 
@@ -56,8 +57,16 @@ func (idx *Indexer) BuildIndex(documents []scanner.Document) (*Index, error) {
 		Documents: make([]IndexDocument, 0, len(documents)),
 	}
 
+	// Rank keywords against the whole corpus so that terms shared by every
+	// document do not crowd out the ones that distinguish each page
+	corpus := make([]string, 0, len(documents))
 	for _, doc := range documents {
-		indexDoc := idx.processDocument(doc)
+		corpus = append(corpus, string(doc.Content))
+	}
+	extractor := keywords.NewExtractor(corpus)
+
+	for _, doc := range documents {
+		indexDoc := idx.processDocument(doc, extractor)
 		index.Documents = append(index.Documents, indexDoc)
 	}
 
@@ -65,15 +74,16 @@ func (idx *Indexer) BuildIndex(documents []scanner.Document) (*Index, error) {
 }
 
 // processDocument converts a scanner.Document into an IndexDocument, extracting
-// and cleaning data to make it suitable for indexing.
-func (idx *Indexer) processDocument(doc scanner.Document) IndexDocument {
+// and cleaning data to make it suitable for indexing. Keywords are ranked by
+// extractor.
+func (idx *Indexer) processDocument(doc scanner.Document, extractor *keywords.Extractor) IndexDocument {
 	content := string(doc.Content)
 
 	// Extract headings
 	headings := idx.extractHeadings(content)
 
-	// Extract keywords (simple approach - can be enhanced)
-	keywords := idx.extractKeywords(content)
+	// Extract ranked keywords
+	keywords := idx.extractKeywords(content, extractor)
 
 	// Clean content for search (remove markdown syntax)
 	cleanContent := idx.cleanContent(content)
@@ -142,38 +152,11 @@ func (idx *Indexer) extractHeadings(content string) []string {
 	return headings
 }
 
-// extractKeywords performs a simple keyword extraction from content by counting
-// word frequencies and selecting the most common, non-trivial words.
-func (idx *Indexer) extractKeywords(content string) []string {
-	// Remove markdown syntax
-	clean := idx.cleanContent(content)
-
-	// Split into words
-	words := strings.Fields(clean)
-
-	// Count word frequency
-	wordCount := make(map[string]int)
-	for _, word := range words {
-		word = strings.ToLower(word)
-		word = strings.Trim(word, ".,!?;:'\"")
-
-		// Skip common words and short words
-		if len(word) < 4 || isCommonWord(word) {
-			continue
-		}
-
-		wordCount[word]++
-	}
-
-	// Get top keywords
-	var keywords []string
-	for word, count := range wordCount {
-		if count >= 3 { // Word appears at least 3 times
-			keywords = append(keywords, word)
-		}
-	}
-
-	return keywords
+// extractKeywords returns the top TF-IDF ranked keywords and keyphrases for
+// content. An extractor without a corpus ranks terms by frequency within the
+// document alone.
+func (idx *Indexer) extractKeywords(content string, extractor *keywords.Extractor) []string {
+	return extractor.Extract(content, keywords.DefaultLimit)
 }
 
 // cleanContent strips markdown syntax from a string, leaving plain text.
@@ -240,17 +223,5 @@ func (idx *Indexer) SaveIndex(index *Index) error {
 
 // isCommonWord checks if a word is a common English stop word.
 func isCommonWord(word string) bool {
-	commonWords := map[string]bool{
-		"the": true, "and": true, "for": true, "are": true,
-		"with": true, "this": true, "that": true, "from": true,
-		"have": true, "been": true, "were": true, "will": true,
-		"your": true, "their": true, "what": true, "when": true,
-		"where": true, "which": true, "these": true, "those": true,
-		"there": true, "then": true, "than": true, "both": true,
-		"each": true, "some": true, "such": true, "only": true,
-		"very": true, "just": true, "most": true, "also": true,
-		"into": true, "over": true, "after": true, "before": true,
-	}
-
-	return commonWords[word]
+	return keywords.IsStopWord(word)
 }
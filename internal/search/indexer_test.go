@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 )
 
@@ -114,7 +115,7 @@ func TestIndexer_ProcessDocument(t *testing.T) {
 	indexer := NewIndexer("/tmp/test")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			indexDoc := indexer.processDocument(tt.doc)
+			indexDoc := indexer.processDocument(tt.doc, keywords.NewExtractor(nil))
 
 			if indexDoc.ID != tt.wantID {
 				t.Errorf("processDocument() ID = %s, want %s", indexDoc.ID, tt.wantID)
@@ -194,7 +195,7 @@ func TestIndexer_ExtractKeywords(t *testing.T) {
 		"The protocol uses standard procedures and requires careful implementation. " +
 		"Protocol specifications must be followed during implementation process."
 
-	keywords := indexer.extractKeywords(content)
+	keywords := indexer.extractKeywords(content, keywords.NewExtractor(nil))
 
 	if len(keywords) == 0 {
 		t.Error("extractKeywords() returned no keywords")
@@ -335,7 +336,7 @@ func TestEnhancedMetadataInIndex(t *testing.T) {
 	}
 
 	indexer := NewIndexer("/tmp/test")
-	indexDoc := indexer.processDocument(doc)
+	indexDoc := indexer.processDocument(doc, keywords.NewExtractor(nil))
 
 	// Verify all enhanced metadata fields are populated
 	if indexDoc.Summary == "" {
@@ -368,3 +369,25 @@ func TestEnhancedMetadataInIndex(t *testing.T) {
 		t.Errorf("Tags count = %d, want %d", len(indexDoc.Tags), len(expectedTags))
 	}
 }
+
+// TestIndexer_BuildIndex_Keywords tests that BuildIndex ranks keywords
+// against the whole document set, so that terms shared by every document
+// rank below the ones that distinguish a page.
+func TestIndexer_BuildIndex_Keywords(t *testing.T) {
+	docs := []scanner.Document{
+		{RelativePath: "webhooks.md", Title: "Webhooks", Content: []byte("Documentation guide. The documentation covers webhooks and webhooks retries.")},
+		{RelativePath: "billing.md", Title: "Billing", Content: []byte("Documentation guide. The documentation covers billing and invoices.")},
+		{RelativePath: "auth.md", Title: "Auth", Content: []byte("Documentation guide. The documentation covers authentication tokens.")},
+	}
+
+	index, err := NewIndexer(t.TempDir()).BuildIndex(docs)
+	if err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	// Ranked within the document alone, documentation would come first
+	got := index.Documents[0].Keywords
+	if len(got) == 0 || got[0] != "webhooks" {
+		t.Errorf("BuildIndex() keywords = %v, want webhooks ranked first", got)
+	}
+}
@@ -0,0 +1,8 @@
+// Package site defines the layout names shared by the packages that write a
+// built site, so that none of them has to import the HTML renderer for them.
+package site
+
+// IndexPage is the home page of a built site. The compiler writes it even
+// when the home page is README.md, so links into a version or locale
+// directory can always point at it.
+const IndexPage = "index.html"
@@ -11,13 +11,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 )
 
 // Builder is responsible for constructing a TableOfContents from a slice of documents.
-type Builder struct {
-	// Configuration options for the builder can be added here in the future.
-}
+type Builder struct{}
 
 // NewBuilder creates and returns a new TOC Builder.
 func NewBuilder() *Builder {
@@ -31,6 +30,13 @@ func (b *Builder) Build(documents []scanner.Document) *TableOfContents {
 	// Sort documents by path for consistent ordering
 	b.sortDocuments(documents)
 
+	// Rank keywords against the whole document set
+	corpus := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		corpus = append(corpus, string(doc.Content))
+	}
+	extractor := keywords.NewExtractor(corpus)
+
 	// Create root node
 	root := &TOCNode{
 		ID:       "root",
@@ -40,7 +46,7 @@ func (b *Builder) Build(documents []scanner.Document) *TableOfContents {
 
 	// Build the tree
 	for _, doc := range documents {
-		b.addDocumentToTree(root, doc)
+		b.addDocumentToTree(root, doc, extractor)
 	}
 
 	// Create TOC with index
@@ -54,8 +60,8 @@ func (b *Builder) Build(documents []scanner.Document) *TableOfContents {
 }
 
 // addDocumentToTree adds a single document to the TOC tree, creating parent
-// directory nodes as needed.
-func (b *Builder) addDocumentToTree(root *TOCNode, doc scanner.Document) {
+// directory nodes as needed. Keywords are ranked by extractor.
+func (b *Builder) addDocumentToTree(root *TOCNode, doc scanner.Document, extractor *keywords.Extractor) {
 	// Split path into parts
 	parts := strings.Split(filepath.ToSlash(doc.RelativePath), "/")
 
@@ -73,7 +79,7 @@ func (b *Builder) addDocumentToTree(root *TOCNode, doc scanner.Document) {
 				ID:       generateNodeID(pathParts),
 				Title:    doc.Title,
 				Path:     doc.RelativePath,
-				Metadata: b.extractMetadata(doc),
+				Metadata: b.extractMetadata(doc, extractor),
 			}
 			currentNode.AddChild(child)
 		} else {
@@ -135,8 +141,8 @@ func humanizeTitle(name string) string {
 }
 
 // extractMetadata processes a document to extract and compute various metadata
-// fields for the corresponding TOC node.
-func (b *Builder) extractMetadata(doc scanner.Document) NodeMetadata {
+// fields for the corresponding TOC node, ranking keywords with extractor.
+func (b *Builder) extractMetadata(doc scanner.Document, extractor *keywords.Extractor) NodeMetadata {
 	content := string(doc.Content)
 
 	// Calculate word count
@@ -164,8 +170,8 @@ func (b *Builder) extractMetadata(doc scanner.Document) NodeMetadata {
 	summary = regexp.MustCompile(`\s+`).ReplaceAllString(summary, " ")
 	summary = strings.TrimSpace(summary)
 
-	// Extract keywords (use simple frequency analysis)
-	keywords := b.extractKeywords(content)
+	// Extract ranked keywords
+	keywords := b.extractKeywords(content, extractor)
 
 	// Extract tags from frontmatter if available
 	var tags []string
@@ -192,73 +198,9 @@ func (b *Builder) extractMetadata(doc scanner.Document) NodeMetadata {
 	}
 }
 
-// extractKeywords performs simple keyword extraction from content.
-func (b *Builder) extractKeywords(content string) []string {
-	// Remove markdown syntax
-	clean := b.cleanContent(content)
-
-	// Split into words
-	words := strings.Fields(clean)
-
-	// Count word frequency
-	wordCount := make(map[string]int)
-	for _, word := range words {
-		word = strings.ToLower(word)
-		word = strings.Trim(word, ".,!?;:'\"")
-
-		// Skip common words and short words
-		if len(word) < 4 || b.isCommonWord(word) {
-			continue
-		}
-
-		wordCount[word]++
-	}
-
-	// Get top keywords (appears at least 2 times)
-	var keywords []string
-	for word, count := range wordCount {
-		if count >= 2 && len(keywords) < 10 { // Limit to 10 keywords
-			keywords = append(keywords, word)
-		}
-	}
-
-	return keywords
-}
-
-// cleanContent removes markdown syntax for accurate keyword extraction and summaries.
-func (b *Builder) cleanContent(content string) string {
-	// Remove code blocks
-	codeBlockRegex := regexp.MustCompile("```[\\s\\S]*?```")
-	content = codeBlockRegex.ReplaceAllString(content, "")
-
-	// Remove inline code
-	inlineCodeRegex := regexp.MustCompile("`[^`]+`")
-	content = inlineCodeRegex.ReplaceAllString(content, "")
-
-	// Remove headers but keep text
-	headerRegex := regexp.MustCompile(`^#{1,6}\s+`)
-	lines := strings.Split(content, "\n")
-	for i, line := range lines {
-		lines[i] = headerRegex.ReplaceAllString(line, "")
-	}
-	content = strings.Join(lines, "\n")
-
-	return content
-}
-
-// isCommonWord checks if a word is a common English stop word.
-func (b *Builder) isCommonWord(word string) bool {
-	commonWords := map[string]bool{
-		"the": true, "and": true, "for": true, "are": true,
-		"with": true, "this": true, "that": true, "from": true,
-		"have": true, "been": true, "were": true, "will": true,
-		"your": true, "their": true, "what": true, "when": true,
-		"where": true, "which": true, "these": true, "those": true,
-		"there": true, "then": true, "than": true, "both": true,
-		"each": true, "some": true, "such": true, "only": true,
-		"very": true, "just": true, "most": true, "also": true,
-		"into": true, "over": true, "after": true, "before": true,
-	}
-
-	return commonWords[word]
+// extractKeywords returns the top TF-IDF ranked keywords and keyphrases for
+// content. An extractor without a corpus ranks terms by frequency within the
+// document alone.
+func (b *Builder) extractKeywords(content string, extractor *keywords.Extractor) []string {
+	return extractor.Extract(content, keywords.DefaultLimit)
 }
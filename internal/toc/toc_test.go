@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/onedusk/jot/internal/keywords"
 	"github.com/onedusk/jot/internal/scanner"
 )
 
@@ -375,7 +376,7 @@ func TestBuilder_ExtractMetadata(t *testing.T) {
 	builder := NewBuilder()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			metadata := builder.extractMetadata(tt.doc)
+			metadata := builder.extractMetadata(tt.doc, keywords.NewExtractor(nil))
 
 			// Check size
 			if metadata.Size < tt.wantMin.Size {
@@ -419,7 +420,7 @@ func TestBuilder_ExtractKeywords(t *testing.T) {
 		"The protocol uses standard procedures. Implementation details are provided below. " +
 		"Protocol specifications must be followed carefully during implementation."
 
-	keywords := builder.extractKeywords(content)
+	keywords := builder.extractKeywords(content, keywords.NewExtractor(nil))
 
 	// Should extract "protocol" and "implementation" as they appear multiple times
 	hasProtocol := false
@@ -484,3 +485,33 @@ func TestTOCWithEnhancedMetadata(t *testing.T) {
 		}
 	}
 }
+
+// TestBuilder_Build_Keywords tests that Build ranks node keywords against the
+// whole document set, so that terms shared by every document rank below the
+// ones that distinguish a page.
+func TestBuilder_Build_Keywords(t *testing.T) {
+	docs := []scanner.Document{
+		{RelativePath: "webhooks.md", Title: "Webhooks", Content: []byte("Documentation guide. The documentation covers webhooks and webhooks retries.")},
+		{RelativePath: "billing.md", Title: "Billing", Content: []byte("Documentation guide. The documentation covers billing and invoices.")},
+		{RelativePath: "auth.md", Title: "Auth", Content: []byte("Documentation guide. The documentation covers authentication tokens.")},
+	}
+
+	toc := NewBuilder().Build(docs)
+
+	var node *TOCNode
+	for _, child := range toc.Root.Children {
+		if child.Path == "webhooks.md" {
+			node = child
+		}
+	}
+	if node == nil {
+		t.Fatal("Build() did not add webhooks.md")
+	}
+	// Ranked within the document alone, documentation would come first
+	if got := node.Metadata.Keywords; len(got) == 0 || got[0] != "webhooks" {
+		t.Errorf("Build() keywords = %v, want webhooks ranked first", got)
+	}
+	if !strings.Contains(toc.ToXML(), "webhooks") {
+		t.Error("ToXML() does not include the node keywords")
+	}
+}
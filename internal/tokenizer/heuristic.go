@@ -0,0 +1,106 @@
+package tokenizer
+
+import (
+	"hash/fnv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HeuristicEncoding is the name of the heuristic tokenizer.
+const HeuristicEncoding = "heuristic"
+
+// maxHeuristicRunes is the longest run of word characters counted as a
+// single token; longer words are split, approximating how BPE encodings break
+// up rare words.
+const maxHeuristicRunes = 6
+
+// HeuristicTokenizer approximates BPE tokenization without any data files.
+// A token is a run of up to six letters or digits, a single CJK character,
+// a single punctuation character, or a run of whitespace. As in BPE
+// encodings, a single leading space is attached to the following token.
+// Counts for English prose run slightly above cl100k_base, erring towards
+// smaller chunks, which makes it a usable fallback for offline environments.
+type HeuristicTokenizer struct{}
+
+// NewHeuristicTokenizer creates a new HeuristicTokenizer.
+func NewHeuristicTokenizer() *HeuristicTokenizer {
+	return &HeuristicTokenizer{}
+}
+
+// Encode returns a token ID for every token, derived from a hash of its text.
+// IDs are stable but do not correspond to any model vocabulary.
+func (h *HeuristicTokenizer) Encode(text string) []int {
+	offsets := h.Offsets(text)
+	ids := make([]int, len(offsets))
+	for i, start := range offsets {
+		end := len(text)
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		hash := fnv.New32a()
+		hash.Write([]byte(text[start:end]))
+		ids[i] = int(hash.Sum32() & 0x7fffffff)
+	}
+	return ids
+}
+
+// Count returns the number of tokens in text.
+func (h *HeuristicTokenizer) Count(text string) int {
+	return len(h.Offsets(text))
+}
+
+// Encoding returns HeuristicEncoding.
+func (h *HeuristicTokenizer) Encoding() string {
+	return HeuristicEncoding
+}
+
+// Offsets returns the byte offset at which each token of text starts.
+func (h *HeuristicTokenizer) Offsets(text string) []int {
+	var offsets []int
+
+	for pos := 0; pos < len(text); {
+		offsets = append(offsets, pos)
+
+		r, size := utf8.DecodeRuneInString(text[pos:])
+
+		// A single space joins the token that follows it
+		if r == ' ' && pos+size < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[pos+size:])
+			if !unicode.IsSpace(next) {
+				pos += size
+				r, size = next, utf8.RuneLen(next)
+			}
+		}
+
+		switch {
+		case isCJK(r):
+			pos += size
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			for n := 0; pos < len(text) && n < maxHeuristicRunes; n++ {
+				r, size = utf8.DecodeRuneInString(text[pos:])
+				if (!unicode.IsLetter(r) && !unicode.IsDigit(r)) || isCJK(r) {
+					break
+				}
+				pos += size
+			}
+		case unicode.IsSpace(r):
+			for pos < len(text) {
+				r, size = utf8.DecodeRuneInString(text[pos:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				pos += size
+			}
+		default:
+			pos += size
+		}
+	}
+
+	return offsets
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character, which
+// BPE encodings typically spend at least one token on each.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,193 @@
+package tokenizer
+
+import (
+	"context"
+	"crypto/sha1"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// DataDirEnv names the environment variable pointing at a directory of
+// <encoding>.tiktoken files.
+const DataDirEnv = "JOT_TOKENIZER_DATA"
+
+// OfflineEnv names the environment variable that, when set to a true value,
+// disables downloading tokenizer data.
+const OfflineEnv = "JOT_OFFLINE"
+
+// downloadTimeout bounds how long a tokenizer data download may take, so an
+// unreachable network fails fast instead of hanging.
+const downloadTimeout = 30 * time.Second
+
+// embeddedData holds any BPE files placed in the data directory at build time.
+//
+//go:embed data
+var embeddedData embed.FS
+
+var (
+	dataDir = os.Getenv(DataDirEnv)
+	offline = isTrue(os.Getenv(OfflineEnv))
+)
+
+func init() {
+	tiktoken.SetBpeLoader(&localLoader{})
+}
+
+// SetDataDir sets the directory searched for <encoding>.tiktoken files. It
+// takes precedence over the JOT_TOKENIZER_DATA environment variable.
+func SetDataDir(dir string) {
+	dataDir = dir
+}
+
+// SetOffline controls whether tokenizer data may be downloaded when it is not
+// embedded or available locally.
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// localLoader implements tiktoken.BpeLoader. It resolves BPE files from, in
+// order: data embedded in the binary, the configured data directory, the
+// tiktoken download cache, and finally the network unless offline mode is on.
+type localLoader struct{}
+
+// LoadTiktokenBpe implements tiktoken.BpeLoader.
+func (l *localLoader) LoadTiktokenBpe(url string) (map[string]int, error) {
+	contents, err := readBpeFile(url)
+	if err != nil {
+		return nil, err
+	}
+	return parseBpe(contents)
+}
+
+// readBpeFile returns the contents of the BPE file published at url.
+func readBpeFile(url string) ([]byte, error) {
+	name := path.Base(url)
+
+	if contents, err := embeddedData.ReadFile("data/" + name); err == nil {
+		return contents, nil
+	}
+
+	if dataDir != "" {
+		contents, err := os.ReadFile(filepath.Join(dataDir, name))
+		if err == nil {
+			return contents, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read tokenizer data: %w", err)
+		}
+	}
+
+	cachePath := filepath.Join(cacheDir(), fmt.Sprintf("%x", sha1.Sum([]byte(url))))
+	if contents, err := os.ReadFile(cachePath); err == nil {
+		return contents, nil
+	}
+
+	if offline {
+		return nil, missingDataError(name, url, nil)
+	}
+
+	contents, err := download(url)
+	if err != nil {
+		return nil, missingDataError(name, url, err)
+	}
+
+	// Cache in tiktoken's layout so later runs, including offline ones, reuse it
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+		tmp := cachePath + ".tmp"
+		if err := os.WriteFile(tmp, contents, 0644); err == nil {
+			os.Rename(tmp, cachePath)
+		}
+	}
+
+	return contents, nil
+}
+
+// download fetches url with a bounded timeout.
+func download(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// missingDataError explains how to make the encoding available offline.
+func missingDataError(name, url string, cause error) error {
+	location := "$" + DataDirEnv + " or llm.tokenizer_data"
+	if dataDir != "" {
+		location = dataDir
+	}
+
+	msg := fmt.Sprintf("tokenizer data %s is not available offline: download %s into %s, or use --tokenizer %s", name, url, location, HeuristicEncoding)
+	if cause != nil {
+		return fmt.Errorf("%s (download failed: %w)", msg, cause)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// cacheDir returns the directory tiktoken-go uses to cache downloads.
+func cacheDir() string {
+	if dir := strings.TrimSpace(os.Getenv("TIKTOKEN_CACHE_DIR")); dir != "" {
+		return dir
+	}
+	if dir := strings.TrimSpace(os.Getenv("DATA_GYM_CACHE_DIR")); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "data-gym-cache")
+}
+
+// parseBpe parses a .tiktoken file: one base64 token and its rank per line.
+func parseBpe(contents []byte) (map[string]int, error) {
+	ranks := make(map[string]int)
+	for i, line := range strings.Split(string(contents), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid tokenizer data on line %d", i+1)
+		}
+		token, err := base64.StdEncoding.DecodeString(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tokenizer data on line %d: %w", i+1, err)
+		}
+		rank, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tokenizer data on line %d: %w", i+1, err)
+		}
+		ranks[string(token)] = rank
+	}
+	return ranks, nil
+}
+
+// isTrue reports whether an environment variable value means enabled.
+func isTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package tokenizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Factory creates a Tokenizer for a registered encoding.
+type Factory func() (Tokenizer, error)
+
+// registry maps encoding names to their factories.
+var registry = map[string]Factory{}
+
+func init() {
+	for _, name := range []string{"cl100k_base", "o200k_base", "p50k_base"} {
+		name := name
+		Register(name, func() (Tokenizer, error) {
+			return NewTikTokenizer(name)
+		})
+	}
+	Register(HeuristicEncoding, func() (Tokenizer, error) {
+		return NewHeuristicTokenizer(), nil
+	})
+}
+
+// Register makes an encoding available under name, replacing any existing
+// registration with the same name.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// New creates a Tokenizer for the named encoding. An empty name selects the
+// default encoding.
+// Returns an error if the encoding is not registered or cannot be initialized.
+func New(name string) (Tokenizer, error) {
+	if name == "" {
+		name = DefaultEncoding()
+	}
+
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tokenizer encoding: %s (supported: %s)", name, strings.Join(AvailableEncodings(), ", "))
+	}
+
+	tok, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s tokenizer: %w", name, err)
+	}
+	return tok, nil
+}
+
+// AvailableEncodings returns the names of all registered encodings, sorted.
+func AvailableEncodings() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultEncoding returns the default encoding name.
+func DefaultEncoding() string {
+	return "cl100k_base"
+}
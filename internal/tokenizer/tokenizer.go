@@ -12,15 +12,26 @@ type Tokenizer interface {
 
 	// Count returns the number of tokens in the given text
 	Count(text string) int
+
+	// Offsets tokenizes text once and returns the byte offset at which each
+	// token starts, so callers can slice text by token ranges without
+	// re-tokenizing substrings. Offsets may fall inside a multi-byte UTF-8
+	// sequence when a token splits a character.
+	Offsets(text string) []int
+
+	// Encoding returns the name of the encoding, recorded in exports so
+	// consumers know how token counts were computed
+	Encoding() string
 }
 
 // TikTokenizer implements the Tokenizer interface using tiktoken-go library
-// with cl100k_base encoding for GPT-4 and Claude compatibility.
+// with a BPE encoding such as cl100k_base (GPT-4 and Claude compatible).
 type TikTokenizer struct {
+	name     string
 	encoding *tiktoken.Tiktoken
 }
 
-// Encode converts text into a sequence of token IDs using cl100k_base encoding.
+// Encode converts text into a sequence of token IDs.
 func (t *TikTokenizer) Encode(text string) []int {
 	return t.encoding.Encode(text, nil, nil)
 }
@@ -30,16 +41,43 @@ func (t *TikTokenizer) Count(text string) int {
 	return len(t.Encode(text))
 }
 
+// Encoding returns the name of the BPE encoding.
+func (t *TikTokenizer) Encoding() string {
+	return t.name
+}
+
+// Offsets returns the byte offset at which each token of text starts.
+func (t *TikTokenizer) Offsets(text string) []int {
+	tokens := t.Encode(text)
+	offsets := make([]int, len(tokens))
+
+	pos := 0
+	for i, token := range tokens {
+		offsets[i] = pos
+		// Decoding a single token yields its raw bytes, which concatenate
+		// back to the original text
+		pos += len(t.encoding.Decode([]int{token}))
+	}
+	return offsets
+}
+
 // NewTokenizer creates a new TikTokenizer instance with cl100k_base encoding.
 // This encoding is compatible with GPT-4, GPT-3.5-turbo, and Claude models.
 // Returns an error if the encoding cannot be initialized.
 func NewTokenizer() (*TikTokenizer, error) {
-	encoding, err := tiktoken.GetEncoding("cl100k_base")
+	return NewTikTokenizer(DefaultEncoding())
+}
+
+// NewTikTokenizer creates a TikTokenizer for the named BPE encoding.
+// Returns an error if the encoding is unknown or its data cannot be loaded.
+func NewTikTokenizer(name string) (*TikTokenizer, error) {
+	encoding, err := tiktoken.GetEncoding(name)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TikTokenizer{
+		name:     name,
 		encoding: encoding,
 	}, nil
 }
@@ -0,0 +1,106 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHeuristicTokenizer tests token boundaries of the heuristic tokenizer.
+func TestHeuristicTokenizer(t *testing.T) {
+	tok := NewHeuristicTokenizer()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"leading space joins word", "hello world", []string{"hello", " world"}},
+		{"long words are split", "documentation", []string{"docume", "ntatio", "n"}},
+		{"punctuation and newlines", "Hi, you.\n\nNext", []string{"Hi", ",", " you", ".", "\n\n", "Next"}},
+		{"multi-byte runes", "naïve 日本", []string{"naïve", " 日", "本"}},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offsets := tok.Offsets(tt.text)
+
+			var got []string
+			for i, start := range offsets {
+				end := len(tt.text)
+				if i+1 < len(offsets) {
+					end = offsets[i+1]
+				}
+				got = append(got, tt.text[start:end])
+			}
+
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("tokens = %q, want %q", got, tt.want)
+			}
+			if tok.Count(tt.text) != len(tt.want) || len(tok.Encode(tt.text)) != len(tt.want) {
+				t.Errorf("Count() = %d, want %d", tok.Count(tt.text), len(tt.want))
+			}
+		})
+	}
+}
+
+// TestNew tests encoding selection through the registry.
+func TestNew(t *testing.T) {
+	tok, err := New(HeuristicEncoding)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", HeuristicEncoding, err)
+	}
+	if tok.Encoding() != HeuristicEncoding {
+		t.Errorf("Encoding() = %q, want %q", tok.Encoding(), HeuristicEncoding)
+	}
+
+	if _, err := New("unknown"); err == nil {
+		t.Error("New(unknown) should fail")
+	}
+
+	for _, name := range []string{"cl100k_base", "o200k_base", "p50k_base", HeuristicEncoding} {
+		found := false
+		for _, available := range AvailableEncodings() {
+			if available == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("AvailableEncodings() is missing %q", name)
+		}
+	}
+}
+
+// TestLocalLoader tests loading BPE data from a local directory and the
+// offline error when it is missing.
+func TestLocalLoader(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TIKTOKEN_CACHE_DIR", t.TempDir())
+	defer SetDataDir(dataDir)
+	defer SetOffline(offline)
+
+	SetDataDir(dir)
+	SetOffline(true)
+
+	url := "https://openaipublic.blob.core.windows.net/encodings/test_base.tiktoken"
+	loader := &localLoader{}
+
+	if _, err := loader.LoadTiktokenBpe(url); err == nil || !strings.Contains(err.Error(), "--tokenizer heuristic") {
+		t.Errorf("LoadTiktokenBpe() error = %v, want offline error suggesting the heuristic tokenizer", err)
+	}
+
+	// "aGVsbG8=" and "IHdvcmxk" are base64 for "hello" and " world"
+	if err := os.WriteFile(filepath.Join(dir, "test_base.tiktoken"), []byte("aGVsbG8= 0\nIHdvcmxk 1\n"), 0644); err != nil {
+		t.Fatalf("failed to write test data: %v", err)
+	}
+
+	ranks, err := loader.LoadTiktokenBpe(url)
+	if err != nil {
+		t.Fatalf("LoadTiktokenBpe() error = %v", err)
+	}
+	if len(ranks) != 2 || ranks["hello"] != 0 || ranks[" world"] != 1 {
+		t.Errorf("LoadTiktokenBpe() = %v, want hello:0 and \" world\":1", ranks)
+	}
+}
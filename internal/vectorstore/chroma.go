@@ -0,0 +1,115 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/onedusk/jot/internal/export"
+)
+
+// chromaCollections is the Chroma v2 API path of the collections of the
+// default tenant and database.
+const chromaCollections = "/api/v2/tenants/default_tenant/databases/default_database/collections"
+
+// ChromaStore pushes chunks to a Chroma collection through its v2 REST API.
+// Records are identified by their chunk ID.
+type ChromaStore struct {
+	client     *client
+	collection string
+	id         string // Collection ID, resolved by EnsureCollection
+	target     export.Target
+}
+
+// newChromaStore creates a ChromaStore for the given collection.
+func newChromaStore(c *client, collection string) *ChromaStore {
+	target, _ := export.NewTarget("chroma")
+	return &ChromaStore{client: c, collection: collection, target: target}
+}
+
+// Name implements the Store interface.
+func (s *ChromaStore) Name() string {
+	return "chroma"
+}
+
+// path returns the API path of the collection followed by suffix.
+func (s *ChromaStore) path(suffix string) string {
+	return chromaCollections + "/" + url.PathEscape(s.id) + suffix
+}
+
+// EnsureCollection implements the Store interface. Chroma infers the
+// dimensions from the first upsert; new collections use cosine distance.
+func (s *ChromaStore) EnsureCollection(ctx context.Context, dimensions int) error {
+	body := map[string]interface{}{
+		"name":          s.collection,
+		"get_or_create": true,
+		"metadata":      map[string]interface{}{"hnsw:space": "cosine"},
+	}
+
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := s.client.do(ctx, http.MethodPost, chromaCollections, body, &resp); err != nil {
+		return err
+	}
+	if resp.ID == "" {
+		return fmt.Errorf("chroma returned no collection ID for %s", s.collection)
+	}
+	s.id = resp.ID
+	return nil
+}
+
+// Upsert implements the Store interface.
+func (s *ChromaStore) Upsert(ctx context.Context, chunks []Chunk) error {
+	body := struct {
+		IDs        []string                 `json:"ids"`
+		Embeddings [][]float32              `json:"embeddings"`
+		Documents  []string                 `json:"documents"`
+		Metadatas  []map[string]interface{} `json:"metadatas"`
+	}{}
+
+	for _, chunk := range chunks {
+		record := s.target.Record(chunk.Metadata, chunk.Frontmatter).(export.ChromaRecord)
+		body.IDs = append(body.IDs, record.ID)
+		body.Embeddings = append(body.Embeddings, record.Embedding)
+		body.Documents = append(body.Documents, record.Document)
+		record.Metadata[SourceKey] = chunk.Source
+		body.Metadatas = append(body.Metadatas, record.Metadata)
+	}
+
+	return s.client.do(ctx, http.MethodPost, s.path("/upsert"), body, nil)
+}
+
+// List implements the Store interface, paging through the record IDs of the
+// source.
+func (s *ChromaStore) List(ctx context.Context, source string) ([]string, error) {
+	const limit = 1000
+	var ids []string
+
+	for offset := 0; ; offset += limit {
+		body := map[string]interface{}{
+			"limit":   limit,
+			"offset":  offset,
+			"include": []string{},
+			"where":   map[string]interface{}{SourceKey: source},
+		}
+
+		var resp struct {
+			IDs []string `json:"ids"`
+		}
+		if err := s.client.do(ctx, http.MethodPost, s.path("/get"), body, &resp); err != nil {
+			return nil, err
+		}
+		ids = append(ids, resp.IDs...)
+
+		if len(resp.IDs) < limit {
+			return ids, nil
+		}
+	}
+}
+
+// Delete implements the Store interface.
+func (s *ChromaStore) Delete(ctx context.Context, chunkIDs []string) error {
+	return s.client.do(ctx, http.MethodPost, s.path("/delete"), map[string]interface{}{"ids": chunkIDs}, nil)
+}
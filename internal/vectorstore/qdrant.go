@@ -0,0 +1,116 @@
+package vectorstore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/onedusk/jot/internal/export"
+)
+
+// QdrantStore pushes chunks to a Qdrant collection through its REST API.
+// Points are identified by export.ChunkUUID and carry the chunk ID in their
+// payload.
+type QdrantStore struct {
+	client     *client
+	collection string
+	target     export.Target
+}
+
+// newQdrantStore creates a QdrantStore for the given collection.
+func newQdrantStore(c *client, collection string) *QdrantStore {
+	target, _ := export.NewTarget("qdrant")
+	return &QdrantStore{client: c, collection: collection, target: target}
+}
+
+// Name implements the Store interface.
+func (q *QdrantStore) Name() string {
+	return "qdrant"
+}
+
+// path returns the API path of the collection followed by suffix.
+func (q *QdrantStore) path(suffix string) string {
+	return "/collections/" + url.PathEscape(q.collection) + suffix
+}
+
+// EnsureCollection implements the Store interface. New collections use
+// cosine distance.
+func (q *QdrantStore) EnsureCollection(ctx context.Context, dimensions int) error {
+	err := q.client.do(ctx, http.MethodGet, q.path(""), nil, nil)
+	if err == nil || !isNotFound(err) {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"vectors": map[string]interface{}{"size": dimensions, "distance": "Cosine"},
+	}
+	return q.client.do(ctx, http.MethodPut, q.path(""), body, nil)
+}
+
+// Upsert implements the Store interface.
+func (q *QdrantStore) Upsert(ctx context.Context, chunks []Chunk) error {
+	points := make([]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		point := q.target.Record(chunk.Metadata, chunk.Frontmatter).(export.QdrantPoint)
+		point.Payload[SourceKey] = chunk.Source
+		points[i] = point
+	}
+	return q.client.do(ctx, http.MethodPut, q.path("/points?wait=true"), map[string]interface{}{"points": points}, nil)
+}
+
+// qdrantScrollResponse is the response body of a scroll request.
+type qdrantScrollResponse struct {
+	Result struct {
+		Points []struct {
+			Payload struct {
+				ChunkID string `json:"chunk_id"`
+			} `json:"payload"`
+		} `json:"points"`
+		NextPageOffset interface{} `json:"next_page_offset"`
+	} `json:"result"`
+}
+
+// List implements the Store interface by scrolling through the points of the
+// source.
+func (q *QdrantStore) List(ctx context.Context, source string) ([]string, error) {
+	var ids []string
+	var offset interface{}
+
+	for {
+		body := map[string]interface{}{
+			"limit":        256,
+			"with_payload": []string{"chunk_id"},
+			"with_vector":  false,
+			"filter": map[string]interface{}{
+				"must": []interface{}{
+					map[string]interface{}{"key": SourceKey, "match": map[string]interface{}{"value": source}},
+				},
+			},
+		}
+		if offset != nil {
+			body["offset"] = offset
+		}
+
+		var resp qdrantScrollResponse
+		if err := q.client.do(ctx, http.MethodPost, q.path("/points/scroll"), body, &resp); err != nil {
+			return nil, err
+		}
+		for _, point := range resp.Result.Points {
+			ids = append(ids, point.Payload.ChunkID)
+		}
+
+		offset = resp.Result.NextPageOffset
+		if offset == nil {
+			return ids, nil
+		}
+	}
+}
+
+// Delete implements the Store interface.
+func (q *QdrantStore) Delete(ctx context.Context, chunkIDs []string) error {
+	points := make([]string, len(chunkIDs))
+	for i, id := range chunkIDs {
+		points[i] = export.ChunkUUID(id)
+	}
+	return q.client.do(ctx, http.MethodPost, q.path("/points/delete?wait=true"), map[string]interface{}{"points": points}, nil)
+}
@@ -0,0 +1,223 @@
+// Package vectorstore pushes exported chunks directly into vector databases
+// over their HTTP APIs: it creates the collection if missing, upserts chunks
+// in batches under stable IDs and prunes chunks that are no longer exported.
+// Pushed records carry a source marker, and pruning only touches records with
+// the same marker, so collections can be shared with other tools and projects.
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/onedusk/jot/internal/export"
+)
+
+// DefaultBatchSize is the number of chunks sent per upsert request.
+const DefaultBatchSize = 100
+
+// SourceKey is the payload field that marks records pushed by Jot with the
+// source they were pushed from.
+const SourceKey = "jot_source"
+
+// DefaultSource is the source marker of pushes that do not set one.
+const DefaultSource = "jot"
+
+// Chunk is an exported chunk together with the frontmatter of its document,
+// which stores keep as metadata.
+type Chunk struct {
+	Metadata    export.ChunkMetadata
+	Frontmatter map[string]interface{}
+	Source      string // Stored under SourceKey; set by Pusher
+}
+
+// Store is a vector database collection that chunks can be pushed to.
+// Chunks are identified by their chunk ID; stores that require another ID
+// format derive it deterministically and keep the chunk ID in the payload.
+type Store interface {
+	// Name identifies the store type, such as "qdrant".
+	Name() string
+
+	// EnsureCollection creates the collection for vectors of the given
+	// dimensions if it does not exist yet.
+	EnsureCollection(ctx context.Context, dimensions int) error
+
+	// Upsert inserts or replaces chunks, storing their source under SourceKey.
+	Upsert(ctx context.Context, chunks []Chunk) error
+
+	// List returns the chunk IDs of the chunks in the collection whose
+	// SourceKey is source. Records of other sources are not returned.
+	List(ctx context.Context, source string) ([]string, error)
+
+	// Delete removes the chunks with the given chunk IDs.
+	Delete(ctx context.Context, chunkIDs []string) error
+}
+
+// Open creates a Store from a URL of the form scheme://host:port/collection.
+// Supported schemes are qdrant, chroma and weaviate; append "+https" to the
+// scheme (for example qdrant+https://) to connect over TLS. The API key may
+// be empty for servers that do not require one.
+func Open(rawURL, apiKey string) (Store, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push URL %q: %w", rawURL, err)
+	}
+
+	scheme, transport := parsed.Scheme, "http"
+	if strings.HasSuffix(scheme, "+https") {
+		scheme, transport = strings.TrimSuffix(scheme, "+https"), "https"
+	}
+
+	collection := strings.Trim(parsed.Path, "/")
+	if parsed.Host == "" || collection == "" || strings.Contains(collection, "/") {
+		return nil, fmt.Errorf("invalid push URL %q: expected %s://host:port/collection", rawURL, scheme)
+	}
+
+	c := newClient(transport+"://"+parsed.Host, apiKey)
+	switch scheme {
+	case "qdrant":
+		return newQdrantStore(c, collection), nil
+	case "chroma":
+		return newChromaStore(c, collection), nil
+	case "weaviate":
+		return newWeaviateStore(c, collection), nil
+	default:
+		return nil, fmt.Errorf("unsupported push target: %s (supported: %s)", scheme, strings.Join(AvailableStores(), ", "))
+	}
+}
+
+// AvailableStores returns the URL schemes accepted by Open.
+func AvailableStores() []string {
+	return []string{"qdrant", "chroma", "weaviate"}
+}
+
+// Result summarizes a push.
+type Result struct {
+	Upserted int // Chunks inserted or replaced
+	Deleted  int // Stale chunks removed
+}
+
+// Pusher streams chunks into a Store in batches. Call Add for every chunk of
+// the export and Finish once all chunks were added.
+type Pusher struct {
+	store     Store
+	batchSize int
+	source    string
+	batch     []Chunk
+	seen      map[string]bool
+	ensured   bool
+	result    Result
+}
+
+// NewPusher creates a Pusher sending batches of at most batchSize chunks.
+// A non-positive batch size selects DefaultBatchSize.
+func NewPusher(store Store, batchSize int) *Pusher {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Pusher{
+		store:     store,
+		batchSize: batchSize,
+		source:    DefaultSource,
+		seen:      make(map[string]bool),
+	}
+}
+
+// SetSource sets the source marker stored with every chunk. Finish only
+// prunes chunks with this marker, so projects sharing a collection must use
+// different sources. An empty source keeps DefaultSource.
+func (p *Pusher) SetSource(source string) {
+	if source != "" {
+		p.source = source
+	}
+}
+
+// Add queues a chunk, sending a batch when it is full. The first chunk
+// determines the vector dimensions of a newly created collection. Every chunk
+// must carry a vector and a chunk ID that is unique within the push.
+func (p *Pusher) Add(ctx context.Context, chunk Chunk) error {
+	id := chunk.Metadata.ChunkID
+	if len(chunk.Metadata.Vector) == 0 {
+		return fmt.Errorf("chunk %s has no vector; pushing requires embeddings", id)
+	}
+	if p.seen[id] {
+		return fmt.Errorf("duplicate chunk ID %s; pushing requires unique chunk IDs", id)
+	}
+	p.seen[id] = true
+
+	if !p.ensured {
+		if err := p.store.EnsureCollection(ctx, len(chunk.Metadata.Vector)); err != nil {
+			return fmt.Errorf("failed to create %s collection: %w", p.store.Name(), err)
+		}
+		p.ensured = true
+	}
+
+	chunk.Source = p.source
+	p.batch = append(p.batch, chunk)
+	if len(p.batch) >= p.batchSize {
+		return p.flush(ctx)
+	}
+	return nil
+}
+
+// Keep marks chunks as still exported without sending them, so that Finish
+// does not delete them. Incremental pushes use it for unchanged chunks.
+func (p *Pusher) Keep(chunkIDs ...string) {
+	for _, id := range chunkIDs {
+		p.seen[id] = true
+	}
+}
+
+// Finish sends the remaining chunks and deletes every chunk of the same
+// source that was not part of this push, such as chunks of removed
+// documents. Records of other sources and records without a chunk ID are
+// left alone.
+func (p *Pusher) Finish(ctx context.Context) (Result, error) {
+	if err := p.flush(ctx); err != nil {
+		return p.result, err
+	}
+	if len(p.seen) == 0 {
+		// Nothing was pushed or kept, so the collection may not exist; leave
+		// it alone rather than deleting everything in it.
+		return p.result, nil
+	}
+
+	existing, err := p.store.List(ctx, p.source)
+	if err != nil {
+		return p.result, fmt.Errorf("failed to list %s chunks: %w", p.store.Name(), err)
+	}
+
+	var stale []string
+	for _, id := range existing {
+		if id != "" && !p.seen[id] {
+			stale = append(stale, id)
+		}
+	}
+
+	for start := 0; start < len(stale); start += p.batchSize {
+		end := start + p.batchSize
+		if end > len(stale) {
+			end = len(stale)
+		}
+		if err := p.store.Delete(ctx, stale[start:end]); err != nil {
+			return p.result, fmt.Errorf("failed to delete stale %s chunks: %w", p.store.Name(), err)
+		}
+		p.result.Deleted += end - start
+	}
+
+	return p.result, nil
+}
+
+// flush upserts the queued chunks.
+func (p *Pusher) flush(ctx context.Context) error {
+	if len(p.batch) == 0 {
+		return nil
+	}
+	if err := p.store.Upsert(ctx, p.batch); err != nil {
+		return fmt.Errorf("failed to upsert to %s: %w", p.store.Name(), err)
+	}
+	p.result.Upserted += len(p.batch)
+	p.batch = p.batch[:0]
+	return nil
+}
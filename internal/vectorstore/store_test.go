@@ -0,0 +1,477 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/onedusk/jot/internal/export"
+)
+
+// fakeCollection is the in-memory state of a fake vector store.
+type fakeCollection struct {
+	mu       sync.Mutex
+	created  int               // Number of times the collection was created
+	upserts  int               // Number of upsert requests
+	points   map[string]string // Native ID -> chunk ID
+	sources  map[string]string // Native ID -> jot_source marker
+	problems []string          // Protocol violations detected by the fake
+}
+
+func newFakeCollection() *fakeCollection {
+	return &fakeCollection{points: make(map[string]string), sources: make(map[string]string)}
+}
+
+// sourceIDs returns the sorted native IDs of the records of source.
+func (f *fakeCollection) sourceIDs(source string) []string {
+	var ids []string
+	for _, id := range f.sortedIDs() {
+		if f.sources[id] == source {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// sortedIDs returns the native IDs in sorted order.
+func (f *fakeCollection) sortedIDs() []string {
+	ids := make([]string, 0, len(f.points))
+	for id := range f.points {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// chunkIDs returns the stored chunk IDs in sorted order.
+func (f *fakeCollection) chunkIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.points))
+	for _, id := range f.points {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func decode(r *http.Request, v interface{}) {
+	json.NewDecoder(r.Body).Decode(v)
+}
+
+func reply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// qdrantHandler implements the Qdrant REST endpoints used by QdrantStore
+// for the collection "docs".
+func qdrantHandler(f *fakeCollection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/collections/docs":
+			if f.created == 0 {
+				http.Error(w, `{"status":{"error":"Not found"}}`, http.StatusNotFound)
+				return
+			}
+			reply(w, map[string]interface{}{"result": map[string]interface{}{}})
+
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs":
+			var body struct {
+				Vectors struct {
+					Size int `json:"size"`
+				} `json:"vectors"`
+			}
+			decode(r, &body)
+			if body.Vectors.Size != 4 {
+				f.problems = append(f.problems, "collection created with size "+strconv.Itoa(body.Vectors.Size))
+			}
+			f.created++
+			reply(w, map[string]interface{}{"result": true})
+
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs/points":
+			var body struct {
+				Points []export.QdrantPoint `json:"points"`
+			}
+			decode(r, &body)
+			f.upserts++
+			for _, point := range body.Points {
+				chunkID, _ := point.Payload["chunk_id"].(string)
+				if point.ID != export.ChunkUUID(chunkID) || len(point.Vector) != 4 {
+					f.problems = append(f.problems, "bad point "+point.ID)
+				}
+				f.points[point.ID] = chunkID
+				f.sources[point.ID], _ = point.Payload[SourceKey].(string)
+			}
+			reply(w, map[string]interface{}{"result": map[string]string{"status": "completed"}})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/scroll":
+			var body struct {
+				Limit  int    `json:"limit"`
+				Offset string `json:"offset"`
+				Filter struct {
+					Must []struct {
+						Key   string `json:"key"`
+						Match struct {
+							Value string `json:"value"`
+						} `json:"match"`
+					} `json:"must"`
+				} `json:"filter"`
+			}
+			decode(r, &body)
+			if len(body.Filter.Must) != 1 || body.Filter.Must[0].Key != SourceKey {
+				f.problems = append(f.problems, "scroll without source filter")
+				return
+			}
+			ids := f.sourceIDs(body.Filter.Must[0].Match.Value)
+			start := sort.SearchStrings(ids, body.Offset)
+			end := start + 2 // small pages to exercise pagination
+			var next interface{}
+			if end < len(ids) {
+				next = ids[end]
+			} else {
+				end = len(ids)
+			}
+			var points []map[string]interface{}
+			for _, id := range ids[start:end] {
+				points = append(points, map[string]interface{}{"id": id, "payload": map[string]string{"chunk_id": f.points[id]}})
+			}
+			reply(w, map[string]interface{}{"result": map[string]interface{}{"points": points, "next_page_offset": next}})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/delete":
+			var body struct {
+				Points []string `json:"points"`
+			}
+			decode(r, &body)
+			for _, id := range body.Points {
+				delete(f.points, id)
+			}
+			reply(w, map[string]interface{}{"result": map[string]string{"status": "completed"}})
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// chromaHandler implements the Chroma v2 endpoints used by ChromaStore for
+// the collection "docs".
+func chromaHandler(f *fakeCollection) http.Handler {
+	const collection = chromaCollections + "/c-1"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Path {
+		case chromaCollections:
+			var body struct {
+				Name        string `json:"name"`
+				GetOrCreate bool   `json:"get_or_create"`
+			}
+			decode(r, &body)
+			if body.Name != "docs" || !body.GetOrCreate {
+				f.problems = append(f.problems, "bad collection request")
+			}
+			f.created++
+			reply(w, map[string]string{"id": "c-1", "name": body.Name})
+
+		case collection + "/upsert":
+			var body struct {
+				IDs        []string                 `json:"ids"`
+				Embeddings [][]float32              `json:"embeddings"`
+				Documents  []string                 `json:"documents"`
+				Metadatas  []map[string]interface{} `json:"metadatas"`
+			}
+			decode(r, &body)
+			f.upserts++
+			if len(body.Embeddings) != len(body.IDs) || len(body.Documents) != len(body.IDs) || len(body.Metadatas) != len(body.IDs) {
+				f.problems = append(f.problems, "upsert arrays differ in length")
+			}
+			for i, id := range body.IDs {
+				if _, ok := body.Metadatas[i]["heading_path"].(string); !ok {
+					f.problems = append(f.problems, "non-scalar heading path for "+id)
+				}
+				f.points[id] = id
+				f.sources[id], _ = body.Metadatas[i][SourceKey].(string)
+			}
+			reply(w, map[string]interface{}{})
+
+		case collection + "/get":
+			var body struct {
+				Limit  int               `json:"limit"`
+				Offset int               `json:"offset"`
+				Where  map[string]string `json:"where"`
+			}
+			decode(r, &body)
+			source, ok := body.Where[SourceKey]
+			if !ok {
+				f.problems = append(f.problems, "get without source filter")
+			}
+			ids := f.sourceIDs(source)
+			if body.Offset > len(ids) {
+				body.Offset = len(ids)
+			}
+			end := body.Offset + body.Limit
+			if end > len(ids) {
+				end = len(ids)
+			}
+			reply(w, map[string]interface{}{"ids": ids[body.Offset:end]})
+
+		case collection + "/delete":
+			var body struct {
+				IDs []string `json:"ids"`
+			}
+			decode(r, &body)
+			for _, id := range body.IDs {
+				delete(f.points, id)
+			}
+			reply(w, map[string]interface{}{})
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// weaviateHandler implements the Weaviate endpoints used by WeaviateStore
+// for the class "Docs".
+func weaviateHandler(f *fakeCollection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/schema/Docs":
+			if f.created == 0 {
+				http.NotFound(w, r)
+				return
+			}
+			reply(w, map[string]string{"class": "Docs"})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/schema":
+			var body struct {
+				Class      string `json:"class"`
+				Vectorizer string `json:"vectorizer"`
+			}
+			decode(r, &body)
+			if body.Class != "Docs" || body.Vectorizer != "none" {
+				f.problems = append(f.problems, "bad class "+body.Class)
+			}
+			f.created++
+			reply(w, body)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/batch/objects":
+			var body struct {
+				Objects []export.WeaviateObject `json:"objects"`
+			}
+			decode(r, &body)
+			f.upserts++
+			var results []map[string]interface{}
+			for _, object := range body.Objects {
+				chunkID, _ := object.Properties["chunk_id"].(string)
+				if object.Class != "Docs" || object.ID != export.ChunkUUID(chunkID) {
+					f.problems = append(f.problems, "bad object "+object.ID)
+				}
+				f.points[object.ID] = chunkID
+				f.sources[object.ID], _ = object.Properties[SourceKey].(string)
+				results = append(results, map[string]interface{}{"id": object.ID, "result": map[string]interface{}{}})
+			}
+			reply(w, results)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/objects":
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			ids := f.sortedIDs()
+			start := 0
+			if after := r.URL.Query().Get("after"); after != "" {
+				start = sort.SearchStrings(ids, after) + 1
+			}
+			end := start + limit
+			if end > len(ids) {
+				end = len(ids)
+			}
+			var objects []map[string]interface{}
+			for _, id := range ids[start:end] {
+				objects = append(objects, map[string]interface{}{"id": id, "properties": map[string]string{"chunk_id": f.points[id], SourceKey: f.sources[id]}})
+			}
+			reply(w, map[string]interface{}{"objects": objects})
+
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/objects/Docs/"):
+			id := strings.TrimPrefix(r.URL.Path, "/v1/objects/Docs/")
+			if _, ok := f.points[id]; !ok {
+				http.NotFound(w, r)
+				return
+			}
+			delete(f.points, id)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// testChunks returns chunks of the given documents, two per document.
+func testChunks(docs ...string) []Chunk {
+	var chunks []Chunk
+	for _, doc := range docs {
+		for i := 0; i < 2; i++ {
+			id := doc + "-chunk-" + strconv.Itoa(i)
+			chunks = append(chunks, Chunk{
+				Metadata: export.ChunkMetadata{
+					DocID:       doc,
+					ChunkID:     id,
+					Text:        "Text of " + id,
+					Source:      doc + ".md",
+					Vector:      []float32{1, 0, 0, float32(i)},
+					HeadingPath: []string{"Guide", doc},
+				},
+				Frontmatter: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			})
+		}
+	}
+	return chunks
+}
+
+// push pushes chunks to the store in batches of 3.
+func push(t *testing.T, store Store, chunks []Chunk) Result {
+	t.Helper()
+	pusher := NewPusher(store, 3)
+	for _, chunk := range chunks {
+		if err := pusher.Add(context.Background(), chunk); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	result, err := pusher.Finish(context.Background())
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	return result
+}
+
+// TestPush tests creating, upserting and pruning against fake stores, and
+// that pruning keeps records of other sources.
+func TestPush(t *testing.T) {
+	tests := []struct {
+		scheme  string
+		handler func(*fakeCollection) http.Handler
+	}{
+		{"qdrant", qdrantHandler},
+		{"chroma", chromaHandler},
+		{"weaviate", weaviateHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			fake := newFakeCollection()
+			server := httptest.NewServer(tt.handler(fake))
+			defer server.Close()
+
+			store, err := Open(tt.scheme+"://"+strings.TrimPrefix(server.URL, "http://")+"/docs", "")
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			// Records of another tool (no chunk ID, no source) and of another
+			// project sharing the collection
+			fake.points["foreign-1"] = ""
+			fake.points["foreign-2"] = "other-chunk-0"
+			fake.sources["foreign-2"] = "other"
+
+			// First push: three documents, six chunks in two batches
+			result := push(t, store, testChunks("a", "b", "c"))
+			if result.Upserted != 6 || result.Deleted != 0 {
+				t.Errorf("first push = %+v, want 6 upserted, 0 deleted", result)
+			}
+			if fake.upserts != 2 {
+				t.Errorf("upsert requests = %d, want 2", fake.upserts)
+			}
+
+			// Second push: document b disappeared
+			result = push(t, store, testChunks("a", "c"))
+			if result.Upserted != 4 || result.Deleted != 2 {
+				t.Errorf("second push = %+v, want 4 upserted, 2 deleted", result)
+			}
+
+			want := []string{"", "a-chunk-0", "a-chunk-1", "c-chunk-0", "c-chunk-1", "other-chunk-0"}
+			if got := fake.chunkIDs(); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("stored chunks = %v, want %v", got, want)
+			}
+			if tt.scheme != "chroma" && fake.created != 1 {
+				t.Errorf("collection created %d times, want 1", fake.created)
+			}
+			if len(fake.problems) > 0 {
+				t.Errorf("protocol problems: %v", fake.problems)
+			}
+		})
+	}
+}
+
+// TestPusher_Errors tests that invalid chunks are rejected before anything is sent.
+func TestPusher_Errors(t *testing.T) {
+	fake := newFakeCollection()
+	server := httptest.NewServer(qdrantHandler(fake))
+	defer server.Close()
+
+	store, err := Open("qdrant://"+strings.TrimPrefix(server.URL, "http://")+"/docs", "")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	chunk := testChunks("a")[0]
+	noVector := chunk
+	noVector.Metadata.Vector = nil
+
+	pusher := NewPusher(store, 10)
+	if err := pusher.Add(context.Background(), noVector); err == nil || !strings.Contains(err.Error(), "requires embeddings") {
+		t.Errorf("Add() error = %v, want missing vector error", err)
+	}
+	if err := pusher.Add(context.Background(), chunk); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := pusher.Add(context.Background(), chunk); err == nil || !strings.Contains(err.Error(), "duplicate chunk ID") {
+		t.Errorf("Add() error = %v, want duplicate ID error", err)
+	}
+}
+
+// TestOpen tests push URL parsing.
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		url     string
+		name    string
+		wantErr bool
+	}{
+		{"qdrant://localhost:6333/docs", "qdrant", false},
+		{"qdrant+https://cloud.example.com/docs", "qdrant", false},
+		{"chroma://localhost:8000/docs", "chroma", false},
+		{"weaviate://localhost:8080/docs", "weaviate", false},
+		{"pinecone://host/docs", "", true},
+		{"qdrant://localhost:6333", "", true},
+		{"qdrant://localhost:6333/a/b", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			store, err := Open(tt.url, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && store.Name() != tt.name {
+				t.Errorf("Open() store = %s, want %s", store.Name(), tt.name)
+			}
+		})
+	}
+}
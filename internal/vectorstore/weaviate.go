@@ -0,0 +1,144 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/onedusk/jot/internal/export"
+)
+
+// WeaviateStore pushes chunks to a Weaviate class through its REST API.
+// Objects are identified by export.ChunkUUID and carry the chunk ID as a
+// property.
+type WeaviateStore struct {
+	client *client
+	class  string
+	target export.Target
+}
+
+// newWeaviateStore creates a WeaviateStore for the given class. Weaviate
+// class names start with an upper-case letter, so the first letter is
+// capitalized.
+func newWeaviateStore(c *client, class string) *WeaviateStore {
+	r, size := utf8.DecodeRuneInString(class)
+	class = string(unicode.ToUpper(r)) + class[size:]
+	return &WeaviateStore{client: c, class: class, target: export.NewWeaviateTarget(class)}
+}
+
+// Name implements the Store interface.
+func (w *WeaviateStore) Name() string {
+	return "weaviate"
+}
+
+// EnsureCollection implements the Store interface. New classes store
+// vectors supplied by Jot (no vectorizer) and use cosine distance.
+func (w *WeaviateStore) EnsureCollection(ctx context.Context, dimensions int) error {
+	err := w.client.do(ctx, http.MethodGet, "/v1/schema/"+url.PathEscape(w.class), nil, nil)
+	if err == nil || !isNotFound(err) {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"class":             w.class,
+		"vectorizer":        "none",
+		"vectorIndexConfig": map[string]interface{}{"distance": "cosine"},
+	}
+	return w.client.do(ctx, http.MethodPost, "/v1/schema", body, nil)
+}
+
+// weaviateBatchResult is one element of a batch objects response.
+type weaviateBatchResult struct {
+	ID     string `json:"id"`
+	Result struct {
+		Errors *struct {
+			Error []struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"errors"`
+	} `json:"result"`
+}
+
+// Upsert implements the Store interface. Batch requests replace objects with
+// existing IDs; per-object errors are reported as a single error.
+func (w *WeaviateStore) Upsert(ctx context.Context, chunks []Chunk) error {
+	objects := make([]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		object := w.target.Record(chunk.Metadata, chunk.Frontmatter).(export.WeaviateObject)
+		object.Properties[SourceKey] = chunk.Source
+		objects[i] = object
+	}
+
+	var results []weaviateBatchResult
+	if err := w.client.do(ctx, http.MethodPost, "/v1/batch/objects", map[string]interface{}{"objects": objects}, &results); err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, result := range results {
+		if result.Result.Errors == nil {
+			continue
+		}
+		for _, e := range result.Result.Errors.Error {
+			failures = append(failures, result.ID+": "+e.Message)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d objects failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// List implements the Store interface, paging through all objects of the
+// class and keeping those of the source.
+func (w *WeaviateStore) List(ctx context.Context, source string) ([]string, error) {
+	const limit = 100
+	var ids []string
+	after := ""
+
+	for {
+		query := url.Values{"class": {w.class}, "limit": {fmt.Sprint(limit)}}
+		if after != "" {
+			query.Set("after", after)
+		}
+
+		var resp struct {
+			Objects []struct {
+				ID         string `json:"id"`
+				Properties struct {
+					ChunkID string `json:"chunk_id"`
+					Source  string `json:"jot_source"`
+				} `json:"properties"`
+			} `json:"objects"`
+		}
+		if err := w.client.do(ctx, http.MethodGet, "/v1/objects?"+query.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		for _, object := range resp.Objects {
+			if object.Properties.Source == source {
+				ids = append(ids, object.Properties.ChunkID)
+			}
+		}
+
+		if len(resp.Objects) < limit {
+			return ids, nil
+		}
+		after = resp.Objects[len(resp.Objects)-1].ID
+	}
+}
+
+// Delete implements the Store interface. Objects that no longer exist are
+// ignored.
+func (w *WeaviateStore) Delete(ctx context.Context, chunkIDs []string) error {
+	for _, id := range chunkIDs {
+		path := "/v1/objects/" + url.PathEscape(w.class) + "/" + export.ChunkUUID(id)
+		if err := w.client.do(ctx, http.MethodDelete, path, nil, nil); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
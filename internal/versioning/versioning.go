@@ -0,0 +1,258 @@
+// Package versioning resolves the versions of the documentation that are
+// built side by side into one site, such as /v1/ and /v2/, from version
+// directories (docs/v1, docs/v2) or from git tags and branches.
+package versioning
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onedusk/jot/internal/site"
+)
+
+// LatestDir is the output directory that holds a copy of the latest version.
+const LatestDir = "latest"
+
+// versionDirRegex matches directory names that look like versions: v1, v2.1,
+// 1.0 or 2.x.
+var versionDirRegex = regexp.MustCompile(`^v?\d+(\.(\d+|x))*$`)
+
+// Version is one version of the documentation.
+type Version struct {
+	Name  string `mapstructure:"name"`  // Output directory and URL segment, e.g. "v2"
+	Label string `mapstructure:"label"` // Shown in the version switcher; defaults to Name
+	Path  string `mapstructure:"path"`  // Directory holding the version's markdown
+	Ref   string `mapstructure:"ref"`   // Git tag or branch to read Path from; empty for the working tree
+}
+
+// DisplayName returns the label of the version, or its name when it has none.
+func (v Version) DisplayName() string {
+	if v.Label != "" {
+		return v.Label
+	}
+	return v.Name
+}
+
+// Detect returns the version directories directly inside the given input
+// paths, newest first. A directory is a version when its name looks like
+// one (v1, v2.1, 1.0, 2.x). Input paths that are files are ignored.
+func Detect(inputPaths []string) ([]Version, error) {
+	var versions []Version
+	for _, inputPath := range inputPaths {
+		info, err := os.Stat(inputPath)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", inputPath, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && versionDirRegex.MatchString(entry.Name()) {
+				versions = append(versions, Version{Name: entry.Name(), Path: filepath.Join(inputPath, entry.Name())})
+			}
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i].Name, versions[j].Name) > 0
+	})
+	return versions, nil
+}
+
+// Validate checks that versions have unique names usable as directory names
+// and a source to read from.
+func Validate(versions []Version) error {
+	seen := make(map[string]bool)
+	for _, version := range versions {
+		switch {
+		case version.Name == "":
+			return fmt.Errorf("version without a name")
+		case version.Name == LatestDir:
+			return fmt.Errorf("version name %q is reserved for the copy of the latest version", LatestDir)
+		case strings.ContainsAny(version.Name, `/\`) || version.Name == "." || version.Name == "..":
+			return fmt.Errorf("invalid version name: %s", version.Name)
+		case seen[version.Name]:
+			return fmt.Errorf("duplicate version: %s", version.Name)
+		case version.Path == "" && version.Ref == "":
+			return fmt.Errorf("version %s needs a path or a ref", version.Name)
+		}
+		seen[version.Name] = true
+	}
+	return nil
+}
+
+// Latest returns the version with the given name, or the first version when
+// name is empty.
+func Latest(versions []Version, name string) (Version, error) {
+	if len(versions) == 0 {
+		return Version{}, fmt.Errorf("no versions")
+	}
+	if name == "" {
+		return versions[0], nil
+	}
+	for _, version := range versions {
+		if version.Name == name {
+			return version, nil
+		}
+	}
+	return Version{}, fmt.Errorf("latest version %s is not a configured version", name)
+}
+
+// Compare orders version names numerically component by component, so that
+// v10 sorts after v9 and 2.x after 2.1. It returns a negative number when a
+// is older than b, a positive number when it is newer, and 0 when they are
+// equal. Names that are not versions compare as strings, below versions.
+func Compare(a, b string) int {
+	partsA, okA := versionParts(a)
+	partsB, okB := versionParts(b)
+	switch {
+	case okA && !okB:
+		return 1
+	case !okA && okB:
+		return -1
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var partA, partB int
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+		if partA != partB {
+			if partA < partB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version name into its numeric components; "x"
+// counts as higher than any number.
+func versionParts(name string) ([]int, bool) {
+	if !versionDirRegex.MatchString(name) {
+		return nil, false
+	}
+	var parts []int
+	for _, part := range strings.Split(strings.TrimPrefix(name, "v"), ".") {
+		if part == "x" {
+			parts = append(parts, int(^uint(0)>>1))
+			continue
+		}
+		number, _ := strconv.Atoi(part)
+		parts = append(parts, number)
+	}
+	return parts, true
+}
+
+// Checkout extracts the markdown files under path (relative to the current
+// directory) as of the git ref into dest, using the git binary, and returns
+// the directory inside dest that corresponds to path.
+func Checkout(ref, path, dest string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "archive", "--format=tar", ref+":./"+filepath.ToSlash(filepath.Clean(path)))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %s: %w", path, ref, strings.TrimSpace(stderr.String()), err)
+	}
+
+	dir := filepath.Join(dest, filepath.Clean(path))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	archive := tar.NewReader(&stdout)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive of %s: %w", ref, err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(strings.ToLower(header.Name), ".md") {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s at %s: %w", header.Name, ref, err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+
+	return dir, nil
+}
+
+// siteIndex is a versions.json entry.
+type siteIndex struct {
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	Path   string `json:"path"` // Directory of the version relative to the site root, with a trailing slash
+	Latest bool   `json:"latest,omitempty"`
+}
+
+// redirectPage is the site root index.html of a versioned site. It points at
+// the index page the compiler writes into every version directory.
+const redirectPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta http-equiv="refresh" content="0; url=` + LatestDir + `/` + site.IndexPage + `">
+    <link rel="canonical" href="` + LatestDir + `/">
+    <title>Documentation</title>
+</head>
+<body>
+    <p><a href="` + LatestDir + `/` + site.IndexPage + `">Go to the latest documentation</a></p>
+</body>
+</html>
+`
+
+// WriteIndex writes the root of a versioned site to outputPath: an
+// index.html redirecting to the latest version and a versions.json listing
+// every version and its directory, for tools and custom version switchers.
+func WriteIndex(outputPath string, versions []Version, latest string) error {
+	if err := os.WriteFile(filepath.Join(outputPath, "index.html"), []byte(redirectPage), 0644); err != nil {
+		return fmt.Errorf("failed to write index.html: %w", err)
+	}
+
+	entries := make([]siteIndex, 0, len(versions)+1)
+	for _, version := range versions {
+		entries = append(entries, siteIndex{Name: version.Name, Label: version.DisplayName(), Path: version.Name + "/", Latest: version.Name == latest})
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal versions.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputPath, "versions.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write versions.json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package versioning
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDetect tests detection of version directories, newest first.
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"v1", "v10", "v2.1", "2.x", "guide", "v2-beta"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "v3"), []byte("# Not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := Detect([]string{root, filepath.Join(root, "missing")})
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	var names []string
+	for _, version := range versions {
+		names = append(names, version.Name)
+		if version.Path != filepath.Join(root, version.Name) {
+			t.Errorf("Detect() path of %s = %s", version.Name, version.Path)
+		}
+	}
+	if want := []string{"v10", "2.x", "v2.1", "v1"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Detect() = %v, want %v", names, want)
+	}
+}
+
+// TestCompare tests the numeric ordering of version names.
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v2", "v10", -1},
+		{"v1.10", "v1.9", 1},
+		{"v1.0", "v1", 0},
+		{"1.x", "1.99", 1},
+		{"v1", "next", 1},
+		{"alpha", "beta", -1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestValidate tests the rejection of unusable version configurations.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []Version
+		wantErr  string
+	}{
+		{"valid", []Version{{Name: "v2", Path: "docs"}, {Name: "v1", Ref: "v1.0.0"}}, ""},
+		{"missing name", []Version{{Path: "docs"}}, "without a name"},
+		{"reserved name", []Version{{Name: "latest", Path: "docs"}}, "reserved"},
+		{"nested name", []Version{{Name: "v1/old", Path: "docs"}}, "invalid version name"},
+		{"duplicate", []Version{{Name: "v1", Path: "a"}, {Name: "v1", Path: "b"}}, "duplicate"},
+		{"no source", []Version{{Name: "v1"}}, "needs a path or a ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.versions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCheckout tests reading the markdown of a directory at a git tag.
+func TestCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Jot", "-c", "user.email=jot@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("docs/guide/install.md", "# Install v1")
+	write("docs/logo.png", "not markdown")
+	git("add", ".")
+	git("commit", "-q", "-m", "v1")
+	git("tag", "v1.0.0")
+	write("docs/guide/install.md", "# Install v2")
+	git("commit", "-q", "-am", "v2")
+
+	// Checkout reads paths relative to the current directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	dest := t.TempDir()
+	dir, err := Checkout("v1.0.0", "docs", dest)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if dir != filepath.Join(dest, "docs") {
+		t.Errorf("Checkout() dir = %s, want %s", dir, filepath.Join(dest, "docs"))
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "guide", "install.md"))
+	if err != nil || string(content) != "# Install v1" {
+		t.Errorf("Checkout() install.md = %q, %v; want the tagged content", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "logo.png")); !os.IsNotExist(err) {
+		t.Errorf("Checkout() extracted a file that is not markdown")
+	}
+
+	if _, err := Checkout("v9.9.9", "docs", dest); err == nil {
+		t.Error("Checkout() of an unknown ref succeeded")
+	}
+}
+
+// TestWriteIndex tests the redirect page and versions.json of a versioned
+// site.
+func TestWriteIndex(t *testing.T) {
+	dir := t.TempDir()
+	versions := []Version{{Name: "v2", Label: "2.x"}, {Name: "v1"}}
+	if err := WriteIndex(dir, versions, "v2"); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil || !strings.Contains(string(page), `url=latest/index.html`) {
+		t.Errorf("WriteIndex() index.html = %s, %v; want a redirect to latest/", page, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "versions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []siteIndex
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("invalid versions.json: %v", err)
+	}
+	want := []siteIndex{{Name: "v2", Label: "2.x", Path: "v2/", Latest: true}, {Name: "v1", Label: "v1", Path: "v1/"}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("versions.json = %+v, want %+v", entries, want)
+	}
+}